@@ -0,0 +1,61 @@
+package main
+
+import (
+	"maps"
+	"slices"
+
+	"github.com/alecthomas/kong"
+
+	"github.com/sushichan044/memo-cli/internal/alias"
+	"github.com/sushichan044/memo-cli/schema"
+)
+
+type (
+	AliasCmd struct {
+		List AliasListCmd `cmd:"" default:"withargs" help:"List the aliases of the [alias] section of the config file."`
+	}
+
+	AliasListCmd struct{}
+)
+
+func (c *AliasListCmd) Run(ctx *CLIContext, kctx *kong.Context) error {
+	names := slices.Sorted(maps.Keys(ctx.cfg.Aliases))
+	width := 0
+	for _, name := range names {
+		width = max(width, len(name))
+	}
+
+	for _, name := range names {
+		item := schema.Alias{
+			Name:     name,
+			Command:  ctx.cfg.Aliases[name],
+			Shadowed: builtinCommand(kctx.Model, name),
+		}
+		if ctx.out.JSON() {
+			if err := ctx.out.Emit(item); err != nil {
+				return err
+			}
+			continue
+		}
+		note := ""
+		if item.Shadowed {
+			note = "  (shadowed by the built-in command)"
+		}
+		ctx.out.Printf("%-*s = %s%s\n", width, item.Name, item.Command, note)
+	}
+	return nil
+}
+
+// expandAlias replaces the command name in args with the arguments of its alias, if any.
+// Built-in commands always win over aliases, while aliases win over plugins and may expand to one.
+func expandAlias(app *kong.Application, aliases map[string]string, args []string) ([]string, error) {
+	i, ok := commandIndex(args)
+	if !ok {
+		return args, nil
+	}
+	words, aliased, err := alias.Expand(args[i], aliases, func(name string) bool { return builtinCommand(app, name) })
+	if err != nil || !aliased {
+		return args, err
+	}
+	return slices.Concat(args[:i], words, args[i+1:]), nil
+}
@@ -0,0 +1,48 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/sushichan044/memo-cli/internal/crypt"
+	"github.com/sushichan044/memo-cli/internal/hook"
+	"github.com/sushichan044/memo-cli/internal/memo"
+)
+
+type AppendCmd struct {
+	Ref  string   `arg:"" help:"Memo to append to: @latest, a path, or a memo name" predictor:"ref"`
+	Text []string `arg:"" help:"Text to append as a new line (default: read from stdin)" optional:""`
+}
+
+func (c *AppendCmd) Run(ctx *CLIContext) error {
+	entry, err := memo.Resolve(ctx.fs, c.Ref)
+	if err != nil {
+		return err
+	}
+
+	var text []byte
+	if len(c.Text) > 0 {
+		text = []byte(strings.Join(c.Text, " ") + "\n")
+	} else {
+		text, err = io.ReadAll(os.Stdin)
+		if err != nil {
+			return fmt.Errorf("failed to read stdin: %w", err)
+		}
+	}
+
+	if err = memo.Append(ctx.fs, crypt.NewKeyring(ctx.cfg.Encryption), entry, text, ctx.cfg.FilePerm()); err != nil {
+		return err
+	}
+	_ = ctx.hooks().Run(hook.PostEdit, entry.Schema())
+	ctx.record("append %s", entry.RelPath)
+
+	if ctx.out.JSON() {
+		return ctx.out.Emit(entry.Schema())
+	}
+
+	ctx.out.Infof("✅ Appended to: %s\n", entry.Path)
+
+	return nil
+}
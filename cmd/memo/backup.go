@@ -0,0 +1,189 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/sushichan044/memo-cli/internal/backup"
+	"github.com/sushichan044/memo-cli/internal/history"
+	"github.com/sushichan044/memo-cli/schema"
+)
+
+type (
+	BackupCmd struct {
+		Create BackupCreateCmd `cmd:"" default:"withargs" help:"Write a timestamped archive of the memo directory."`
+		Verify BackupVerifyCmd `cmd:"verify"              help:"Check an archive against its manifest."`
+	}
+
+	BackupCreateCmd struct {
+		Dest string `help:"Directory to write the archive to (default: backup.dir in the config file, or $XDG_DATA_HOME/memo/backups)" short:"o" type:"path"`
+	}
+
+	BackupVerifyCmd struct {
+		Archive string `arg:"" help:"Archive to verify" type:"existingfile"`
+	}
+
+	RestoreCmd struct {
+		Archive string `arg:"" help:"Archive written by memo backup"                     type:"existingfile"`
+		Force   bool   `       help:"Overwrite memos whose content differs from the archive"`
+		DryRun  bool   `       help:"Show what would be restored without writing anything"  name:"dry-run"`
+	}
+)
+
+func (c *BackupCreateCmd) Run(ctx *CLIContext) error {
+	dir := c.Dest
+	if dir == "" {
+		var err error
+		if dir, err = ctx.cfg.BackupPath(); err != nil {
+			return err
+		}
+	}
+
+	// Archives hold every memo, so they get the same permissions as the memo directory.
+	if err := os.MkdirAll(dir, ctx.cfg.DirPerm()); err != nil {
+		return fmt.Errorf("failed to create backup directory: %w", err)
+	}
+
+	path := filepath.Join(dir, backup.FileName(time.Now()))
+	file, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_EXCL, ctx.cfg.FilePerm())
+	if err != nil {
+		return fmt.Errorf("failed to create archive: %w", err)
+	}
+
+	// The history repository is not backed up: restoring its internals file by file could corrupt a live one.
+	manifest, err := backup.Write(ctx.fs, file, time.Now(), history.Internal)
+	if closeErr := file.Close(); err == nil && closeErr != nil {
+		err = fmt.Errorf("failed to write archive: %w", closeErr)
+	}
+	if err != nil {
+		_ = os.Remove(path)
+		return err
+	}
+
+	var total int64
+	for _, f := range manifest.Files {
+		total += f.Size
+	}
+
+	if ctx.out.JSON() {
+		return ctx.out.Emit(schema.Backup{Path: path, Files: len(manifest.Files), Bytes: total})
+	}
+
+	ctx.out.Infof("✅ Backed up %d file(s) to: %s\n", len(manifest.Files), path)
+	ctx.out.Println(path)
+
+	return nil
+}
+
+func (c *BackupVerifyCmd) Run(ctx *CLIContext) error {
+	archive, err := readArchive(c.Archive)
+	if err != nil {
+		return err
+	}
+
+	problems := archive.Verify()
+
+	if ctx.out.JSON() {
+		doc := schema.Verified{
+			Path:      c.Archive,
+			CreatedAt: archive.Manifest.CreatedAt,
+			Files:     len(archive.Manifest.Files),
+			Problems:  make([]string, 0, len(problems)),
+		}
+		for _, p := range problems {
+			doc.Problems = append(doc.Problems, p.String())
+		}
+		if emitErr := ctx.out.Emit(doc); emitErr != nil {
+			return emitErr
+		}
+	} else {
+		for _, p := range problems {
+			ctx.out.Infof("  %s\n", p)
+		}
+	}
+
+	if len(problems) > 0 {
+		return fmt.Errorf("%s failed verification with %d problem(s)", c.Archive, len(problems))
+	}
+
+	ctx.out.Infof("✅ Archive is intact: %d file(s) backed up at %s\n",
+		len(archive.Manifest.Files), archive.Manifest.CreatedAt.Local().Format(time.DateTime))
+
+	return nil
+}
+
+func (c *RestoreCmd) Run(ctx *CLIContext) error {
+	archive, err := readArchive(c.Archive)
+	if err != nil {
+		return err
+	}
+
+	opts := backup.RestoreOptions{
+		FilePerm: ctx.cfg.FilePerm(),
+		DirPerm:  ctx.cfg.DirPerm(),
+		Force:    c.Force,
+		DryRun:   c.DryRun,
+		// Keep the history repository as it is, even for archives taken before it was left out of backups,
+		// and record the restore in it rather than rewinding it.
+		Skip: history.Internal,
+	}
+
+	result, err := archive.Restore(ctx.fs, opts)
+	if err != nil && !errors.Is(err, backup.ErrConflict) {
+		return err
+	}
+	if err == nil && !c.DryRun && len(result.Restored)+len(result.Conflicts) > 0 {
+		ctx.record("restore %s", filepath.Base(c.Archive))
+	}
+
+	if ctx.out.JSON() {
+		doc := schema.Restored{
+			Restored:  nonNil(result.Restored),
+			Unchanged: nonNil(result.Unchanged),
+			Conflicts: nonNil(result.Conflicts),
+			DryRun:    c.DryRun,
+			Aborted:   err != nil,
+		}
+		if emitErr := ctx.out.Emit(doc); emitErr != nil {
+			return emitErr
+		}
+		return err
+	}
+
+	for _, name := range result.Conflicts {
+		ctx.out.Infof("  conflict  %s\n", name)
+	}
+	if err != nil {
+		return err
+	}
+
+	verb := "Restored"
+	if c.DryRun {
+		verb = "Would restore"
+	}
+	ctx.out.Infof("✅ %s %d missing and %d changed file(s); %d already up to date\n",
+		verb, len(result.Restored), len(result.Conflicts), len(result.Unchanged))
+
+	return nil
+}
+
+func readArchive(path string) (*backup.Archive, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open archive: %w", err)
+	}
+	defer file.Close()
+
+	return backup.Read(file)
+}
+
+// nonNil keeps empty lists as [] rather than null in JSON output.
+func nonNil(s []string) []string {
+	if s == nil {
+		return []string{}
+	}
+	return s
+}
@@ -0,0 +1,140 @@
+package main
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+
+	"github.com/alecthomas/kong"
+
+	"github.com/sushichan044/memo-cli/internal/complete"
+	"github.com/sushichan044/memo-cli/internal/markdown"
+	"github.com/sushichan044/memo-cli/internal/memo"
+	"github.com/sushichan044/memo-cli/internal/plugin"
+)
+
+type (
+	CompletionCmd struct {
+		Shell string `arg:"" help:"Shell to complete memo in" enum:"bash,zsh,fish"`
+	}
+
+	CompleteCmd struct {
+		Words []string `arg:"" optional:"" help:"Words typed so far, the last one being completed"`
+	}
+)
+
+func (c *CompletionCmd) Run(ctx *CLIContext) error {
+	script, err := complete.Script(c.Shell)
+	if err != nil {
+		return err
+	}
+	ctx.out.Printf("%s", script)
+	return nil
+}
+
+// Run prints the completions of the last word, one per line and followed by a tab and a description
+// when there is one, for the completion scripts printed by memo completion.
+func (c *CompleteCmd) Run(ctx *CLIContext, kctx *kong.Context) error {
+	words := c.Words
+	if len(words) > 1 {
+		expanded, err := expandAlias(kctx.Model, ctx.cfg.Aliases, words[:len(words)-1])
+		if err != nil {
+			return err
+		}
+		words = append(expanded, words[len(words)-1])
+	}
+
+	completer := &complete.Completer{
+		App: kctx.Model,
+		Predictors: map[string]complete.Predictor{
+			"ref":     func() []string { return refCandidates(ctx) },
+			"tag":     func() []string { return tagCandidates(ctx) },
+			"ext":     func() []string { return extCandidates(ctx) },
+			"command": func() []string { return commandCandidates(kctx.Model) },
+		},
+		Commands: func() []complete.Candidate { return externalCommands(ctx) },
+	}
+
+	result := completer.Complete(words)
+	for _, candidate := range result.Candidates {
+		if candidate.Description == "" {
+			ctx.out.Println(candidate.Value)
+		} else {
+			ctx.out.Printf("%s\t%s\n", candidate.Value, candidate.Description)
+		}
+	}
+	if result.Files {
+		ctx.out.Println(complete.FilesDirective)
+	}
+	return nil
+}
+
+// refCandidates returns @latest and the names of memos, most recent first.
+func refCandidates(ctx *CLIContext) []string {
+	entries, _ := memo.List(ctx.fs)
+	refs := []string{memo.LatestRef}
+	for _, entry := range entries {
+		if entry.Name != "" && !slices.Contains(refs, entry.Name) {
+			refs = append(refs, entry.Name)
+		}
+	}
+	return refs
+}
+
+// tagCandidates returns the tags used in memos. Encrypted memos are left out so that completion never
+// asks for a passphrase.
+func tagCandidates(ctx *CLIContext) []string {
+	entries, _ := memo.List(ctx.fs)
+	tags := make(map[string]bool)
+	for _, entry := range entries {
+		if entry.Encrypted {
+			continue
+		}
+		content, err := ctx.fs.ReadFile(entry.RelPath)
+		if err != nil {
+			continue
+		}
+		for _, tag := range markdown.Tags(content) {
+			tags[tag] = true
+		}
+	}
+	return slices.Sorted(maps.Keys(tags))
+}
+
+// extCandidates returns md, the default extension, and the extensions of existing memos.
+func extCandidates(ctx *CLIContext) []string {
+	entries, _ := memo.List(ctx.fs)
+	exts := []string{"md"}
+	for _, entry := range entries {
+		if !slices.Contains(exts, entry.Ext) {
+			exts = append(exts, entry.Ext)
+		}
+	}
+	return exts
+}
+
+// commandCandidates returns the visible top-level commands and the plugins.
+func commandCandidates(app *kong.Application) []string {
+	var names []string
+	for _, child := range app.Children {
+		if child.Type == kong.CommandNode && !child.Hidden {
+			names = append(names, child.Name)
+		}
+	}
+	for _, p := range plugin.List() {
+		names = append(names, p.Name)
+	}
+	return names
+}
+
+// externalCommands returns the aliases and plugins, completed along with the built-in commands.
+func externalCommands(ctx *CLIContext) []complete.Candidate {
+	var candidates []complete.Candidate
+	for _, name := range slices.Sorted(maps.Keys(ctx.cfg.Aliases)) {
+		candidates = append(candidates, complete.Candidate{Value: name, Description: "alias for " + ctx.cfg.Aliases[name]})
+	}
+	for _, p := range plugin.List() {
+		candidates = append(candidates, complete.Candidate{Value: p.Name, Description: fmt.Sprintf("plugin (%s)", p.Path)})
+	}
+	return candidates
+}
@@ -0,0 +1,122 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"time"
+
+	"github.com/sushichan044/memo-cli/internal/daily"
+	"github.com/sushichan044/memo-cli/internal/hook"
+	"github.com/sushichan044/memo-cli/schema"
+)
+
+type (
+	// dailyFlags are shared by the daily note commands.
+	dailyFlags struct {
+		Carry  *bool `help:"Carry unchecked tasks of the previous daily note into a new one (default: daily.carry_forward)" negatable:""`
+		NoEdit bool  `help:"Print the path of the daily note instead of opening it in $EDITOR"                              name:"no-edit"`
+	}
+
+	TodayCmd struct {
+		dailyFlags
+	}
+
+	YesterdayCmd struct {
+		dailyFlags
+	}
+
+	DayCmd struct {
+		Date string `arg:"" help:"Day of the daily note: YYYY-MM-DD, today, yesterday or tomorrow"`
+
+		dailyFlags
+	}
+)
+
+func (c *TodayCmd) Run(ctx *CLIContext) error {
+	return c.open(ctx, time.Now())
+}
+
+func (c *YesterdayCmd) Run(ctx *CLIContext) error {
+	return c.open(ctx, time.Now().AddDate(0, 0, -1))
+}
+
+func (c *DayCmd) Run(ctx *CLIContext) error {
+	date, err := daily.ParseDate(c.Date, time.Now())
+	if err != nil {
+		return err
+	}
+	return c.open(ctx, date)
+}
+
+// open creates or reuses the daily note of date and opens it in the editor.
+func (f *dailyFlags) open(ctx *CLIContext, date time.Time) error {
+	tmpl, err := dailyTemplate(ctx)
+	if err != nil {
+		return err
+	}
+
+	// --carry and --no-carry override daily.carry_forward of the config file.
+	carry := ctx.cfg.Daily.CarryForward
+	if f.Carry != nil {
+		carry = *f.Carry
+	}
+
+	note, err := daily.Open(ctx.fs, date, daily.Options{
+		Template:     tmpl,
+		CarryForward: carry,
+		FilePerm:     ctx.cfg.FilePerm(),
+		DirPerm:      ctx.cfg.DirPerm(),
+		Hooks:        ctx.hooks(),
+	})
+	if err != nil {
+		return err
+	}
+	if note.Created {
+		ctx.record("new %s", note.Entry.RelPath)
+		ctx.out.Infof("✅ Daily note created at: %s\n", note.Entry.Path)
+		if note.Carried > 0 {
+			ctx.out.Infof("📌 Carried over %d open task(s)\n", note.Carried)
+		}
+	}
+
+	if !f.NoEdit {
+		changed, editErr := editPlain(note.Entry.Path)
+		if editErr != nil {
+			return editErr
+		}
+		if changed {
+			_ = ctx.hooks().Run(hook.PostEdit, note.Entry.Schema())
+			ctx.record("edit %s", note.Entry.RelPath)
+		}
+	}
+
+	if ctx.out.JSON() {
+		return ctx.out.Emit(schema.Daily{Memo: note.Entry.Schema(), Created: note.Created, Carried: note.Carried})
+	}
+
+	if f.NoEdit {
+		ctx.out.Println(note.Entry.Path)
+	}
+
+	return nil
+}
+
+// dailyTemplate reads the configured daily template, or returns "" for the built-in one.
+func dailyTemplate(ctx *CLIContext) (string, error) {
+	path, err := ctx.cfg.DailyTemplatePath()
+	if err != nil {
+		return "", err
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) && ctx.cfg.Daily.Template == "" {
+			return "", nil
+		}
+		return "", fmt.Errorf("failed to read daily template: %w", err)
+	}
+
+	return string(data), nil
+}
@@ -0,0 +1,52 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/alecthomas/kong"
+
+	"github.com/sushichan044/memo-cli/internal/docs"
+	"github.com/sushichan044/memo-cli/version"
+)
+
+//go:generate go run . gen-docs --doc-format markdown ../../docs
+
+const (
+	docFormatMan      = "man"
+	docFormatMarkdown = "markdown"
+)
+
+// GenDocsCmd selects its format with --doc-format, as kong does not allow a command flag to share the name
+// of the global --format.
+type GenDocsCmd struct {
+	Dir    string `arg:""              help:"Directory to write the documentation to" type:"path"`
+	Format string `enum:"man,markdown" help:"Documentation format: man or markdown"   name:"doc-format" required:""`
+}
+
+// Run writes man pages or the Markdown reference of the CLI, selected with --doc-format, into c.Dir.
+func (c *GenDocsCmd) Run(ctx *CLIContext, kctx *kong.Context) error {
+	var pages []docs.Page
+	switch c.Format {
+	case docFormatMan:
+		pages = docs.Man(kctx.Model, "memo-cli "+version.Get())
+	case docFormatMarkdown:
+		pages = docs.Markdown(kctx.Model)
+	}
+
+	//nolint:mnd // documentation is meant to be read by everyone
+	if err := os.MkdirAll(c.Dir, 0o755); err != nil {
+		return err
+	}
+	for _, page := range pages {
+		//nolint:gosec,mnd // documentation is meant to be read by everyone
+		if err := os.WriteFile(filepath.Join(c.Dir, page.Name), page.Content, 0o644); err != nil {
+			return fmt.Errorf("writing %s: %w", page.Name, err)
+		}
+	}
+
+	ctx.out.Infof("✅ Wrote %d page(s) to: %s\n", len(pages), c.Dir)
+	ctx.out.Println(c.Dir)
+	return nil
+}
@@ -0,0 +1,69 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/sushichan044/memo-cli/internal/memo"
+	"github.com/sushichan044/memo-cli/schema"
+)
+
+type DoctorCmd struct {
+	FixPerms bool `help:"Tighten permissions of memos and date directories to the configured modes." name:"fix-perms"`
+}
+
+func (c *DoctorCmd) Run(ctx *CLIContext) error {
+	creator := memo.NewWithFS(ctx.cfg, ctx.fs)
+
+	gitignoreWarning := creator.CheckGitignore()
+	rootWarning := creator.CheckRootPermissions()
+	ctx.out.Warn(gitignoreWarning)
+	ctx.out.Warn(rootWarning)
+
+	issues, err := creator.CheckPermissions()
+	if err != nil {
+		return err
+	}
+
+	fixed := false
+	if c.FixPerms && len(issues) > 0 {
+		if fixErr := creator.FixPermissions(issues); fixErr != nil {
+			return fixErr
+		}
+		fixed = true
+	}
+
+	if ctx.out.JSON() {
+		report := schema.Doctor{
+			GitignoreOK:      gitignoreWarning == "",
+			RootPrivate:      rootWarning == "",
+			PermissionIssues: make([]schema.PermissionIssue, 0, len(issues)),
+			Fixed:            fixed,
+		}
+		for _, issue := range issues {
+			report.PermissionIssues = append(report.PermissionIssues, schema.PermissionIssue{
+				Path:  issue.Path,
+				Mode:  fmt.Sprintf("%04o", issue.Mode),
+				Want:  fmt.Sprintf("%04o", issue.Want),
+				IsDir: issue.IsDir,
+			})
+		}
+		if emitErr := ctx.out.Emit(report); emitErr != nil {
+			return emitErr
+		}
+	} else {
+		for _, issue := range issues {
+			ctx.out.Infof("  %04o -> %04o  %s\n", issue.Mode, issue.Want, issue.Path)
+		}
+	}
+
+	switch {
+	case len(issues) == 0:
+		ctx.out.Infof("✅ Memo permissions look good\n")
+	case fixed:
+		ctx.out.Infof("✅ Fixed permissions of %d path(s)\n", len(issues))
+	default:
+		return fmt.Errorf("found %d path(s) with loose permissions; run `memo doctor --fix-perms` to fix them", len(issues))
+	}
+
+	return nil
+}
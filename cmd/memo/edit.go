@@ -0,0 +1,66 @@
+package main
+
+import (
+	"bytes"
+	"os"
+
+	"github.com/sushichan044/memo-cli/internal/crypt"
+	"github.com/sushichan044/memo-cli/internal/editor"
+	"github.com/sushichan044/memo-cli/internal/hook"
+	"github.com/sushichan044/memo-cli/internal/memo"
+	"github.com/sushichan044/memo-cli/schema"
+)
+
+type EditCmd struct {
+	Ref string `arg:"" optional:"" help:"Memo to edit: @latest, a path, or a memo name" default:"@latest" predictor:"ref"`
+}
+
+func (c *EditCmd) Run(ctx *CLIContext) error {
+	entry, err := memo.Resolve(ctx.fs, c.Ref)
+	if err != nil {
+		return err
+	}
+
+	var changed bool
+	if entry.Encrypted {
+		changed, err = crypt.NewKeyring(ctx.cfg.Encryption).Edit(ctx.fs, entry.RelPath, ctx.cfg.FilePerm(), editor.Open)
+	} else {
+		changed, err = editPlain(entry.Path)
+	}
+	if err != nil {
+		return err
+	}
+	if changed {
+		_ = ctx.hooks().Run(hook.PostEdit, entry.Schema())
+		ctx.record("edit %s", entry.RelPath)
+	}
+
+	if ctx.out.JSON() {
+		return ctx.out.Emit(schema.Edited{Memo: entry.Schema(), Changed: changed})
+	}
+
+	if changed && entry.Encrypted {
+		ctx.out.Infof("🔒 Memo re-encrypted at: %s\n", entry.Path)
+	}
+
+	return nil
+}
+
+// editPlain opens an unencrypted memo in the editor and reports whether its content changed.
+func editPlain(path string) (bool, error) {
+	before, err := os.ReadFile(path)
+	if err != nil {
+		return false, err
+	}
+
+	if openErr := editor.Open(path); openErr != nil {
+		return false, openErr
+	}
+
+	after, err := os.ReadFile(path)
+	if err != nil {
+		return false, err
+	}
+
+	return !bytes.Equal(before, after), nil
+}
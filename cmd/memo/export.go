@@ -0,0 +1,287 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"time"
+
+	"github.com/sushichan044/memo-cli/internal/daily"
+	"github.com/sushichan044/memo-cli/internal/importer"
+	"github.com/sushichan044/memo-cli/internal/memo"
+	"github.com/sushichan044/memo-cli/internal/memofs"
+	"github.com/sushichan044/memo-cli/internal/site"
+	"github.com/sushichan044/memo-cli/internal/transfer"
+	"github.com/sushichan044/memo-cli/schema"
+)
+
+const (
+	importFromAuto   = "auto"
+	importFromExport = "export"
+)
+
+type (
+	ExportCmd struct {
+		Archive ExportArchiveCmd `cmd:"" default:"withargs" help:"Write memos as JSON Lines (the default), zip or tar, selected with --archive-format."`
+		HTML    ExportHTMLCmd    `cmd:"html"                help:"Render memos as a static HTML site."`
+	}
+
+	ExportArchiveCmd struct {
+		Output        string `help:"File to write to (default: stdout)"                                                    short:"o" type:"path"`
+		ArchiveFormat string `help:"Archive format: jsonl, zip or tar (named so as not to clash with the global --format)" name:"archive-format" enum:"jsonl,zip,tar" default:"jsonl"`
+		Since         string `help:"Only export memos created since a day (YYYY-MM-DD, today, yesterday)"`
+		Until         string `help:"Only export memos created until a day (YYYY-MM-DD, today, yesterday)"`
+	}
+
+	ImportCmd struct {
+		Source string `arg:""                                                        help:"File written by memo export (JSON Lines, zip or tar), - for stdin, or notes of another tool"`
+		From   string `       default:"auto" enum:"auto,export,obsidian,jrnl,folder" help:"Source format: auto detects an Obsidian vault, a jrnl file, any other folder or a memo export"`
+		DryRun bool   `                                                              help:"Show what would be imported, and from where, without writing anything"                         name:"dry-run"`
+	}
+
+	ExportHTMLCmd struct {
+		Dir              string `arg:""                                                                        help:"Directory to write the site to" type:"path"`
+		Title            string `default:"Memos"                                                               help:"Site title"`
+		Since            string `help:"Only export memos created since a day (YYYY-MM-DD, today, yesterday)"`
+		Until            string `help:"Only export memos created until a day (YYYY-MM-DD, today, yesterday)"`
+		Tag              string `help:"Only export memos tagged with this tag"                                 short:"t" predictor:"tag"`
+		IncludeEncrypted bool   `help:"Also export encrypted memos, decrypted (they are left out by default)" name:"include-encrypted"`
+		Force            bool   `help:"Write into a directory that is not empty"`
+	}
+)
+
+func (c *ExportArchiveCmd) Run(ctx *CLIContext) error {
+	since, err := daily.ParseOptionalDate(c.Since, time.Now())
+	if err != nil {
+		return err
+	}
+	until, err := daily.ParseOptionalDate(c.Until, time.Now())
+	if err != nil {
+		return err
+	}
+
+	entries, err := memo.List(ctx.fs)
+	if err != nil {
+		return err
+	}
+	selected := slices.DeleteFunc(entries, func(e memo.Entry) bool {
+		return (!since.IsZero() && e.DateDir < memo.DateDir(since)) || (!until.IsZero() && e.DateDir > memo.DateDir(until))
+	})
+	// Oldest first, so that an import recreates memos in the order they were written.
+	slices.Reverse(selected)
+
+	if c.Output == "" {
+		_, err = transfer.Export(ctx.fs, selected, os.Stdout, transfer.Format(c.ArchiveFormat))
+		return err
+	}
+
+	// Exports hold memos, so they get the same permissions.
+	file, err := os.OpenFile(c.Output, os.O_WRONLY|os.O_CREATE|os.O_EXCL, ctx.cfg.FilePerm())
+	if err != nil {
+		return fmt.Errorf("failed to create export: %w", err)
+	}
+	count, err := transfer.Export(ctx.fs, selected, file, transfer.Format(c.ArchiveFormat))
+	if closeErr := file.Close(); err == nil && closeErr != nil {
+		err = fmt.Errorf("failed to write export: %w", closeErr)
+	}
+	if err != nil {
+		_ = os.Remove(c.Output)
+		return err
+	}
+
+	if ctx.out.JSON() {
+		return ctx.out.Emit(schema.Exported{Path: c.Output, Format: c.ArchiveFormat, Memos: count})
+	}
+
+	ctx.out.Infof("✅ Exported %d memo(s) to: %s\n", count, c.Output)
+
+	return nil
+}
+
+func (c *ImportCmd) Run(ctx *CLIContext) error {
+	items, notes, err := c.read()
+	if err != nil {
+		return err
+	}
+
+	var preSkipped []transfer.Skipped
+	if notes != nil {
+		items, preSkipped = importer.Items(notes)
+	}
+
+	result, err := transfer.Import(ctx.fs, items, transfer.ImportOptions{
+		FilePerm: ctx.cfg.FilePerm(),
+		DirPerm:  ctx.cfg.DirPerm(),
+		DryRun:   c.DryRun,
+	})
+	// A failed import may still have written some memos; they are recorded as such.
+	if !c.DryRun && len(result.Imported)+len(result.Renamed) > 0 {
+		if err != nil {
+			ctx.record("import %s (partial)", filepath.Base(c.Source))
+		} else {
+			ctx.record("import %s", filepath.Base(c.Source))
+		}
+	}
+	result.Skipped = append(preSkipped, result.Skipped...)
+
+	if ctx.out.JSON() {
+		doc := schema.Imported{
+			Imported: nonNil(result.Imported),
+			Renamed:  make([]schema.ImportRename, 0, len(result.Renamed)),
+			Skipped:  make([]schema.ImportSkip, 0, len(result.Skipped)),
+			DryRun:   c.DryRun,
+		}
+		for _, r := range result.Renamed {
+			doc.Renamed = append(doc.Renamed, schema.ImportRename{From: r.From, To: r.To})
+		}
+		for _, s := range result.Skipped {
+			doc.Skipped = append(doc.Skipped, schema.ImportSkip{RelPath: s.Name, Reason: s.Reason})
+		}
+		for _, note := range notes {
+			relPath, _ := note.RelPath()
+			doc.Sources = append(doc.Sources, schema.ImportSource{
+				RelPath: relPath, Source: note.Source, TimeSource: string(note.TimeSource),
+			})
+		}
+		if emitErr := ctx.out.Emit(doc); emitErr != nil {
+			return emitErr
+		}
+		return err
+	}
+	if err != nil {
+		return err
+	}
+
+	if c.DryRun {
+		for _, note := range notes {
+			if relPath, relErr := note.RelPath(); relErr == nil {
+				ctx.out.Infof("  %s <- %s (%s)\n", relPath, note.Source, note.TimeSource)
+			}
+		}
+	}
+	for _, r := range result.Renamed {
+		ctx.out.Infof("  renamed  %s -> %s\n", r.From, r.To)
+	}
+	for _, s := range result.Skipped {
+		ctx.out.Infof("  skipped  %s: %s\n", s.Name, s.Reason)
+	}
+
+	verb := "Imported"
+	if c.DryRun {
+		verb = "Would import"
+	}
+	ctx.out.Infof("✅ %s %d memo(s), %d renamed; skipped %d\n",
+		verb, len(result.Imported)+len(result.Renamed), len(result.Renamed), len(result.Skipped))
+
+	return nil
+}
+
+// read reads the source: a memo export as items, or the notes of another tool.
+func (c *ImportCmd) read() ([]transfer.Item, []importer.Note, error) {
+	if c.Source == "-" {
+		if c.From != importFromAuto && c.From != importFromExport {
+			return nil, nil, fmt.Errorf("--from %s cannot read from stdin", c.From)
+		}
+		items, err := transfer.Read(os.Stdin)
+		return items, nil, err
+	}
+
+	abs, err := filepath.Abs(c.Source)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to open import: %w", err)
+	}
+	// Importers read through fs.FS, rooted at the parent directory so that a file source works too.
+	fsys, name := os.DirFS(filepath.Dir(abs)), filepath.Base(abs)
+
+	var imp importer.Importer
+	switch c.From {
+	case importFromExport:
+	case importFromAuto:
+		if info, statErr := os.Stat(abs); statErr != nil {
+			return nil, nil, fmt.Errorf("failed to open import: %w", statErr)
+		} else if info.IsDir() {
+			imp, _ = importer.Detect(fsys, name)
+		} else if jrnl := (importer.Jrnl{}); jrnl.Detect(fsys, name) {
+			imp = jrnl
+		}
+	default:
+		imp, _ = importer.Lookup(c.From)
+	}
+
+	if imp != nil {
+		notes, notesErr := imp.Notes(fsys, name)
+		if notesErr != nil {
+			return nil, nil, fmt.Errorf("failed to read %s: %w", c.Source, notesErr)
+		}
+		return nil, nonNilNotes(notes), nil
+	}
+
+	file, err := os.Open(abs)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to open import: %w", err)
+	}
+	defer file.Close()
+
+	items, err := transfer.Read(file)
+	return items, nil, err
+}
+
+// nonNilNotes tells an importer source without notes from a memo export.
+func nonNilNotes(notes []importer.Note) []importer.Note {
+	if notes == nil {
+		return []importer.Note{}
+	}
+	return notes
+}
+
+func (c *ExportHTMLCmd) Run(ctx *CLIContext) error {
+	since, err := daily.ParseOptionalDate(c.Since, time.Now())
+	if err != nil {
+		return err
+	}
+	until, err := daily.ParseOptionalDate(c.Until, time.Now())
+	if err != nil {
+		return err
+	}
+
+	if !c.Force {
+		existing, readErr := os.ReadDir(c.Dir)
+		if readErr != nil && !errors.Is(readErr, fs.ErrNotExist) {
+			return fmt.Errorf("failed to read %s: %w", c.Dir, readErr)
+		}
+		if len(existing) > 0 {
+			return fmt.Errorf("%s is not empty; use --force to write into it", c.Dir)
+		}
+	}
+
+	entries, err := memo.List(ctx.fs)
+	if err != nil {
+		return err
+	}
+
+	result, err := site.Write(memofs.NewOS(c.Dir), entries, readableContent(ctx), site.Options{
+		Title:            c.Title,
+		Since:            since,
+		Until:            until,
+		Tag:              c.Tag,
+		IncludeEncrypted: c.IncludeEncrypted,
+		FilePerm:         ctx.cfg.FilePerm(),
+		DirPerm:          ctx.cfg.DirPerm(),
+		Now:              time.Now(),
+	})
+	if err != nil {
+		return err
+	}
+
+	if ctx.out.JSON() {
+		return ctx.out.Emit(schema.Site{Path: c.Dir, Memos: result.Memos, Tags: result.Tags})
+	}
+
+	ctx.out.Infof("✅ Exported %d memo(s) and %d tag page(s) to: %s\n", result.Memos, result.Tags, c.Dir)
+	ctx.out.Println(c.Dir)
+
+	return nil
+}
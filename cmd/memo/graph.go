@@ -0,0 +1,88 @@
+package main
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/alecthomas/kong"
+
+	"github.com/sushichan044/memo-cli/internal/daily"
+	"github.com/sushichan044/memo-cli/internal/graph"
+	"github.com/sushichan044/memo-cli/internal/memo"
+	"github.com/sushichan044/memo-cli/schema"
+)
+
+type GraphCmd struct {
+	Since   string `help:"Only include memos created since a day (YYYY-MM-DD, today, yesterday)"`
+	Until   string `help:"Only include memos created until a day (YYYY-MM-DD, today, yesterday)"`
+	NoLinks bool   `help:"Leave out [[wiki-link]] edges"                                                                        name:"no-links"`
+	NoTags  bool   `help:"Leave out shared tag edges"                                                                           name:"no-tags"`
+	Output  string `help:"Syntax of the text output: dot (Graphviz) or mermaid; JSON is selected with the global --format json" enum:"dot,mermaid" default:"dot"`
+}
+
+func (c *GraphCmd) Run(ctx *CLIContext, kctx *kong.Context) error {
+	if ctx.out.JSON() && flagGiven(kctx, "output") {
+		return fmt.Errorf("--output %s cannot be combined with --format json", c.Output)
+	}
+
+	since, err := daily.ParseOptionalDate(c.Since, time.Now())
+	if err != nil {
+		return err
+	}
+	until, err := daily.ParseOptionalDate(c.Until, time.Now())
+	if err != nil {
+		return err
+	}
+
+	entries, err := memo.List(ctx.fs)
+	if err != nil {
+		return err
+	}
+
+	g, err := graph.Build(entries, readableContent(ctx), graph.Options{
+		Since: since, Until: until, NoLinks: c.NoLinks, NoTags: c.NoTags,
+	})
+	if err != nil {
+		return err
+	}
+
+	switch {
+	case ctx.out.JSON():
+		return ctx.out.Emit(graphSchema(g))
+	case c.Output == "mermaid":
+		ctx.out.Printf("%s", g.Mermaid())
+	default:
+		ctx.out.Printf("%s", g.DOT())
+	}
+
+	return nil
+}
+
+// flagGiven reports whether the flag name was given on the command line, rather than left to its default.
+func flagGiven(kctx *kong.Context, name string) bool {
+	for _, el := range kctx.Path {
+		if el.Flag != nil && el.Flag.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+func graphSchema(g *graph.Graph) schema.Graph {
+	doc := schema.Graph{
+		Nodes: make([]schema.GraphNode, 0, len(g.Nodes)),
+		Edges: make([]schema.GraphEdge, 0, len(g.Edges)),
+	}
+	for _, node := range g.Nodes {
+		doc.Nodes = append(doc.Nodes, schema.GraphNode{Memo: node.Entry.Schema(), Tags: nonNil(node.Tags)})
+	}
+	for _, edge := range g.Edges {
+		doc.Edges = append(doc.Edges, schema.GraphEdge{
+			From: g.Nodes[edge.From].Entry.RelPath,
+			To:   g.Nodes[edge.To].Entry.RelPath,
+			Kind: string(edge.Kind),
+			Tags: edge.Tags,
+		})
+	}
+	return doc
+}
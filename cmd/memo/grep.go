@@ -0,0 +1,103 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+
+	"github.com/sushichan044/memo-cli/internal/crypt"
+	"github.com/sushichan044/memo-cli/internal/index"
+	"github.com/sushichan044/memo-cli/internal/memo"
+	"github.com/sushichan044/memo-cli/schema"
+)
+
+type GrepCmd struct {
+	Pattern    string `arg:"" help:"Regular expression to search for"`
+	IgnoreCase bool   `       help:"Match case-insensitively"           short:"i"`
+}
+
+func (c *GrepCmd) Run(ctx *CLIContext) error {
+	pattern := c.Pattern
+	if c.IgnoreCase {
+		pattern = "(?i)" + pattern
+	}
+
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return fmt.Errorf("invalid pattern: %w", err)
+	}
+
+	entries, err := memo.List(ctx.fs)
+	if err != nil {
+		return err
+	}
+
+	candidates := entries
+	if ix, _ := syncIndex(ctx, entries); ix != nil {
+		candidates = ix.Candidates(ctx.fs, entries, re)
+	}
+
+	matches, err := memo.Search(candidates, re, readableContent(ctx))
+	if err != nil {
+		return err
+	}
+
+	for _, m := range matches {
+		if ctx.out.JSON() {
+			if emitErr := ctx.out.Emit(schema.Match{Memo: m.Entry.Schema(), Line: m.Line, Text: m.Text}); emitErr != nil {
+				return emitErr
+			}
+			continue
+		}
+		ctx.out.Printf("%s:%d:%s\n", m.Entry.Path, m.Line, m.Text)
+	}
+
+	return nil
+}
+
+// readableContent returns a reader of memo content for commands that scan every memo.
+// Without an identity, encrypted memos are skipped with a single warning instead of failing outright.
+func readableContent(ctx *CLIContext) func(memo.Entry) ([]byte, error) {
+	keyring := crypt.NewKeyring(ctx.cfg.Encryption)
+	warned := false
+
+	return func(entry memo.Entry) ([]byte, error) {
+		content, err := keyring.ReadFile(ctx.fs, entry.RelPath)
+		if errors.Is(err, crypt.ErrNoIdentity) {
+			if !warned {
+				ctx.out.Warn(fmt.Sprintf("⚠️  Warning: skipping encrypted memos: %v", err))
+				warned = true
+			}
+			return nil, nil
+		}
+		return content, err
+	}
+}
+
+// syncIndex loads the search index of the memo root and brings it up to date with entries, saving it if it changed.
+// The index only speeds searches up: when it cannot be used, a warning is printed and nil is returned,
+// and callers search every memo.
+func syncIndex(ctx *CLIContext, entries []memo.Entry) (*index.Index, string) {
+	path, err := ctx.cfg.IndexPath()
+	if err != nil {
+		ctx.out.Warn(fmt.Sprintf("⚠️  Warning: search index unavailable: %v", err))
+		return nil, ""
+	}
+
+	ix, err := index.Load(path)
+	if err != nil {
+		ctx.out.Warn(fmt.Sprintf("⚠️  Warning: search index unavailable: %v", err))
+		return nil, ""
+	}
+	changed, err := ix.Sync(ctx.fs, entries)
+	if err != nil {
+		ctx.out.Warn(fmt.Sprintf("⚠️  Warning: search index unavailable: %v", err))
+		return nil, ""
+	}
+	if changed {
+		if saveErr := ix.Save(path); saveErr != nil {
+			ctx.out.Warn(fmt.Sprintf("⚠️  Warning: %v", saveErr))
+		}
+	}
+	return ix, path
+}
@@ -0,0 +1,128 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/sushichan044/memo-cli/internal/history"
+	"github.com/sushichan044/memo-cli/internal/hook"
+	"github.com/sushichan044/memo-cli/internal/memo"
+	"github.com/sushichan044/memo-cli/schema"
+)
+
+type (
+	LogCmd struct {
+		Ref string `arg:"" optional:"" help:"Memo to show the history of: @latest, a path, or a memo name" default:"@latest" predictor:"ref"`
+	}
+
+	DiffCmd struct {
+		Ref string `arg:"" help:"Memo to diff: @latest, a path, or a memo name" predictor:"ref"`
+		Rev string `arg:"" help:"Revision to compare the current memo with (default: show the last change)" optional:""`
+	}
+
+	RevertCmd struct {
+		Ref string `arg:"" help:"Memo to restore: @latest, a path, or a memo name" predictor:"ref"`
+		Rev string `arg:"" help:"Revision to restore the memo to, as printed by memo log"`
+	}
+)
+
+// record commits the memo root to its history repository, if history is enabled.
+// The memo itself has already been changed, so a failed commit is only reported as a warning.
+func (ctx *CLIContext) record(format string, args ...any) {
+	repo, err := history.Open(ctx.fs.Root())
+	if errors.Is(err, history.ErrNotEnabled) {
+		return
+	}
+	if err == nil {
+		_, err = repo.Commit(fmt.Sprintf(format, args...))
+	}
+	if err != nil {
+		ctx.out.Warn(fmt.Sprintf("⚠️  Warning: failed to record history: %v", err))
+	}
+}
+
+func (c *LogCmd) Run(ctx *CLIContext) error {
+	entry, repo, err := resolveWithHistory(ctx, c.Ref)
+	if err != nil {
+		return err
+	}
+
+	revisions, err := repo.Log(entry.RelPath)
+	if err != nil {
+		return err
+	}
+
+	for _, rev := range revisions {
+		if ctx.out.JSON() {
+			doc := schema.Revision{Hash: rev.Hash, Date: rev.Time, Subject: rev.Subject, RelPath: rev.Name}
+			if emitErr := ctx.out.Emit(doc); emitErr != nil {
+				return emitErr
+			}
+			continue
+		}
+		ctx.out.Printf("%.7s  %s  %s\n", rev.Hash, rev.Time.Local().Format("2006-01-02 15:04:05"), rev.Subject)
+	}
+
+	return nil
+}
+
+func (c *DiffCmd) Run(ctx *CLIContext) error {
+	entry, repo, err := resolveWithHistory(ctx, c.Ref)
+	if err != nil {
+		return err
+	}
+
+	patch, err := repo.Diff(entry.RelPath, c.Rev)
+	if err != nil {
+		return err
+	}
+
+	if ctx.out.JSON() {
+		return ctx.out.Emit(schema.Diff{Memo: entry.Schema(), Rev: c.Rev, Patch: patch})
+	}
+
+	_, err = os.Stdout.WriteString(patch)
+	return err
+}
+
+func (c *RevertCmd) Run(ctx *CLIContext) error {
+	entry, repo, err := resolveWithHistory(ctx, c.Ref)
+	if err != nil {
+		return err
+	}
+
+	content, err := repo.Show(entry.RelPath, c.Rev)
+	if err != nil {
+		return err
+	}
+
+	if writeErr := ctx.fs.WriteFile(entry.RelPath, content, ctx.cfg.FilePerm()); writeErr != nil {
+		return fmt.Errorf("failed to revert memo: %w", writeErr)
+	}
+	_ = ctx.hooks().Run(hook.PostEdit, entry.Schema())
+	ctx.record("revert %s to %s", entry.RelPath, c.Rev)
+
+	if ctx.out.JSON() {
+		return ctx.out.Emit(schema.Reverted{Memo: entry.Schema(), Rev: c.Rev})
+	}
+
+	ctx.out.Infof("⏪ Memo reverted to %s: %s\n", c.Rev, entry.Path)
+
+	return nil
+}
+
+// resolveWithHistory resolves ref and opens the history repository, which must be enabled.
+func resolveWithHistory(ctx *CLIContext, ref string) (memo.Entry, *history.Repo, error) {
+	repo, err := history.Open(ctx.fs.Root())
+	if err != nil {
+		return memo.Entry{}, nil, err
+	}
+
+	entry, err := memo.Resolve(ctx.fs, ref)
+	if err != nil {
+		return memo.Entry{}, nil, err
+	}
+
+	return entry, repo, nil
+}
@@ -0,0 +1,19 @@
+package main
+
+import (
+	"os"
+
+	"github.com/sushichan044/memo-cli/internal/hook"
+	"github.com/sushichan044/memo-cli/internal/memo"
+)
+
+// hooks returns the runner of the hooks in the config file. Hook output goes to stderr,
+// so that it never mixes with results on stdout.
+func (ctx *CLIContext) hooks() *hook.Runner {
+	return hook.New(ctx.cfg, ctx.fs.Root(), os.Stderr)
+}
+
+// creator returns a Creator of memos in the memo root that runs the create hooks.
+func (ctx *CLIContext) creator() *memo.Creator {
+	return memo.NewWithFS(ctx.cfg, ctx.fs).WithHooks(ctx.hooks())
+}
@@ -0,0 +1,87 @@
+package main_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// memoCLI builds the memo binary and returns a function running it on a memo directory of its own,
+// with a post-edit hook appending the relative path of every edited memo to the returned log.
+func memoCLI(t *testing.T) (func(stdin string, args ...string) string, string) {
+	t.Helper()
+
+	dir := t.TempDir()
+	bin := filepath.Join(dir, "memo")
+	build := exec.Command("go", "build", "-o", bin, ".")
+	out, err := build.CombinedOutput()
+	require.NoError(t, err, string(out))
+
+	log := filepath.Join(dir, "hooks.log")
+	configFile := filepath.Join(dir, "config.toml")
+	config := "[hooks]\npost-edit = 'echo \"$MEMO_REL_PATH\" >> " + log + "'\n"
+	require.NoError(t, os.WriteFile(configFile, []byte(config), 0o600))
+
+	run := func(stdin string, args ...string) string {
+		t.Helper()
+
+		cmd := exec.Command(bin, args...)
+		cmd.Dir = dir
+		cmd.Env = append(os.Environ(),
+			"MEMO_ROOT_DIR="+filepath.Join(dir, "notes"),
+			"MEMO_CONFIG_FILE="+configFile,
+			"MEMO_FORMAT=json",
+		)
+		cmd.Stdin = strings.NewReader(stdin)
+		var stderr bytes.Buffer
+		cmd.Stderr = &stderr
+		stdout, runErr := cmd.Output()
+		require.NoError(t, runErr, stderr.String())
+		return string(stdout)
+	}
+	return run, log
+}
+
+func TestPostEditHook_TasksDoneAndRevert(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("hooks are tested with sh")
+	}
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("history needs git")
+	}
+
+	run, log := memoCLI(t)
+	run("", "init", "--history")
+	run("", "new", "todo")
+
+	var created struct {
+		Hash string `json:"hash"`
+	}
+	require.NoError(t, json.Unmarshal([]byte(run("", "log", "todo")), &created))
+
+	run("- [ ] buy milk\n", "append", "todo")
+	var task struct {
+		ID      string `json:"id"`
+		RelPath string `json:"rel_path"`
+	}
+	require.NoError(t, json.Unmarshal([]byte(run("", "tasks")), &task))
+
+	require.NoError(t, os.Remove(log))
+	run("", "tasks", "done", task.ID)
+	data, err := os.ReadFile(log)
+	require.NoError(t, err)
+	assert.Equal(t, task.RelPath+"\n", string(data), "toggling a task runs post-edit")
+
+	run("", "revert", "todo", created.Hash)
+	data, err = os.ReadFile(log)
+	require.NoError(t, err)
+	assert.Equal(t, task.RelPath+"\n"+task.RelPath+"\n", string(data), "reverting a memo runs post-edit")
+}
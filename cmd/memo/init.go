@@ -0,0 +1,42 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/sushichan044/memo-cli/internal/history"
+	"github.com/sushichan044/memo-cli/schema"
+)
+
+type InitCmd struct {
+	History bool `help:"Keep the history of every memo in a git repository inside the memo directory."`
+}
+
+func (c *InitCmd) Run(ctx *CLIContext) error {
+	if err := ctx.fs.MkdirAll(".", ctx.cfg.DirPerm()); err != nil {
+		return fmt.Errorf("failed to create base directory: %w", err)
+	}
+
+	if c.History {
+		repo, err := history.Init(ctx.fs.Root(), ctx.cfg.FilePerm())
+		if err != nil {
+			return err
+		}
+		if _, commitErr := repo.Commit("import existing memos"); commitErr != nil {
+			return commitErr
+		}
+	}
+
+	_, historyErr := history.Open(ctx.fs.Root())
+	enabled := historyErr == nil
+
+	if ctx.out.JSON() {
+		return ctx.out.Emit(schema.Init{BaseDir: ctx.fs.Root(), History: enabled})
+	}
+
+	ctx.out.Infof("✅ Memo directory initialized at: %s\n", ctx.fs.Root())
+	if enabled {
+		ctx.out.Infof("📜 History is enabled; see `memo log`, `memo diff` and `memo revert`\n")
+	}
+
+	return nil
+}
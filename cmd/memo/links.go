@@ -0,0 +1,125 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/sushichan044/memo-cli/internal/links"
+	"github.com/sushichan044/memo-cli/internal/memo"
+	"github.com/sushichan044/memo-cli/schema"
+)
+
+type (
+	LinksCmd struct {
+		Ref string `arg:"" optional:"" help:"Memo to list the links of: @latest, a path, or a memo name" default:"@latest" predictor:"ref"`
+	}
+
+	BacklinksCmd struct {
+		Ref string `arg:"" optional:"" help:"Memo to list the links to: @latest, a path, or a memo name" default:"@latest" predictor:"ref"`
+	}
+
+	CheckLinksCmd struct{}
+)
+
+func (c *LinksCmd) Run(ctx *CLIContext) error {
+	entry, index, err := resolveWithLinks(ctx, c.Ref)
+	if err != nil {
+		return err
+	}
+
+	for _, link := range index.Outgoing(entry) {
+		if ctx.out.JSON() {
+			if emitErr := ctx.out.Emit(linkSchema(link)); emitErr != nil {
+				return emitErr
+			}
+			continue
+		}
+		if link.Broken {
+			ctx.out.Printf("%d: [[%s]] -> (broken)\n", link.Line, link.Target)
+			continue
+		}
+		ctx.out.Printf("%d: [[%s]] -> %s\n", link.Line, link.Target, link.To.Path)
+	}
+
+	return nil
+}
+
+func (c *BacklinksCmd) Run(ctx *CLIContext) error {
+	entry, index, err := resolveWithLinks(ctx, c.Ref)
+	if err != nil {
+		return err
+	}
+
+	for _, link := range index.Backlinks(entry) {
+		if ctx.out.JSON() {
+			if emitErr := ctx.out.Emit(linkSchema(link)); emitErr != nil {
+				return emitErr
+			}
+			continue
+		}
+		ctx.out.Printf("%s:%d:%s\n", link.From.Path, link.Line, link.Text)
+	}
+
+	return nil
+}
+
+func (c *CheckLinksCmd) Run(ctx *CLIContext) error {
+	index, err := buildLinks(ctx)
+	if err != nil {
+		return err
+	}
+
+	broken := index.Broken()
+	for _, link := range broken {
+		if ctx.out.JSON() {
+			if emitErr := ctx.out.Emit(linkSchema(link)); emitErr != nil {
+				return emitErr
+			}
+			continue
+		}
+		ctx.out.Printf("%s:%d: broken link [[%s]]\n", link.From.Path, link.Line, link.Target)
+	}
+
+	if len(broken) > 0 {
+		return fmt.Errorf("found %d broken link(s)", len(broken))
+	}
+	ctx.out.Infof("✅ All %d link(s) resolve\n", len(index.All()))
+
+	return nil
+}
+
+func buildLinks(ctx *CLIContext) (*links.Index, error) {
+	entries, err := memo.List(ctx.fs)
+	if err != nil {
+		return nil, err
+	}
+
+	return links.Build(entries, readableContent(ctx))
+}
+
+func resolveWithLinks(ctx *CLIContext, ref string) (memo.Entry, *links.Index, error) {
+	entry, err := memo.Resolve(ctx.fs, ref)
+	if err != nil {
+		return memo.Entry{}, nil, err
+	}
+
+	index, err := buildLinks(ctx)
+	if err != nil {
+		return memo.Entry{}, nil, err
+	}
+
+	return entry, index, nil
+}
+
+func linkSchema(link links.Link) schema.Link {
+	doc := schema.Link{
+		Memo:   link.From.Schema(),
+		Line:   link.Line,
+		Target: link.Target,
+		Text:   link.Text,
+	}
+	if !link.Broken {
+		to := link.To.Schema()
+		doc.To = &to
+	}
+	return doc
+}
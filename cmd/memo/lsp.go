@@ -0,0 +1,22 @@
+package main
+
+import (
+	"context"
+	"os"
+
+	"github.com/sushichan044/memo-cli/internal/crypt"
+	"github.com/sushichan044/memo-cli/internal/lsp"
+	"github.com/sushichan044/memo-cli/version"
+)
+
+type LSPCmd struct{}
+
+// Run speaks LSP on stdin and stdout until the editor sends "exit" or closes stdin.
+func (c *LSPCmd) Run(ctx *CLIContext) error {
+	srv := lsp.New(ctx.fs, lsp.Options{
+		Keyring: crypt.NewKeyring(ctx.cfg.Encryption),
+		Version: version.Get(),
+	})
+
+	return srv.Serve(context.Background(), os.Stdin, os.Stdout)
+}
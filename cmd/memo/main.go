@@ -7,54 +7,118 @@ import (
 	"github.com/alecthomas/kong"
 
 	"github.com/sushichan044/memo-cli/internal/config"
+	"github.com/sushichan044/memo-cli/internal/crypt"
 	"github.com/sushichan044/memo-cli/internal/memo"
+	"github.com/sushichan044/memo-cli/internal/memofs"
+	"github.com/sushichan044/memo-cli/internal/output"
+	"github.com/sushichan044/memo-cli/schema"
 	"github.com/sushichan044/memo-cli/version"
 )
 
 type (
 	CLIContext struct {
 		cfg *config.Config
+		fs  memofs.FS
+		out *output.Printer
 	}
 
 	CLI struct {
 		Version kong.VersionFlag `short:"v" help:"Show version."`
-
-		New NewCmd `cmd:"new" help:"Create a new memo."`
+		Format  string           `          help:"Output format (text or json)." enum:"text,json" default:"text" env:"MEMO_FORMAT"`
+
+		Init       InitCmd       `cmd:"init"        help:"Initialize the memo directory."`
+		New        NewCmd        `cmd:"new"         help:"Create a new memo."`
+		Today      TodayCmd      `cmd:"today"       help:"Open today's daily note, creating it if needed."`
+		Yesterday  YesterdayCmd  `cmd:"yesterday"   help:"Open yesterday's daily note."`
+		Day        DayCmd        `cmd:"day"         help:"Open the daily note of a given day."`
+		Show       ShowCmd       `cmd:"show"        help:"Print a memo, decrypting it if needed."`
+		Edit       EditCmd       `cmd:"edit"        help:"Open a memo in $EDITOR, decrypting it if needed."`
+		Append     AppendCmd     `cmd:"append"      help:"Append text to a memo."`
+		Mv         MvCmd         `cmd:"mv"          help:"Rename a memo."`
+		Rm         RmCmd         `cmd:"rm"          help:"Delete a memo."`
+		Grep       GrepCmd       `cmd:"grep"        help:"Search memos, including encrypted ones."`
+		Tasks      TasksCmd      `cmd:"tasks"       help:"List and check off Markdown tasks across memos."`
+		Links      LinksCmd      `cmd:"links"       help:"List the [[wiki-links]] of a memo."`
+		Backlinks  BacklinksCmd  `cmd:"backlinks"   help:"List the memos linking to a memo."`
+		CheckLinks CheckLinksCmd `cmd:"check-links" help:"Report [[wiki-links]] that do not resolve to a memo."`
+		Graph      GraphCmd      `cmd:"graph"       help:"Print the graph of linked memos as DOT or Mermaid (--output), or JSON (--format json)."`
+		Log        LogCmd        `cmd:"log"         help:"Show the history of a memo."`
+		Diff       DiffCmd       `cmd:"diff"        help:"Show changes to a memo."`
+		Revert     RevertCmd     `cmd:"revert"      help:"Restore a memo to an earlier revision."`
+		Backup     BackupCmd     `cmd:"backup"      help:"Back up the memo directory."`
+		Restore    RestoreCmd    `cmd:"restore"     help:"Restore memos from a backup archive."`
+		Export     ExportCmd     `cmd:"export"      help:"Export memos to other formats."`
+		Import     ImportCmd     `cmd:"import"      help:"Import memos written by memo export, or notes from Obsidian, jrnl or a folder."`
+		UI         UICmd         `cmd:"ui"          help:"Browse, search and edit memos in a full-screen terminal UI."`
+		Serve      ServeCmd      `cmd:"serve"       help:"Serve a web UI and JSON API to browse, search and edit memos."`
+		MCP        MCPCmd        `cmd:"mcp"         help:"Serve memos to AI agents over the Model Context Protocol on stdio."`
+		LSP        LSPCmd        `cmd:"lsp"         help:"Run a language server for editing memos, with link and tag completion and link diagnostics."`
+		Watch      WatchCmd      `cmd:"watch"       help:"Print an NDJSON event for every memo created, modified or deleted, keeping the search index up to date."`
+		Doctor     DoctorCmd     `cmd:"doctor"      help:"Diagnose the memo directory."`
+		Alias      AliasCmd      `cmd:"alias"       help:"List command aliases defined in the config file."`
+		Completion CompletionCmd `cmd:"completion"  help:"Print a shell completion script for bash, zsh or fish."`
+		GenDocs    GenDocsCmd    `cmd:""            help:"Write man pages (--doc-format man) or a Markdown reference (--doc-format markdown) of memo." name:"gen-docs"   hidden:""`
+		Complete   CompleteCmd   `cmd:""            help:"Print completions for the completion scripts."                                       name:"__complete" hidden:""`
+		Help       HelpCmd       `cmd:"help"        help:"Show help for memo or one of its commands, and list plugins (memo-<name> executables on PATH)."`
 		// List ListCmd `cmd:"list" help:"List all memos."` TODO: add go-fzf integration
 	}
 
 	NewCmd struct {
-		Name string `arg:"" optional:"" help:"Memo name (default: HH-MM-SS)"`
-		Ext  string `                   help:"Memo file extension"           short:"e" default:"md"`
+		Name    string `arg:"" optional:"" help:"Memo name (default: HH-MM-SS)"`
+		Ext     string `                   help:"Memo file extension"                                 short:"e" default:"md" predictor:"ext"`
+		Encrypt bool   `                   help:"Encrypt the memo with age to the configured recipients"`
 	}
 )
 
 func (c *NewCmd) Run(ctx *CLIContext) error {
-	creator := memo.New(ctx.cfg)
+	creator := ctx.creator()
 
 	// Check gitignore and print warning if needed
-	if warning := creator.CheckGitignore(); warning != "" {
-		fmt.Fprintln(os.Stderr, warning)
-		fmt.Fprintln(os.Stderr) // blank line
+	gitignoreWarning := creator.CheckGitignore()
+	ctx.out.Warn(gitignoreWarning)
+
+	// Check memo root permissions and print warning if needed
+	ctx.out.Warn(creator.CheckRootPermissions())
+
+	var (
+		path string
+		err  error
+	)
+	if c.Encrypt {
+		recipients, recipientsErr := crypt.NewKeyring(ctx.cfg.Encryption).Recipients()
+		if recipientsErr != nil {
+			return recipientsErr
+		}
+		path, err = creator.CreateEncrypted(c.Name, c.Ext, recipients...)
+	} else {
+		path, err = creator.Create(c.Name, c.Ext)
+	}
+	if err != nil {
+		return err
 	}
 
-	path, err := creator.Create(c.Name, c.Ext)
+	entry, err := memo.NewEntry(ctx.fs, path)
 	if err != nil {
-		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
 		return err
 	}
+	ctx.record("new %s", entry.RelPath)
+
+	if ctx.out.JSON() {
+		return ctx.out.Emit(schema.Created{Memo: entry.Schema(), GitignoreOK: gitignoreWarning == ""})
+	}
 
 	// Output success message to stderr
-	fmt.Fprintf(os.Stderr, "✅ Memo created at: %s\n", path)
+	ctx.out.Infof("✅ Memo created at: %s\n", path)
 
 	// Output path to stdout (for piping)
-	fmt.Println(path) //nolint:forbidigo // stdout output is intentional for piping
+	ctx.out.Println(path)
 
 	return nil
 }
 
 func main() {
-	ctx := kong.Parse(&CLI{},
+	cli := CLI{}
+	parser := kong.Must(&cli,
 		kong.Vars{
 			"version": fmt.Sprintf("memo-cli %s", version.Get()),
 		},
@@ -63,11 +127,42 @@ func main() {
 		kong.UsageOnError(),
 	)
 
-	cfg, err := config.New()
-	if err != nil {
-		fmt.Fprintf(os.Stderr, "Error loading config: %v\n", err)
-		os.Exit(1)
+	// The config is loaded before parsing to expand aliases, but its errors are reported once the
+	// arguments are known to be valid, so that --help and --version work with a broken config file.
+	args := os.Args[1:]
+	cfg, cfgErr := config.New()
+	if cfgErr == nil {
+		expanded, err := expandAlias(parser.Model, cfg.Aliases, args)
+		if err != nil {
+			exitWithError(output.New(pluginFormat(args), os.Stdout, os.Stderr), err)
+		}
+		args = expanded
 	}
 
-	ctx.FatalIfErrorf(ctx.Run(&CLIContext{cfg: cfg}))
+	if p, pluginArgs, ok := findPlugin(parser.Model, args); ok {
+		os.Exit(runPlugin(p, pluginArgs, pluginFormat(args[:len(args)-len(pluginArgs)])))
+	}
+
+	ctx, err := parser.Parse(args)
+	parser.FatalIfErrorf(err)
+
+	out := output.New(output.Format(cli.Format), os.Stdout, os.Stderr)
+
+	if cfgErr != nil {
+		exitWithError(out, fmt.Errorf("loading config: %w", cfgErr))
+	}
+
+	if runErr := ctx.Run(&CLIContext{cfg: cfg, fs: memofs.NewOS(cfg.BaseDir), out: out}); runErr != nil {
+		exitWithError(out, runErr)
+	}
+}
+
+// exitWithError reports err on stderr, as a schema.Error document in JSON mode, and exits with status 1.
+func exitWithError(out *output.Printer, err error) {
+	if out.JSON() {
+		_ = output.New(output.FormatJSON, os.Stderr, os.Stderr).Emit(schema.Error{Error: err.Error()})
+	} else {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+	}
+	os.Exit(1)
 }
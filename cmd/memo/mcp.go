@@ -0,0 +1,27 @@
+package main
+
+import (
+	"context"
+	"os"
+
+	"github.com/sushichan044/memo-cli/internal/crypt"
+	"github.com/sushichan044/memo-cli/internal/mcp"
+	"github.com/sushichan044/memo-cli/version"
+)
+
+type MCPCmd struct{}
+
+// Run speaks MCP on stdin and stdout until the client closes stdin. Stdout carries protocol messages only;
+// warnings, such as failed history commits, and the output of hooks go to stderr.
+func (c *MCPCmd) Run(ctx *CLIContext) error {
+	srv := mcp.New(ctx.fs, mcp.Options{
+		Creator:  ctx.creator(),
+		Keyring:  crypt.NewKeyring(ctx.cfg.Encryption),
+		FilePerm: ctx.cfg.FilePerm(),
+		Version:  version.Get(),
+		Record:   func(message string) { ctx.record("%s", message) },
+		Hooks:    ctx.hooks(),
+	})
+
+	return srv.Serve(context.Background(), os.Stdin, os.Stdout)
+}
@@ -0,0 +1,33 @@
+package main
+
+import (
+	"github.com/sushichan044/memo-cli/internal/memo"
+	"github.com/sushichan044/memo-cli/schema"
+)
+
+type MvCmd struct {
+	Ref  string `arg:"" help:"Memo to rename: @latest, a path, or a memo name" predictor:"ref"`
+	Name string `arg:"" help:"New memo name; the timestamp and extension are kept"`
+}
+
+func (c *MvCmd) Run(ctx *CLIContext) error {
+	entry, err := memo.Resolve(ctx.fs, c.Ref)
+	if err != nil {
+		return err
+	}
+
+	renamed, err := memo.Rename(ctx.fs, entry, c.Name)
+	if err != nil {
+		return err
+	}
+	ctx.record("mv %s -> %s", entry.RelPath, renamed.RelPath)
+
+	if ctx.out.JSON() {
+		return ctx.out.Emit(schema.Renamed{Memo: renamed.Schema(), From: entry.RelPath})
+	}
+
+	ctx.out.Infof("✅ Memo renamed to: %s\n", renamed.Path)
+	ctx.out.Println(renamed.Path)
+
+	return nil
+}
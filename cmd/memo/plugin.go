@@ -0,0 +1,146 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"os/signal"
+	"slices"
+	"strings"
+
+	"github.com/alecthomas/kong"
+
+	"github.com/sushichan044/memo-cli/internal/config"
+	"github.com/sushichan044/memo-cli/internal/output"
+	"github.com/sushichan044/memo-cli/internal/plugin"
+)
+
+type HelpCmd struct {
+	Command []string `arg:"" optional:"" help:"Command to show help for" predictor:"command"`
+}
+
+// Run prints the help of memo, followed by the plugins found on PATH, or the help of a command.
+// The help of a plugin is its own: memo help foo runs memo-foo --help.
+func (c *HelpCmd) Run(ctx *CLIContext, kctx *kong.Context) error {
+	if len(c.Command) > 0 && !builtinCommand(kctx.Model, c.Command[0]) {
+		if p, ok := plugin.Find(c.Command[0]); ok {
+			os.Exit(runPlugin(p, append(c.Command[1:], "--help"), ctx.out.Format()))
+		}
+	}
+
+	traced, err := kong.Trace(kctx.Kong, c.Command)
+	if err != nil {
+		return err
+	}
+	if traced.Error != nil {
+		return traced.Error
+	}
+	if usageErr := traced.PrintUsage(false); usageErr != nil {
+		return usageErr
+	}
+
+	plugins := plugin.List()
+	if len(c.Command) > 0 || len(plugins) == 0 {
+		return nil
+	}
+	width := 0
+	for _, p := range plugins {
+		width = max(width, len(p.Name))
+	}
+	ctx.out.Printf("\nPlugins:\n")
+	for _, p := range plugins {
+		ctx.out.Printf("  %-*s    %s\n", width, p.Name, p.Path)
+	}
+	return nil
+}
+
+// findPlugin returns the plugin run by args and the arguments for it: like git, the first argument that is
+// not a flag of memo itself names a plugin when it is not a built-in command.
+func findPlugin(app *kong.Application, args []string) (plugin.Plugin, []string, bool) {
+	i, ok := commandIndex(args)
+	if !ok || builtinCommand(app, args[i]) {
+		return plugin.Plugin{}, nil, false
+	}
+	p, ok := plugin.Find(args[i])
+	return p, args[i+1:], ok
+}
+
+// commandIndex returns the index in args of the command name, the first argument that is not a flag
+// of memo itself. Flags other than --format, such as --help, are left to kong and end the search.
+func commandIndex(args []string) (int, bool) {
+	for i := 0; i < len(args); i++ {
+		switch arg := args[i]; {
+		case arg == "--format":
+			i++
+		case strings.HasPrefix(arg, "--format="):
+		case strings.HasPrefix(arg, "-"):
+			return 0, false
+		default:
+			return i, true
+		}
+	}
+	return 0, false
+}
+
+// pluginFormat returns the output format selected by args or MEMO_FORMAT, for a plugin that kong does not parse.
+func pluginFormat(args []string) output.Format {
+	format := os.Getenv("MEMO_FORMAT")
+	for i, arg := range args {
+		if arg == "--format" && i+1 < len(args) {
+			format = args[i+1]
+		} else if value, ok := strings.CutPrefix(arg, "--format="); ok {
+			format = value
+		}
+	}
+	if format == "" {
+		return output.FormatText
+	}
+	return output.Format(format)
+}
+
+// builtinCommand reports whether name is a command of app or one of its aliases.
+func builtinCommand(app *kong.Application, name string) bool {
+	for _, child := range app.Children {
+		if child.Name == name || slices.Contains(child.Aliases, name) {
+			return true
+		}
+	}
+	return false
+}
+
+// runPlugin runs p with args and the configuration of memo, and returns its exit status.
+func runPlugin(p plugin.Plugin, args []string, format output.Format) int {
+	out := output.New(format, os.Stdout, os.Stderr)
+
+	cfg, err := config.New()
+	if err != nil {
+		exitWithError(out, fmt.Errorf("loading config: %w", err))
+	}
+	configFile, err := config.Path()
+	if err != nil {
+		exitWithError(out, fmt.Errorf("loading config: %w", err))
+	}
+	executable, err := os.Executable()
+	if err != nil {
+		exitWithError(out, err)
+	}
+
+	// The plugin handles Ctrl-C itself; memo waits for it to exit.
+	signal.Notify(make(chan os.Signal, 1), os.Interrupt)
+
+	cmd := p.Command(args, plugin.Env{
+		RootDir:    cfg.BaseDir,
+		Format:     string(format),
+		ConfigFile: configFile,
+		Executable: executable,
+	})
+	if runErr := cmd.Run(); runErr != nil {
+		var exitErr *exec.ExitError
+		if errors.As(runErr, &exitErr) {
+			return max(exitErr.ExitCode(), 1)
+		}
+		exitWithError(out, fmt.Errorf("plugin %s failed: %w", p.Name, runErr))
+	}
+	return 0
+}
@@ -0,0 +1,33 @@
+package main
+
+import (
+	"github.com/sushichan044/memo-cli/internal/hook"
+	"github.com/sushichan044/memo-cli/internal/memo"
+)
+
+type RmCmd struct {
+	Ref string `arg:"" help:"Memo to delete: @latest, a path, or a memo name" predictor:"ref"`
+}
+
+func (c *RmCmd) Run(ctx *CLIContext) error {
+	entry, err := memo.Resolve(ctx.fs, c.Ref)
+	if err != nil {
+		return err
+	}
+
+	if hookErr := ctx.hooks().Run(hook.PreDelete, entry.Schema()); hookErr != nil {
+		return hookErr
+	}
+	if deleteErr := memo.Delete(ctx.fs, entry); deleteErr != nil {
+		return deleteErr
+	}
+	ctx.record("rm %s", entry.RelPath)
+
+	if ctx.out.JSON() {
+		return ctx.out.Emit(entry.Schema())
+	}
+
+	ctx.out.Infof("🗑️  Memo deleted: %s\n", entry.Path)
+
+	return nil
+}
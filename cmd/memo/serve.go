@@ -0,0 +1,79 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/sushichan044/memo-cli/internal/crypt"
+	"github.com/sushichan044/memo-cli/internal/server"
+	"github.com/sushichan044/memo-cli/schema"
+)
+
+// shutdownTimeout bounds how long in-flight requests may take once the server is interrupted.
+const shutdownTimeout = 5 * time.Second
+
+type ServeCmd struct {
+	Addr  string `default:"127.0.0.1:7777" help:"Address to listen on; other hosts can reach the memos unless it is a loopback address"`
+	Title string `default:"Memos"          help:"Title of the web UI"`
+}
+
+func (c *ServeCmd) Run(ctx *CLIContext) error {
+	token, err := server.NewToken()
+	if err != nil {
+		return err
+	}
+
+	listener, err := net.Listen("tcp", c.Addr)
+	if err != nil {
+		return fmt.Errorf("failed to listen on %s: %w", c.Addr, err)
+	}
+	if addr, ok := listener.Addr().(*net.TCPAddr); ok && !addr.IP.IsLoopback() {
+		ctx.out.Warn(fmt.Sprintf("⚠️  Warning: listening on %s, reachable from other hosts", addr))
+	}
+
+	srv := &http.Server{
+		Handler: server.New(ctx.fs, server.Options{
+			Token:    token,
+			Title:    c.Title,
+			Creator:  ctx.creator(),
+			Keyring:  crypt.NewKeyring(ctx.cfg.Encryption),
+			FilePerm: ctx.cfg.FilePerm(),
+			Record:   func(message string) { ctx.record("%s", message) },
+			Hooks:    ctx.hooks(),
+		}),
+		ReadHeaderTimeout: shutdownTimeout,
+	}
+
+	url := fmt.Sprintf("http://%s/?token=%s", listener.Addr(), token)
+	if ctx.out.JSON() {
+		if emitErr := ctx.out.Emit(schema.Serving{URL: url, Token: token}); emitErr != nil {
+			return emitErr
+		}
+	} else {
+		ctx.out.Infof("🌐 Serving %s at:\n", ctx.fs.Root())
+		ctx.out.Println(url)
+		ctx.out.Infof("   API: curl -H 'Authorization: Bearer %s' http://%s/api/memos\n", token, listener.Addr())
+	}
+
+	stop, cancel := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer cancel()
+	go func() {
+		<-stop.Done()
+		shutdown, cancelShutdown := context.WithTimeout(context.Background(), shutdownTimeout)
+		defer cancelShutdown()
+		_ = srv.Shutdown(shutdown)
+	}()
+
+	if serveErr := srv.Serve(listener); !errors.Is(serveErr, http.ErrServerClosed) {
+		return fmt.Errorf("failed to serve: %w", serveErr)
+	}
+
+	return nil
+}
@@ -0,0 +1,32 @@
+package main
+
+import (
+	"os"
+
+	"github.com/sushichan044/memo-cli/internal/crypt"
+	"github.com/sushichan044/memo-cli/internal/memo"
+	"github.com/sushichan044/memo-cli/schema"
+)
+
+type ShowCmd struct {
+	Ref string `arg:"" optional:"" help:"Memo to show: @latest, a path, or a memo name" default:"@latest" predictor:"ref"`
+}
+
+func (c *ShowCmd) Run(ctx *CLIContext) error {
+	entry, err := memo.Resolve(ctx.fs, c.Ref)
+	if err != nil {
+		return err
+	}
+
+	content, err := crypt.NewKeyring(ctx.cfg.Encryption).ReadFile(ctx.fs, entry.RelPath)
+	if err != nil {
+		return err
+	}
+
+	if ctx.out.JSON() {
+		return ctx.out.Emit(schema.Content{Memo: entry.Schema(), Content: string(content)})
+	}
+
+	_, err = os.Stdout.Write(content)
+	return err
+}
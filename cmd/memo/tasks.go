@@ -0,0 +1,140 @@
+package main
+
+import (
+	"slices"
+	"time"
+
+	"github.com/sushichan044/memo-cli/internal/crypt"
+	"github.com/sushichan044/memo-cli/internal/daily"
+	"github.com/sushichan044/memo-cli/internal/hook"
+	"github.com/sushichan044/memo-cli/internal/memo"
+	"github.com/sushichan044/memo-cli/internal/tasks"
+	"github.com/sushichan044/memo-cli/schema"
+)
+
+type (
+	TasksCmd struct {
+		List TasksListCmd `cmd:"" default:"withargs" help:"List tasks across all memos."`
+		Done TasksDoneCmd `cmd:"done"                help:"Toggle the checkbox of a task."`
+	}
+
+	TasksListCmd struct {
+		Open  bool   `help:"Show open tasks (the default)"`
+		Done  bool   `help:"Show done tasks; with --open, show all tasks"`
+		Tag   string `help:"Only show tasks tagged, or in a memo tagged, with this tag"                    short:"t" predictor:"tag"`
+		Since string `help:"Only show tasks of memos created since a day (YYYY-MM-DD, today, yesterday)"`
+	}
+
+	TasksDoneCmd struct {
+		ID string `arg:"" help:"Task ID as printed by memo tasks (a unique prefix is enough)"`
+	}
+)
+
+func (c *TasksListCmd) Run(ctx *CLIContext) error {
+	filter := tasks.Filter{Open: c.Open, Done: c.Done, Tag: c.Tag}
+	if c.Since != "" {
+		since, err := daily.ParseDate(c.Since, time.Now())
+		if err != nil {
+			return err
+		}
+		filter.Since = since
+	}
+
+	collected, err := collectTasks(ctx)
+	if err != nil {
+		return err
+	}
+
+	for _, task := range collected {
+		if !filter.Match(task) {
+			continue
+		}
+		if ctx.out.JSON() {
+			if emitErr := ctx.out.Emit(taskSchema(task)); emitErr != nil {
+				return emitErr
+			}
+			continue
+		}
+		ctx.out.Printf("%s  %s  %s %s  %s:%d\n", task.ID, task.Entry.CreatedAt.Format(time.DateOnly),
+			checkbox(task.Done), task.Text, task.Entry.Path, task.Line)
+	}
+
+	return nil
+}
+
+func (c *TasksDoneCmd) Run(ctx *CLIContext) error {
+	collected, err := collectTasks(ctx)
+	if err != nil {
+		return err
+	}
+
+	task, err := tasks.Find(collected, c.ID)
+	if err != nil {
+		return err
+	}
+
+	keyring := crypt.NewKeyring(ctx.cfg.Encryption)
+	content, err := keyring.ReadFile(ctx.fs, task.Entry.RelPath)
+	if err != nil {
+		return err
+	}
+
+	updated, err := tasks.Toggle(content, task)
+	if err != nil {
+		return err
+	}
+
+	if task.Entry.Encrypted {
+		err = keyring.WriteFile(ctx.fs, task.Entry.RelPath, updated, ctx.cfg.FilePerm())
+	} else {
+		err = ctx.fs.WriteFile(task.Entry.RelPath, updated, ctx.cfg.FilePerm())
+	}
+	if err != nil {
+		return err
+	}
+	_ = ctx.hooks().Run(hook.PostEdit, task.Entry.Schema())
+	ctx.record("edit %s", task.Entry.RelPath)
+
+	task.Done = !task.Done
+	if ctx.out.JSON() {
+		return ctx.out.Emit(taskSchema(task))
+	}
+
+	ctx.out.Infof("✅ %s  %s  %s:%d\n", checkbox(task.Done), task.Text, task.Entry.Path, task.Line)
+
+	return nil
+}
+
+func collectTasks(ctx *CLIContext) ([]tasks.Task, error) {
+	entries, err := memo.List(ctx.fs)
+	if err != nil {
+		return nil, err
+	}
+
+	return tasks.Collect(entries, readableContent(ctx))
+}
+
+func taskSchema(task tasks.Task) schema.Task {
+	doc := schema.Task{
+		Memo: task.Entry.Schema(),
+		ID:   task.ID,
+		Line: task.Line,
+		Done: task.Done,
+		Text: task.Text,
+		Tags: slices.Compact(slices.Sorted(slices.Values(slices.Concat(task.Tags, task.MemoTags)))),
+	}
+	if doc.Tags == nil {
+		doc.Tags = []string{}
+	}
+	if !task.Due.IsZero() {
+		doc.Due = task.Due.Format(time.DateOnly)
+	}
+	return doc
+}
+
+func checkbox(done bool) string {
+	if done {
+		return "[x]"
+	}
+	return "[ ]"
+}
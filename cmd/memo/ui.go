@@ -0,0 +1,48 @@
+package main
+
+import (
+	"io"
+	"os"
+
+	"github.com/sushichan044/memo-cli/internal/crypt"
+	"github.com/sushichan044/memo-cli/internal/editor"
+	"github.com/sushichan044/memo-cli/internal/hook"
+	"github.com/sushichan044/memo-cli/internal/memo"
+	"github.com/sushichan044/memo-cli/internal/output"
+	"github.com/sushichan044/memo-cli/internal/tui"
+)
+
+type UICmd struct{}
+
+func (c *UICmd) Run(ctx *CLIContext) error {
+	keyring := crypt.NewKeyring(ctx.cfg.Encryption)
+	// Writing to the terminal would garble the screen: hook output and history warnings go to the status bar.
+	warnings := &tui.Warnings{}
+	hooks := hook.New(ctx.cfg, ctx.fs.Root(), warnings)
+	uiCtx := *ctx
+	uiCtx.out = output.New(ctx.out.Format(), io.Discard, warnings)
+	app := tui.New(ctx.fs, tui.Options{
+		Creator:  memo.NewWithFS(ctx.cfg, ctx.fs).WithHooks(hooks),
+		Keyring:  keyring,
+		FilePerm: ctx.cfg.FilePerm(),
+		Edit: func(entry memo.Entry) (bool, error) {
+			if entry.Encrypted {
+				return keyring.Edit(ctx.fs, entry.RelPath, ctx.cfg.FilePerm(), editor.Open)
+			}
+			return editPlain(entry.Path)
+		},
+		Record:   func(message string) { uiCtx.record("%s", message) },
+		Hooks:    hooks,
+		Warnings: warnings,
+	})
+
+	term, err := tui.NewTerminal(os.Stdin, os.Stdout)
+	if err != nil {
+		return err
+	}
+	runErr := app.Run(term)
+	if closeErr := term.Close(); runErr == nil {
+		runErr = closeErr
+	}
+	return runErr
+}
@@ -0,0 +1,113 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/sushichan044/memo-cli/internal/hook"
+	"github.com/sushichan044/memo-cli/internal/index"
+	"github.com/sushichan044/memo-cli/internal/memo"
+	"github.com/sushichan044/memo-cli/internal/watch"
+	"github.com/sushichan044/memo-cli/schema"
+)
+
+type WatchCmd struct {
+	Exec     string        `help:"Shell command to run for each event, with the event as JSON on stdin and in MEMO_EVENT, MEMO_PATH and MEMO_REL_PATH" placeholder:"COMMAND"`
+	Poll     bool          `help:"Scan the memo root every --interval instead of using change notifications"`
+	Interval time.Duration `help:"Time between scans when polling"                                                                                       default:"1s"`
+}
+
+func (c *WatchCmd) Run(ctx *CLIContext) error {
+	entries, err := memo.List(ctx.fs)
+	if err != nil {
+		return err
+	}
+	ix, indexPath := syncIndex(ctx, entries)
+
+	stop, cancel := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer cancel()
+
+	watcher := watch.New(ctx.fs, watch.Options{
+		Interval: c.Interval,
+		Poll:     c.Poll,
+		Fallback: func(err error) {
+			ctx.out.Warn(fmt.Sprintf("⚠️  Warning: %v; polling every %s instead", err, c.Interval))
+		},
+	})
+	ctx.out.Infof("👀 Watching %s\n", ctx.fs.Root())
+
+	return watcher.Run(stop, func(events []watch.Event) error {
+		for _, e := range events {
+			event := schema.Event{Memo: e.Entry.Schema(), Type: string(e.Op), Time: e.Time}
+			if ix != nil {
+				c.updateIndex(ctx, ix, e)
+			}
+			if emitErr := ctx.out.Emit(event); emitErr != nil {
+				return emitErr
+			}
+			if c.Exec != "" {
+				if hookErr := runExec(c.Exec, event, ctx.cfg.HookTimeout()); hookErr != nil {
+					ctx.out.Warn(fmt.Sprintf("⚠️  Warning: %v", hookErr))
+				}
+			}
+		}
+
+		if ix != nil {
+			if saveErr := ix.Save(indexPath); saveErr != nil {
+				ctx.out.Warn(fmt.Sprintf("⚠️  Warning: %v", saveErr))
+			}
+		}
+		return nil
+	})
+}
+
+// updateIndex applies e to the search index. A memo that cannot be read is dropped from the index,
+// which makes searches read it again.
+func (c *WatchCmd) updateIndex(ctx *CLIContext, ix *index.Index, e watch.Event) {
+	if e.Op == watch.Deleted {
+		ix.Remove(e.Entry.RelPath)
+		return
+	}
+	if err := ix.Update(ctx.fs, e.Entry); err != nil {
+		ix.Remove(e.Entry.RelPath)
+		ctx.out.Warn(fmt.Sprintf("⚠️  Warning: %v", err))
+	}
+}
+
+// runExec runs command in the shell for event, which it receives as JSON on stdin and in environment variables.
+// The command writes to stderr, so that its output does not mix with the stream of events on stdout.
+// Like hooks, it is killed after timeout, so that a hanging command does not hold up later events.
+func runExec(command string, event schema.Event, timeout time.Duration) error {
+	data, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+
+	execCtx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	cmd := hook.ShellCommand(execCtx, command)
+	cmd.Env = append(os.Environ(),
+		"MEMO_EVENT="+event.Type,
+		"MEMO_PATH="+event.Path,
+		"MEMO_REL_PATH="+event.RelPath,
+	)
+	cmd.Stdin = bytes.NewReader(append(data, '\n'))
+	cmd.Stdout = os.Stderr
+	cmd.Stderr = os.Stderr
+
+	if runErr := cmd.Run(); runErr != nil {
+		if errors.Is(execCtx.Err(), context.DeadlineExceeded) {
+			runErr = fmt.Errorf("timed out after %s", timeout)
+		}
+		return fmt.Errorf("--exec %q failed for %s: %w", command, event.RelPath, runErr)
+	}
+	return nil
+}
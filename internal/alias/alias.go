@@ -0,0 +1,106 @@
+// Package alias expands the command aliases of the [alias] section of the config file, such as
+// bug = "new --tag bug bug-report", into the arguments they stand for.
+package alias
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+)
+
+var (
+	// ErrRecursive is returned when an alias expands, directly or through other aliases, to itself.
+	ErrRecursive = errors.New("recursive alias")
+	// ErrInvalid is returned for aliases that cannot be split into arguments.
+	ErrInvalid = errors.New("invalid alias")
+)
+
+// Expand returns the arguments the command name stands for: the expansion of the alias name,
+// itself expanded as long as it starts with another alias. Aliases never shadow built-in commands,
+// as reported by builtin. It reports false when name is not an alias.
+func Expand(name string, aliases map[string]string, builtin func(string) bool) ([]string, bool, error) {
+	words := []string{name}
+	var chain []string
+	for {
+		head := words[0]
+		value, ok := aliases[head]
+		if !ok || builtin(head) {
+			break
+		}
+		if slices.Contains(chain, head) {
+			return nil, false, fmt.Errorf("%w: %s", ErrRecursive, strings.Join(append(chain, head), " -> "))
+		}
+		chain = append(chain, head)
+
+		expansion, err := Split(value)
+		if err != nil {
+			return nil, false, fmt.Errorf("alias %s: %w", head, err)
+		}
+		if len(expansion) == 0 {
+			return nil, false, fmt.Errorf("%w: alias %s is empty", ErrInvalid, head)
+		}
+		words = append(expansion, words[1:]...)
+	}
+	return words, len(chain) > 0, nil
+}
+
+// Split splits s into arguments like a POSIX shell does, without expanding anything:
+// arguments are separated by whitespace, single quotes preserve their content literally, and
+// backslashes escape the next character, within double quotes only when it is special to them.
+func Split(s string) ([]string, error) {
+	var (
+		args    []string
+		current strings.Builder
+		// inArg reports whether an argument is being read, which may be empty, e.g. "".
+		inArg bool
+		quote rune
+	)
+	runes := []rune(s)
+	for i := 0; i < len(runes); i++ {
+		r := runes[i]
+		switch {
+		case quote == '\'':
+			if r == '\'' {
+				quote = 0
+			} else {
+				current.WriteRune(r)
+			}
+		case quote == '"':
+			switch {
+			case r == '"':
+				quote = 0
+			case r == '\\' && i+1 < len(runes) && strings.ContainsRune("\"\\$`", runes[i+1]):
+				i++
+				current.WriteRune(runes[i])
+			default:
+				current.WriteRune(r)
+			}
+		case r == '\'' || r == '"':
+			quote, inArg = r, true
+		case r == '\\':
+			if i+1 == len(runes) {
+				return nil, fmt.Errorf("%w: trailing backslash in %q", ErrInvalid, s)
+			}
+			i++
+			current.WriteRune(runes[i])
+			inArg = true
+		case r == ' ' || r == '\t' || r == '\n':
+			if inArg {
+				args = append(args, current.String())
+				current.Reset()
+				inArg = false
+			}
+		default:
+			current.WriteRune(r)
+			inArg = true
+		}
+	}
+	if quote != 0 {
+		return nil, fmt.Errorf("%w: unterminated %c quote in %q", ErrInvalid, quote, s)
+	}
+	if inArg {
+		args = append(args, current.String())
+	}
+	return args, nil
+}
@@ -0,0 +1,81 @@
+package alias_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/sushichan044/memo-cli/internal/alias"
+)
+
+func TestSplit(t *testing.T) {
+	tests := []struct {
+		input string
+		want  []string
+	}{
+		{"new --ext md", []string{"new", "--ext", "md"}},
+		{"  append  @latest\t", []string{"append", "@latest"}},
+		{`append @latest "two words" 'it''s'`, []string{"append", "@latest", "two words", "its"}},
+		{`grep 'a\b' "\"q\" \n"`, []string{"grep", `a\b`, `"q" \n`}},
+		{`new my\ notes ""`, []string{"new", "my notes", ""}},
+		{"", nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			got, err := alias.Split(tt.input)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+
+	for _, input := range []string{`new "notes`, `new 'notes`, `new notes\`} {
+		_, err := alias.Split(input)
+		require.ErrorIs(t, err, alias.ErrInvalid, input)
+	}
+}
+
+func TestExpand(t *testing.T) {
+	aliases := map[string]string{
+		"bug":   "new --ext md bug-report",
+		"b":     "bug --encrypt",
+		"new":   "new --encrypt",
+		"loop":  "again",
+		"again": "loop x",
+		"self":  "self",
+		"bad":   `show "@latest`,
+		"none":  " ",
+	}
+	builtin := func(name string) bool { return name == "new" || name == "show" }
+
+	tests := []struct {
+		name    string
+		want    []string
+		aliased bool
+	}{
+		{"bug", []string{"new", "--ext", "md", "bug-report"}, true},
+		{"b", []string{"new", "--ext", "md", "bug-report", "--encrypt"}, true},
+		// Built-in commands cannot be redefined.
+		{"new", []string{"new"}, false},
+		{"grep", []string{"grep"}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, aliased, err := alias.Expand(tt.name, aliases, builtin)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+			assert.Equal(t, tt.aliased, aliased)
+		})
+	}
+
+	_, _, err := alias.Expand("loop", aliases, builtin)
+	require.ErrorIs(t, err, alias.ErrRecursive)
+	assert.Contains(t, err.Error(), "loop -> again -> loop")
+	_, _, err = alias.Expand("self", aliases, builtin)
+	require.ErrorIs(t, err, alias.ErrRecursive)
+
+	for _, name := range []string{"bad", "none"} {
+		_, _, err = alias.Expand(name, aliases, builtin)
+		require.ErrorIs(t, err, alias.ErrInvalid, name)
+	}
+}
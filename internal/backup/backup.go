@@ -0,0 +1,329 @@
+// Package backup writes, verifies and restores snapshot archives of the memo root.
+//
+// An archive is a gzip-compressed tar file holding the files of the memo root under memo/,
+// followed by a manifest.json listing the path, size and SHA-256 hash of each of them.
+// The manifest makes an archive verifiable on its own, without the memo root it was taken from.
+package backup
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"path"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/sushichan044/memo-cli/internal/memofs"
+)
+
+const (
+	// ManifestName is the name of the manifest inside an archive.
+	ManifestName = "manifest.json"
+	// filesDir is the directory holding the memo root inside an archive.
+	filesDir = "memo"
+
+	manifestVersion = 1
+	// fileNameLayout timestamps archive file names so that they sort chronologically.
+	fileNameLayout = "20060102-150405"
+)
+
+var (
+	// ErrInvalidArchive is returned when an archive is not a memo backup or is malformed.
+	ErrInvalidArchive = errors.New("invalid backup archive")
+	// ErrConflict is returned by Restore when restoring would overwrite files that differ from the archive.
+	ErrConflict = errors.New("restore would overwrite changed files")
+)
+
+// Manifest lists the files stored in an archive.
+type Manifest struct {
+	Version   int       `json:"version"`
+	CreatedAt time.Time `json:"created_at"`
+	Files     []File    `json:"files"`
+}
+
+// File describes a file of the memo root.
+type File struct {
+	// Path is slash-separated and relative to the memo root.
+	Path   string      `json:"path"`
+	Size   int64       `json:"size"`
+	SHA256 string      `json:"sha256"`
+	Mode   fs.FileMode `json:"mode"`
+}
+
+// Problem is a difference between an archive and its manifest.
+type Problem struct {
+	Path   string
+	Reason string
+}
+
+func (p Problem) String() string {
+	return p.Path + ": " + p.Reason
+}
+
+// FileName returns the archive file name for a backup taken at t (e.g. "memo-20251031-143045.tar.gz").
+func FileName(t time.Time) string {
+	return "memo-" + t.Format(fileNameLayout) + ".tar.gz"
+}
+
+// Write archives every regular file in fsys into w and returns the manifest,
+// which is stored as the last entry of the archive.
+// Symbolic links and other special files are skipped, and so are the files and directories for which
+// skip, if not nil, returns true. A missing memo root yields an empty archive.
+func Write(fsys memofs.FS, w io.Writer, now time.Time, skip func(name string) bool) (*Manifest, error) {
+	manifest := &Manifest{Version: manifestVersion, CreatedAt: now.UTC()}
+
+	gz := gzip.NewWriter(w)
+	tw := tar.NewWriter(gz)
+
+	err := fs.WalkDir(fsys, ".", func(name string, d fs.DirEntry, walkErr error) error {
+		if walkErr != nil {
+			if name == "." && errors.Is(walkErr, fs.ErrNotExist) {
+				return fs.SkipAll
+			}
+			return walkErr
+		}
+		if name != "." && skip != nil && skip(name) {
+			if d.IsDir() {
+				return fs.SkipDir
+			}
+			return nil
+		}
+		if !d.Type().IsRegular() {
+			return nil
+		}
+
+		data, err := fsys.ReadFile(name)
+		if err != nil {
+			return err
+		}
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+
+		file := File{Path: name, Size: int64(len(data)), SHA256: hash(data), Mode: info.Mode().Perm()}
+		manifest.Files = append(manifest.Files, file)
+
+		return writeEntry(tw, path.Join(filesDir, name), data, file.Mode, info.ModTime())
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to back up memo directory: %w", err)
+	}
+
+	manifestData, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	if writeErr := writeEntry(tw, ManifestName, manifestData, 0o600, manifest.CreatedAt); writeErr != nil {
+		return nil, writeErr
+	}
+
+	if closeErr := tw.Close(); closeErr != nil {
+		return nil, fmt.Errorf("failed to write archive: %w", closeErr)
+	}
+	if closeErr := gz.Close(); closeErr != nil {
+		return nil, fmt.Errorf("failed to write archive: %w", closeErr)
+	}
+
+	return manifest, nil
+}
+
+func writeEntry(tw *tar.Writer, name string, data []byte, mode fs.FileMode, modTime time.Time) error {
+	header := &tar.Header{
+		Typeflag: tar.TypeReg,
+		Name:     name,
+		Size:     int64(len(data)),
+		Mode:     int64(mode.Perm()),
+		ModTime:  modTime,
+		Format:   tar.FormatPAX,
+	}
+	if err := tw.WriteHeader(header); err != nil {
+		return fmt.Errorf("failed to write archive: %w", err)
+	}
+	if _, err := tw.Write(data); err != nil {
+		return fmt.Errorf("failed to write archive: %w", err)
+	}
+
+	return nil
+}
+
+// Archive is a backup archive loaded into memory.
+type Archive struct {
+	Manifest Manifest
+
+	files map[string][]byte
+}
+
+// Read loads the archive from r.
+func Read(r io.Reader) (*Archive, error) {
+	gz, err := gzip.NewReader(r)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidArchive, err)
+	}
+	defer gz.Close()
+
+	archive := &Archive{files: map[string][]byte{}}
+	hasManifest := false
+
+	tr := tar.NewReader(gz)
+	for {
+		header, nextErr := tr.Next()
+		if errors.Is(nextErr, io.EOF) {
+			break
+		}
+		if nextErr != nil {
+			return nil, fmt.Errorf("%w: %w", ErrInvalidArchive, nextErr)
+		}
+		if header.Typeflag != tar.TypeReg {
+			continue
+		}
+
+		data, readErr := io.ReadAll(tr)
+		if readErr != nil {
+			return nil, fmt.Errorf("%w: %w", ErrInvalidArchive, readErr)
+		}
+
+		if header.Name == ManifestName {
+			if jsonErr := json.Unmarshal(data, &archive.Manifest); jsonErr != nil {
+				return nil, fmt.Errorf("%w: malformed manifest: %w", ErrInvalidArchive, jsonErr)
+			}
+			hasManifest = true
+			continue
+		}
+
+		name, ok := strings.CutPrefix(header.Name, filesDir+"/")
+		if !ok || !fs.ValidPath(name) {
+			return nil, fmt.Errorf("%w: unexpected entry %q", ErrInvalidArchive, header.Name)
+		}
+		archive.files[name] = data
+	}
+
+	if !hasManifest {
+		return nil, fmt.Errorf("%w: missing %s", ErrInvalidArchive, ManifestName)
+	}
+	for _, file := range archive.Manifest.Files {
+		if !fs.ValidPath(file.Path) {
+			return nil, fmt.Errorf("%w: invalid path %q in manifest", ErrInvalidArchive, file.Path)
+		}
+	}
+
+	return archive, nil
+}
+
+// Verify compares the archived files with the manifest.
+// It returns nil when every file is present with the recorded size and hash, and nothing else is.
+func (a *Archive) Verify() []Problem {
+	var problems []Problem
+	listed := map[string]bool{}
+
+	for _, file := range a.Manifest.Files {
+		listed[file.Path] = true
+
+		data, ok := a.files[file.Path]
+		switch {
+		case !ok:
+			problems = append(problems, Problem{Path: file.Path, Reason: "missing from archive"})
+		case int64(len(data)) != file.Size:
+			problems = append(problems, Problem{
+				Path:   file.Path,
+				Reason: fmt.Sprintf("size is %d, manifest says %d", len(data), file.Size),
+			})
+		case hash(data) != file.SHA256:
+			problems = append(problems, Problem{Path: file.Path, Reason: "SHA-256 does not match manifest"})
+		}
+	}
+
+	var extra []string
+	for name := range a.files {
+		if !listed[name] {
+			extra = append(extra, name)
+		}
+	}
+	slices.Sort(extra)
+	for _, name := range extra {
+		problems = append(problems, Problem{Path: name, Reason: "not listed in manifest"})
+	}
+
+	return problems
+}
+
+// RestoreOptions customizes Restore.
+type RestoreOptions struct {
+	// FilePerm and DirPerm are applied to restored files and created directories.
+	FilePerm fs.FileMode
+	DirPerm  fs.FileMode
+	// Force overwrites files whose content differs from the archive instead of failing with ErrConflict.
+	Force bool
+	// DryRun reports what would be restored without writing anything.
+	DryRun bool
+	// Skip excludes archived files from the restore when it returns true.
+	Skip func(name string) bool
+}
+
+// RestoreResult lists what Restore did, or would do, with each archived file.
+type RestoreResult struct {
+	// Restored are files that did not exist and were written.
+	Restored []string
+	// Unchanged are files that already exist with the archived content.
+	Unchanged []string
+	// Conflicts are files that exist with different content. They are overwritten only with Force.
+	Conflicts []string
+}
+
+// Restore writes the archived files into fsys. Files already identical are left alone.
+// The archive is verified first, and nothing is written if it is damaged or, without Force,
+// if any file would be overwritten; the returned result then lists the conflicts.
+func (a *Archive) Restore(fsys memofs.FS, opts RestoreOptions) (RestoreResult, error) {
+	if problems := a.Verify(); len(problems) > 0 {
+		return RestoreResult{}, fmt.Errorf("%w: %s", ErrInvalidArchive, problems[0])
+	}
+
+	var result RestoreResult
+	for _, file := range a.Manifest.Files {
+		if opts.Skip != nil && opts.Skip(file.Path) {
+			continue
+		}
+
+		current, err := fsys.ReadFile(file.Path)
+		switch {
+		case errors.Is(err, fs.ErrNotExist):
+			result.Restored = append(result.Restored, file.Path)
+		case err != nil:
+			return RestoreResult{}, fmt.Errorf("failed to read %s: %w", file.Path, err)
+		case hash(current) == file.SHA256:
+			result.Unchanged = append(result.Unchanged, file.Path)
+		default:
+			result.Conflicts = append(result.Conflicts, file.Path)
+		}
+	}
+
+	if len(result.Conflicts) > 0 && !opts.Force {
+		return result, fmt.Errorf("%w: %d file(s) differ; use --force to overwrite them", ErrConflict, len(result.Conflicts))
+	}
+	if opts.DryRun {
+		return result, nil
+	}
+
+	for _, name := range slices.Concat(result.Restored, result.Conflicts) {
+		if err := fsys.MkdirAll(path.Dir(name), opts.DirPerm); err != nil {
+			return result, fmt.Errorf("failed to restore %s: %w", name, err)
+		}
+		if err := fsys.WriteFile(name, a.files[name], opts.FilePerm); err != nil {
+			return result, fmt.Errorf("failed to restore %s: %w", name, err)
+		}
+	}
+
+	return result, nil
+}
+
+func hash(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
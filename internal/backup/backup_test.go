@@ -0,0 +1,223 @@
+package backup_test
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"io"
+	"path"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/sushichan044/memo-cli/internal/backup"
+	"github.com/sushichan044/memo-cli/internal/memofs"
+)
+
+func newMemoFS(t *testing.T, files map[string]string) *memofs.Mem {
+	t.Helper()
+
+	fsys := memofs.NewMem("/memo")
+	for name, content := range files {
+		require.NoError(t, fsys.MkdirAll(path.Dir(name), 0o700))
+		require.NoError(t, fsys.WriteFile(name, []byte(content), 0o600))
+	}
+
+	return fsys
+}
+
+func writeArchive(t *testing.T, fsys memofs.FS) []byte {
+	t.Helper()
+
+	var buf bytes.Buffer
+	_, err := backup.Write(fsys, &buf, time.Date(2025, 10, 31, 14, 30, 45, 0, time.UTC), nil)
+	require.NoError(t, err)
+
+	return buf.Bytes()
+}
+
+func TestFileName(t *testing.T) {
+	assert.Equal(t, "memo-20251031-143045.tar.gz", backup.FileName(time.Date(2025, 10, 31, 14, 30, 45, 0, time.UTC)))
+}
+
+func TestWriteRead_RoundTrip(t *testing.T) {
+	fsys := newMemoFS(t, map[string]string{
+		"20251031/14-30-45-notes.md":     "hello\n",
+		"20251030/09-00-00.md.age":       "ciphertext",
+		".git/objects/ab/cdef0123456789": "history",
+	})
+
+	archive, err := backup.Read(bytes.NewReader(writeArchive(t, fsys)))
+	require.NoError(t, err)
+
+	assert.Equal(t, 1, archive.Manifest.Version)
+	require.Len(t, archive.Manifest.Files, 3)
+	assert.Equal(t, ".git/objects/ab/cdef0123456789", archive.Manifest.Files[0].Path)
+	assert.Equal(t, int64(6), archive.Manifest.Files[2].Size)
+	assert.Equal(t, "5891b5b522d5df086d0ff0b110fbd9d21bb4fc7163af34d08286a2e846f6be03", archive.Manifest.Files[2].SHA256)
+	assert.Empty(t, archive.Verify())
+}
+
+func TestWrite_Skip(t *testing.T) {
+	fsys := newMemoFS(t, map[string]string{
+		"20251031/14-30-45-notes.md":     "hello\n",
+		".git/objects/ab/cdef0123456789": "history",
+		".github/notes.md":               "kept",
+	})
+
+	var buf bytes.Buffer
+	manifest, err := backup.Write(fsys, &buf, time.Now(), func(name string) bool {
+		return name == ".git" || strings.HasPrefix(name, ".git/")
+	})
+	require.NoError(t, err)
+
+	paths := make([]string, 0, len(manifest.Files))
+	for _, file := range manifest.Files {
+		paths = append(paths, file.Path)
+	}
+	assert.Equal(t, []string{".github/notes.md", "20251031/14-30-45-notes.md"}, paths)
+}
+
+func TestRead_Invalid(t *testing.T) {
+	_, err := backup.Read(bytes.NewReader([]byte("not a gzip stream")))
+	require.ErrorIs(t, err, backup.ErrInvalidArchive)
+
+	// A tarball without a manifest is not a memo backup.
+	var buf bytes.Buffer
+	gz := gzip.NewWriter(&buf)
+	tw := tar.NewWriter(gz)
+	require.NoError(t, tw.WriteHeader(&tar.Header{Name: "memo/a.md", Mode: 0o600, Typeflag: tar.TypeReg}))
+	require.NoError(t, tw.Close())
+	require.NoError(t, gz.Close())
+
+	_, err = backup.Read(&buf)
+	require.ErrorIs(t, err, backup.ErrInvalidArchive)
+}
+
+func TestVerify_DetectsTampering(t *testing.T) {
+	fsys := newMemoFS(t, map[string]string{"20251031/14-30-45-notes.md": "hello\n"})
+	data := writeArchive(t, fsys)
+
+	// Re-pack the archive with modified file content but the original manifest.
+	tampered := repack(t, data, func(name string, content []byte) []byte {
+		if name == "memo/20251031/14-30-45-notes.md" {
+			return []byte("HELLO\n")
+		}
+		return content
+	})
+
+	archive, err := backup.Read(bytes.NewReader(tampered))
+	require.NoError(t, err)
+
+	problems := archive.Verify()
+	require.Len(t, problems, 1)
+	assert.Equal(t, "20251031/14-30-45-notes.md", problems[0].Path)
+	assert.Contains(t, problems[0].Reason, "SHA-256")
+
+	_, err = archive.Restore(memofs.NewMem("/restore"), backup.RestoreOptions{FilePerm: 0o600, DirPerm: 0o700})
+	require.ErrorIs(t, err, backup.ErrInvalidArchive)
+}
+
+func TestRestore_ConflictDetection(t *testing.T) {
+	source := newMemoFS(t, map[string]string{
+		"20251031/14-30-45-notes.md": "hello\n",
+		"20251031/15-00-00-todo.md":  "- [ ] ship\n",
+		"20251030/09-00-00.md":       "old\n",
+	})
+	archive, err := backup.Read(bytes.NewReader(writeArchive(t, source)))
+	require.NoError(t, err)
+
+	target := newMemoFS(t, map[string]string{
+		"20251031/14-30-45-notes.md": "hello\n",
+		"20251031/15-00-00-todo.md":  "- [x] ship\n",
+	})
+	opts := backup.RestoreOptions{FilePerm: 0o600, DirPerm: 0o700}
+
+	result, err := archive.Restore(target, opts)
+	require.ErrorIs(t, err, backup.ErrConflict)
+	assert.Equal(t, []string{"20251031/15-00-00-todo.md"}, result.Conflicts)
+	// Nothing is written when restoring would overwrite changes.
+	_, statErr := target.Stat("20251030/09-00-00.md")
+	require.Error(t, statErr)
+
+	opts.Force = true
+	result, err = archive.Restore(target, opts)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"20251030/09-00-00.md"}, result.Restored)
+	assert.Equal(t, []string{"20251031/14-30-45-notes.md"}, result.Unchanged)
+
+	content, err := target.ReadFile("20251031/15-00-00-todo.md")
+	require.NoError(t, err)
+	assert.Equal(t, "- [ ] ship\n", string(content))
+}
+
+func TestRestore_DryRun(t *testing.T) {
+	source := newMemoFS(t, map[string]string{"20251031/14-30-45-notes.md": "hello\n"})
+	archive, err := backup.Read(bytes.NewReader(writeArchive(t, source)))
+	require.NoError(t, err)
+
+	target := memofs.NewMem("/restore")
+	result, err := archive.Restore(target, backup.RestoreOptions{DryRun: true})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"20251031/14-30-45-notes.md"}, result.Restored)
+
+	_, statErr := target.Stat("20251031")
+	require.Error(t, statErr)
+}
+
+// repack rewrites every entry of a backup archive through edit.
+func repack(t *testing.T, data []byte, edit func(name string, content []byte) []byte) []byte {
+	t.Helper()
+
+	gr, err := gzip.NewReader(bytes.NewReader(data))
+	require.NoError(t, err)
+	tr := tar.NewReader(gr)
+
+	var buf bytes.Buffer
+	gw := gzip.NewWriter(&buf)
+	tw := tar.NewWriter(gw)
+	for {
+		header, nextErr := tr.Next()
+		if nextErr == io.EOF {
+			break
+		}
+		require.NoError(t, nextErr)
+
+		content, readErr := io.ReadAll(tr)
+		require.NoError(t, readErr)
+		content = edit(header.Name, content)
+
+		header.Size = int64(len(content))
+		require.NoError(t, tw.WriteHeader(header))
+		_, writeErr := tw.Write(content)
+		require.NoError(t, writeErr)
+	}
+	require.NoError(t, tw.Close())
+	require.NoError(t, gw.Close())
+
+	return buf.Bytes()
+}
+
+func TestRestore_Skip(t *testing.T) {
+	source := newMemoFS(t, map[string]string{
+		"20251031/14-30-45-notes.md": "hello\n",
+		".git/HEAD":                  "ref: refs/heads/main\n",
+	})
+	archive, err := backup.Read(bytes.NewReader(writeArchive(t, source)))
+	require.NoError(t, err)
+
+	target := memofs.NewMem("/restore")
+	result, err := archive.Restore(target, backup.RestoreOptions{
+		FilePerm: 0o600,
+		DirPerm:  0o700,
+		Skip:     func(name string) bool { return strings.HasPrefix(name, ".git/") },
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"20251031/14-30-45-notes.md"}, result.Restored)
+
+	_, statErr := target.Stat(".git")
+	require.Error(t, statErr)
+}
@@ -0,0 +1,264 @@
+// Package complete completes memo command lines for shell completion scripts. The scripts call back into
+// memo with the words typed so far, and the candidates are computed from the kong command tree, so that
+// they always match the commands and flags of the running binary.
+package complete
+
+import (
+	"embed"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/alecthomas/kong"
+)
+
+// FilesDirective is printed after the candidates when the shell should complete file paths instead.
+const FilesDirective = ":files"
+
+// PredictorTag is the struct tag naming the predictor of a flag or positional argument, e.g. predictor:"ref".
+const PredictorTag = "predictor"
+
+// Shells lists the shells completion scripts are available for.
+var Shells = []string{"bash", "zsh", "fish"} //nolint:gochecknoglobals // read-only list of supported shells
+
+//go:embed scripts
+var scripts embed.FS
+
+// Script returns the completion script for shell.
+func Script(shell string) (string, error) {
+	if !slices.Contains(Shells, shell) {
+		return "", fmt.Errorf("unsupported shell %q: must be one of %s", shell, strings.Join(Shells, ", "))
+	}
+	data, err := scripts.ReadFile("scripts/memo." + shell)
+	if err != nil {
+		return "", err
+	}
+	return string(data), nil
+}
+
+// Candidate is a possible completion, with a description shown by shells that support them.
+type Candidate struct {
+	Value       string
+	Description string
+}
+
+// Predictor returns the values of a flag or argument that cannot be known from the command tree,
+// such as memo names.
+type Predictor func() []string
+
+// Completer completes command lines of a kong application.
+type Completer struct {
+	App *kong.Application
+	// Predictors are looked up by the predictor tag of flags and positional arguments.
+	Predictors map[string]Predictor
+	// Commands returns commands that are not part of App, such as aliases and plugins,
+	// completed along with the top-level commands.
+	Commands func() []Candidate
+}
+
+// Result is the outcome of a completion.
+type Result struct {
+	Candidates []Candidate
+	// Files reports that the word is a file path, left to the shell to complete.
+	Files bool
+}
+
+// Complete returns the completions of the last of args, the word under the cursor, which may be empty.
+// The other args are the words before it, without the program name.
+func (c *Completer) Complete(args []string) Result {
+	if len(args) == 0 {
+		args = []string{""}
+	}
+	words, current := args[:len(args)-1], args[len(args)-1]
+
+	s := state{node: c.App.Node}
+	for i := 0; i < len(words); i++ {
+		flag := s.consume(words[i])
+		if flag == nil {
+			continue
+		}
+		if i+1 == len(words) {
+			return c.value(flag.Value, current, "")
+		}
+		i++ // the value of flag
+	}
+
+	switch {
+	case !s.dashDash && strings.HasPrefix(current, "--") && strings.Contains(current, "="):
+		name, value, _ := strings.Cut(current, "=")
+		flag := s.flag(strings.TrimPrefix(name, "--"))
+		if flag == nil {
+			return Result{}
+		}
+		return c.value(flag.Value, value, name+"=")
+	case !s.dashDash && strings.HasPrefix(current, "-"):
+		return Result{Candidates: filter(s.flags(), current)}
+	case len(s.node.Children) > 0 && s.positional == 0:
+		candidates := commands(s.node)
+		if s.node == c.App.Node && c.Commands != nil {
+			candidates = append(candidates, c.Commands()...)
+		}
+		return Result{Candidates: filter(candidates, current)}
+	}
+
+	positionals := s.node.Positional
+	if len(positionals) == 0 {
+		return Result{}
+	}
+	arg := positionals[min(s.positional, len(positionals)-1)]
+	if s.positional >= len(positionals) && !arg.IsCumulative() {
+		return Result{}
+	}
+	return c.value(arg, current, "")
+}
+
+// value completes the value of a flag or positional argument. Candidates are prefixed with prefix,
+// such as "--format=" for values typed within a flag.
+func (c *Completer) value(v *kong.Value, current, prefix string) Result {
+	var values []string
+	switch {
+	case v.Enum != "":
+		values = v.EnumSlice()
+	case v.Tag.Get(PredictorTag) != "":
+		if predict, ok := c.Predictors[v.Tag.Get(PredictorTag)]; ok {
+			values = predict()
+		}
+	case v.Tag.Type == "path" || v.Tag.Type == "existingfile" || v.Tag.Type == "existingdir":
+		return Result{Files: true}
+	}
+
+	candidates := make([]Candidate, 0, len(values))
+	for _, value := range values {
+		candidates = append(candidates, Candidate{Value: prefix + value})
+	}
+	return Result{Candidates: filter(candidates, prefix+current)}
+}
+
+// state tracks the command and the positional argument the words typed so far lead to.
+type state struct {
+	node *kong.Node
+	// positional counts the positional arguments given to node.
+	positional int
+	// dashDash reports whether "--" was typed, after which every word is a positional argument.
+	dashDash bool
+}
+
+// consume advances s past word, and returns the flag it names when that flag takes its value
+// from the next word.
+func (s *state) consume(word string) *kong.Flag {
+	switch {
+	case s.dashDash:
+		s.positional++
+		return nil
+	case word == "--":
+		s.dashDash = true
+		return nil
+	case strings.HasPrefix(word, "--"):
+		if strings.Contains(word, "=") {
+			return nil
+		}
+		return takesValue(s.flag(strings.TrimPrefix(word, "--")))
+	case strings.HasPrefix(word, "-") && len(word) > 1:
+		// Only a lone short flag takes the next word, not one combined with others as in -ie.
+		if runes := []rune(word); len(runes) == 2 {
+			return takesValue(s.short(runes[1]))
+		}
+		return nil
+	}
+
+	if s.positional == 0 {
+		if child := findChild(s.node, word); child != nil {
+			s.node = child
+			return nil
+		}
+	}
+	s.positional++
+	return nil
+}
+
+// takesValue returns flag if it takes a value, or nil for boolean and counter flags.
+func takesValue(flag *kong.Flag) *kong.Flag {
+	if flag == nil || flag.IsBool() || flag.IsCounter() {
+		return nil
+	}
+	return flag
+}
+
+// flags returns the visible flags of the current command and its parents, including those of its default
+// subcommand, which apply when no subcommand is given.
+func (s *state) flags() []Candidate {
+	var candidates []Candidate
+	for _, flag := range s.all() {
+		if flag.Hidden {
+			continue
+		}
+		candidates = append(candidates, Candidate{Value: "--" + flag.Name, Description: flag.Help})
+		if flag.Negated {
+			candidates = append(candidates, Candidate{Value: "--no-" + flag.Name, Description: flag.Help})
+		}
+	}
+	return candidates
+}
+
+func (s *state) all() []*kong.Flag {
+	var flags []*kong.Flag
+	for _, group := range s.node.AllFlags(true) {
+		flags = append(flags, group...)
+	}
+	if s.node.DefaultCmd != nil && s.positional == 0 {
+		flags = append(flags, s.node.DefaultCmd.Flags...)
+	}
+	return flags
+}
+
+func (s *state) flag(name string) *kong.Flag {
+	for _, flag := range s.all() {
+		if flag.Name == name || slices.Contains(flag.Aliases, name) {
+			return flag
+		}
+	}
+	return nil
+}
+
+func (s *state) short(r rune) *kong.Flag {
+	for _, flag := range s.all() {
+		if flag.Short == r {
+			return flag
+		}
+	}
+	return nil
+}
+
+// findChild returns the subcommand of node named name, or nil.
+func findChild(node *kong.Node, name string) *kong.Node {
+	for _, child := range node.Children {
+		if child.Type == kong.CommandNode && (child.Name == name || slices.Contains(child.Aliases, name)) {
+			return child
+		}
+	}
+	return nil
+}
+
+// commands returns the visible subcommands of node.
+func commands(node *kong.Node) []Candidate {
+	var candidates []Candidate
+	for _, child := range node.Children {
+		if child.Type == kong.CommandNode && !child.Hidden {
+			candidates = append(candidates, Candidate{Value: child.Name, Description: child.Help})
+		}
+	}
+	return candidates
+}
+
+// filter returns the candidates starting with prefix, without duplicates.
+func filter(candidates []Candidate, prefix string) []Candidate {
+	var out []Candidate
+	seen := make(map[string]bool)
+	for _, candidate := range candidates {
+		if strings.HasPrefix(candidate.Value, prefix) && !seen[candidate.Value] {
+			seen[candidate.Value] = true
+			out = append(out, candidate)
+		}
+	}
+	return out
+}
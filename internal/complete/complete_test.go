@@ -0,0 +1,109 @@
+package complete_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/alecthomas/kong"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/sushichan044/memo-cli/internal/complete"
+)
+
+type cli struct {
+	Format string `help:"Output format" enum:"text,json" default:"text"`
+
+	New struct {
+		Name    string `arg:"" optional:""`
+		Ext     string `                   help:"Extension" short:"e" predictor:"ext"`
+		Encrypt bool   `                   help:"Encrypt"`
+	} `cmd:"" help:"Create a memo."`
+	Show struct {
+		Ref string `arg:"" predictor:"ref"`
+	} `cmd:"" help:"Print a memo."`
+	Tasks struct {
+		List struct {
+			Tag string `predictor:"tag"`
+		} `cmd:"" default:"withargs"`
+		Done struct {
+			ID string `arg:""`
+		} `cmd:""`
+	} `cmd:"" help:"List tasks."`
+	Backup struct {
+		Dest string `type:"path"`
+	} `cmd:""`
+	Secret struct{} `cmd:"" hidden:""`
+}
+
+func completer(t *testing.T) *complete.Completer {
+	t.Helper()
+	parser, err := kong.New(&cli{}, kong.Name("memo"))
+	require.NoError(t, err)
+	return &complete.Completer{
+		App: parser.Model,
+		Predictors: map[string]complete.Predictor{
+			"ref": func() []string { return []string{"@latest", "standup", "retro"} },
+			"tag": func() []string { return []string{"work", "home"} },
+			"ext": func() []string { return []string{"md", "txt"} },
+		},
+		Commands: func() []complete.Candidate { return []complete.Candidate{{Value: "bug", Description: "alias"}} },
+	}
+}
+
+func values(result complete.Result) []string {
+	var out []string
+	for _, candidate := range result.Candidates {
+		out = append(out, candidate.Value)
+	}
+	return out
+}
+
+func TestComplete(t *testing.T) {
+	c := completer(t)
+
+	tests := []struct {
+		line string
+		want []string
+	}{
+		{"", []string{"new", "show", "tasks", "backup", "bug"}},
+		{"s", []string{"show"}},
+		{"--format json s", []string{"show"}},
+		{"--format ", []string{"text", "json"}},
+		{"--format=j", []string{"--format=json"}},
+		{"--f", []string{"--format"}},
+		{"new --e", []string{"--ext", "--encrypt"}},
+		{"new --ext ", []string{"md", "txt"}},
+		{"new -e t", []string{"txt"}},
+		{"new --encrypt notes --ext=", []string{"--ext=md", "--ext=txt"}},
+		{"new notes ", nil},
+		{"show ", []string{"@latest", "standup", "retro"}},
+		{"show @", []string{"@latest"}},
+		{"show retro ", nil},
+		{"tasks ", []string{"list", "done"}},
+		{"tasks --tag ", []string{"work", "home"}},
+		{"tasks list --tag h", []string{"home"}},
+		{"unknown ", nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.line, func(t *testing.T) {
+			result := c.Complete(strings.Split(tt.line, " "))
+			assert.Equal(t, tt.want, values(result))
+			assert.False(t, result.Files)
+		})
+	}
+
+	assert.True(t, c.Complete([]string{"backup", "--dest", ""}).Files)
+	assert.Equal(t, "Create a memo.", c.Complete([]string{"n"}).Candidates[0].Description)
+}
+
+func TestScript(t *testing.T) {
+	for _, shell := range complete.Shells {
+		script, err := complete.Script(shell)
+		require.NoError(t, err)
+		assert.Contains(t, script, "__complete --", shell)
+	}
+
+	_, err := complete.Script("powershell")
+	require.Error(t, err)
+}
@@ -0,0 +1,43 @@
+# bash completion for memo. Load it with:
+#   source <(memo completion bash)
+
+_memo() {
+    local cur words cword
+    if declare -F _get_comp_words_by_ref >/dev/null; then
+        _get_comp_words_by_ref -n =: cur words cword
+    else
+        # Rejoin --flag=value, which bash splits around the "=".
+        words=()
+        local i
+        for ((i = 0; i <= COMP_CWORD; i++)); do
+            if ((i > 1)) && [[ ${COMP_WORDS[i]} == = || ${COMP_WORDS[i-1]} == = ]]; then
+                words[${#words[@]}-1]+=${COMP_WORDS[i]}
+            else
+                words+=("${COMP_WORDS[i]}")
+            fi
+        done
+        cword=$((${#words[@]} - 1))
+        cur=${words[cword]}
+    fi
+
+    local out
+    out=$("${words[0]}" __complete -- "${words[@]:1:cword}" 2>/dev/null) || return
+
+    COMPREPLY=()
+    local line
+    while IFS= read -r line; do
+        if [[ $line == :files ]]; then
+            compopt -o default 2>/dev/null
+            COMPREPLY=()
+            return
+        fi
+        line=${line%%$'\t'*}
+        # Bash completes the part of --flag=value after the "=" as a word of its own.
+        if [[ $cur == *=* && $COMP_WORDBREAKS == *=* ]]; then
+            line=${line#"${cur%%=*}="}
+        fi
+        [[ -n $line ]] && COMPREPLY+=("$line")
+    done <<<"$out"
+}
+
+complete -F _memo memo
@@ -0,0 +1,16 @@
+# fish completion for memo. Load it with:
+#   memo completion fish | source
+# or save it as ~/.config/fish/completions/memo.fish.
+
+function __memo_complete
+    set -l words (commandline -opc)
+    set -l current (commandline -ct)
+    set -l out ($words[1] __complete -- $words[2..-1] "$current" 2>/dev/null)
+    if contains -- :files $out
+        __fish_complete_path "$current"
+        return
+    end
+    printf '%s\n' $out
+end
+
+complete -c memo -f -a '(__memo_complete)'
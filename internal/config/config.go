@@ -1,10 +1,16 @@
 package config
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
 	"errors"
+	"fmt"
 	"os"
 	"os/user"
 	"path/filepath"
+	"time"
+
+	"github.com/sushichan044/memo-cli/internal/xdg"
 )
 
 const (
@@ -12,26 +18,117 @@ const (
 	fallbackMemoDir = "memo"
 
 	memoRootDirEnv = "MEMO_ROOT_DIR"
+
+	// DefaultFileMode keeps memos readable by their owner only.
+	DefaultFileMode os.FileMode = 0o600
+	// DefaultDirMode keeps the memo tree traversable by its owner only.
+	DefaultDirMode os.FileMode = 0o700
+	// DefaultHookTimeout bounds how long a hook may run.
+	DefaultHookTimeout = 30 * time.Second
 )
 
 // Config holds the configuration for the memo CLI.
 type Config struct {
 	// BaseDir is the base directory where memos are stored.
 	BaseDir string
+
+	// FileMode is the permission applied to memo files regardless of the process umask.
+	// Zero means DefaultFileMode.
+	FileMode os.FileMode
+	// DirMode is the permission applied to directories created under BaseDir regardless of the process umask.
+	// Zero means DefaultDirMode.
+	DirMode os.FileMode
+
+	// Encryption holds the age keys used for encrypted memos.
+	Encryption Encryption
+
+	// BackupDir is where `memo backup` writes archives. Empty means DefaultBackupDir.
+	BackupDir string
+
+	// Daily configures daily notes.
+	Daily Daily
+
+	// Hooks are commands run around memo operations.
+	Hooks Hooks
+
+	// Aliases maps command names to the arguments they stand for, split like a shell command line,
+	// e.g. "bug" to "new --ext md bug-report".
+	Aliases map[string]string
+}
+
+// Hooks configures commands run around memo operations. Hooks left zero are not run.
+type Hooks struct {
+	PreCreate  Hook
+	PostCreate Hook
+	PostEdit   Hook
+	PreDelete  Hook
+	// Timeout bounds how long a hook may run. Zero means DefaultHookTimeout.
+	Timeout time.Duration
+}
+
+// Hook is a command: a shell command line, or an executable and its arguments.
+type Hook struct {
+	// Shell is a command line run by sh -c (cmd /C on Windows).
+	Shell string
+	// Args are an executable and its arguments, run without a shell.
+	Args []string
+}
+
+// IsZero reports whether no command is configured.
+func (h Hook) IsZero() bool {
+	return h.Shell == "" && len(h.Args) == 0
+}
+
+// Daily configures daily notes created by `memo today`.
+type Daily struct {
+	// Template is the template file for new daily notes. Empty means daily.md in TemplateDir.
+	Template string
+	// CarryForward copies unchecked tasks of the previous daily note into new ones.
+	CarryForward bool
+}
+
+// Encryption configures age encryption of memos.
+type Encryption struct {
+	// Recipients are age public keys (age1...) that new encrypted memos are encrypted to.
+	Recipients []string
+	// RecipientsFile is a file of additional recipients, one per line.
+	RecipientsFile string
+	// IdentityFile is the age identity file used to decrypt memos.
+	IdentityFile string
 }
 
 // New creates a new Config instance.
 // It checks the MEMO_ROOT_DIR environment variable for custom base directory.
 // If not set, it uses the current directory with .{username}/memo structure.
 // If username cannot be determined, it falls back to .memo/memo.
+// Other settings are read from the configuration file returned by Path, if it exists.
 func New() (*Config, error) {
 	baseDir, err := getBaseDir()
 	if err != nil {
 		return nil, err
 	}
 
-	cleanBaseDir := filepath.Clean(baseDir)
-	return &Config{BaseDir: cleanBaseDir}, nil
+	cfg := &Config{
+		BaseDir:  filepath.Clean(baseDir),
+		FileMode: DefaultFileMode,
+		DirMode:  DefaultDirMode,
+	}
+
+	path, err := Path()
+	if err != nil {
+		return nil, err
+	}
+
+	fc, err := loadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	if applyErr := fc.apply(cfg); applyErr != nil {
+		return nil, fmt.Errorf("invalid config file %s: %w", path, applyErr)
+	}
+
+	return cfg, nil
 }
 
 func getBaseDir() (string, error) {
@@ -77,3 +174,77 @@ func (c *Config) GetIgnorePattern() (string, error) {
 
 	return filepath.ToSlash(relPath) + "/", nil
 }
+
+// FilePerm returns the permission for memo files, falling back to DefaultFileMode.
+func (c *Config) FilePerm() os.FileMode {
+	if c.FileMode == 0 {
+		return DefaultFileMode
+	}
+	return c.FileMode
+}
+
+// BackupPath returns the directory for backup archives, falling back to DefaultBackupDir.
+func (c *Config) BackupPath() (string, error) {
+	if c.BackupDir != "" {
+		return c.BackupDir, nil
+	}
+	return DefaultBackupDir()
+}
+
+// DefaultBackupDir returns $XDG_DATA_HOME/memo/backups.
+func DefaultBackupDir() (string, error) {
+	dataHome, err := xdg.DataHome()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dataHome, "memo", "backups"), nil
+}
+
+// IndexPath returns the search index file of the memo root, $XDG_DATA_HOME/memo/index/<hash of BaseDir>.gob.
+// The index lives outside the memo root so that it never ends up in backups or the history repository.
+func (c *Config) IndexPath() (string, error) {
+	dataHome, err := xdg.DataHome()
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256([]byte(filepath.Clean(c.BaseDir)))
+	return filepath.Join(dataHome, "memo", "index", hex.EncodeToString(sum[:8])+".gob"), nil
+}
+
+// TemplateDir returns the directory holding memo templates, $XDG_CONFIG_HOME/memo/templates.
+func TemplateDir() (string, error) {
+	configHome, err := xdg.ConfigHome()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(configHome, "memo", "templates"), nil
+}
+
+// DailyTemplatePath returns the template file for daily notes, which may not exist.
+func (c *Config) DailyTemplatePath() (string, error) {
+	if c.Daily.Template != "" {
+		return c.Daily.Template, nil
+	}
+
+	dir, err := TemplateDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "daily.md"), nil
+}
+
+// HookTimeout returns how long a hook may run, falling back to DefaultHookTimeout.
+func (c *Config) HookTimeout() time.Duration {
+	if c.Hooks.Timeout <= 0 {
+		return DefaultHookTimeout
+	}
+	return c.Hooks.Timeout
+}
+
+// DirPerm returns the permission for memo directories, falling back to DefaultDirMode.
+func (c *Config) DirPerm() os.FileMode {
+	if c.DirMode == 0 {
+		return DefaultDirMode
+	}
+	return c.DirMode
+}
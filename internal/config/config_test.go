@@ -1,9 +1,11 @@
 package config_test
 
 import (
+	"os"
 	"path/filepath"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/sushichan044/memo-cli/internal/config"
 )
@@ -125,3 +127,240 @@ func TestNew_Integration(t *testing.T) {
 		t.Errorf("BaseDir's parent should be a hidden directory, got %q", parentName)
 	}
 }
+
+func writeConfigFile(t *testing.T, content string) {
+	t.Helper()
+
+	path := filepath.Join(t.TempDir(), "config.toml")
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatalf("failed to write config file: %v", err)
+	}
+	t.Setenv("MEMO_CONFIG_FILE", path)
+}
+
+func TestNew_DefaultPermissions(t *testing.T) {
+	t.Setenv("MEMO_CONFIG_FILE", filepath.Join(t.TempDir(), "missing.toml"))
+
+	cfg, err := config.New()
+	if err != nil {
+		t.Fatalf("New() failed: %v", err)
+	}
+
+	if cfg.FileMode != config.DefaultFileMode {
+		t.Errorf("FileMode = %o; want %o", cfg.FileMode, config.DefaultFileMode)
+	}
+	if cfg.DirMode != config.DefaultDirMode {
+		t.Errorf("DirMode = %o; want %o", cfg.DirMode, config.DefaultDirMode)
+	}
+}
+
+func TestNew_ConfigFilePermissions(t *testing.T) {
+	writeConfigFile(t, `
+[permissions]
+file_mode = "0640"
+dir_mode = "0750"
+`)
+
+	cfg, err := config.New()
+	if err != nil {
+		t.Fatalf("New() failed: %v", err)
+	}
+
+	if cfg.FileMode != 0o640 {
+		t.Errorf("FileMode = %o; want 640", cfg.FileMode)
+	}
+	if cfg.DirMode != 0o750 {
+		t.Errorf("DirMode = %o; want 750", cfg.DirMode)
+	}
+}
+
+func TestNew_InvalidConfigFile(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+	}{
+		{"non octal mode", "[permissions]\nfile_mode = \"rw-------\"\n"},
+		{"mode with type bits", "[permissions]\ndir_mode = \"40700\"\n"},
+		{"file mode without owner access", "[permissions]\nfile_mode = \"0\"\n"},
+		{"dir mode without owner access", "[permissions]\ndir_mode = \"0070\"\n"},
+		{"malformed toml", "[permissions\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			writeConfigFile(t, tt.content)
+
+			if _, err := config.New(); err == nil {
+				t.Error("New() should fail with invalid config file")
+			}
+		})
+	}
+}
+
+func TestPermFallbacks(t *testing.T) {
+	cfg := &config.Config{}
+
+	if cfg.FilePerm() != config.DefaultFileMode {
+		t.Errorf("FilePerm() = %o; want %o", cfg.FilePerm(), config.DefaultFileMode)
+	}
+	if cfg.DirPerm() != config.DefaultDirMode {
+		t.Errorf("DirPerm() = %o; want %o", cfg.DirPerm(), config.DefaultDirMode)
+	}
+}
+
+func TestNew_ConfigFileEncryption(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	writeConfigFile(t, `
+[encryption]
+recipients = ["age1example"]
+recipients_file = "/etc/memo/recipients.txt"
+identity_file = "~/.config/memo/identity.txt"
+`)
+
+	cfg, err := config.New()
+	if err != nil {
+		t.Fatalf("New() failed: %v", err)
+	}
+
+	if len(cfg.Encryption.Recipients) != 1 || cfg.Encryption.Recipients[0] != "age1example" {
+		t.Errorf("Recipients = %v; want [age1example]", cfg.Encryption.Recipients)
+	}
+	if cfg.Encryption.RecipientsFile != "/etc/memo/recipients.txt" {
+		t.Errorf("RecipientsFile = %q", cfg.Encryption.RecipientsFile)
+	}
+	if want := filepath.Join(home, ".config", "memo", "identity.txt"); cfg.Encryption.IdentityFile != want {
+		t.Errorf("IdentityFile = %q; want %q", cfg.Encryption.IdentityFile, want)
+	}
+}
+
+func TestBackupPath(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("XDG_DATA_HOME", filepath.Join(home, "data"))
+	writeConfigFile(t, "")
+
+	cfg, err := config.New()
+	if err != nil {
+		t.Fatalf("New() failed: %v", err)
+	}
+	if got, _ := cfg.BackupPath(); got != filepath.Join(home, "data", "memo", "backups") {
+		t.Errorf("BackupPath() = %q; want default under XDG_DATA_HOME", got)
+	}
+
+	writeConfigFile(t, "[backup]\ndir = \"~/memo-backups\"\n")
+	cfg, err = config.New()
+	if err != nil {
+		t.Fatalf("New() failed: %v", err)
+	}
+	if got, _ := cfg.BackupPath(); got != filepath.Join(home, "memo-backups") {
+		t.Errorf("BackupPath() = %q; want configured directory", got)
+	}
+}
+
+func TestNew_ConfigFileHooks(t *testing.T) {
+	writeConfigFile(t, `[hooks]
+pre-create = "test -n \"$MEMO_NAME\""
+post-edit = ["prettier", "--write"]
+timeout = "5s"
+`)
+
+	cfg, err := config.New()
+	if err != nil {
+		t.Fatalf("New() failed: %v", err)
+	}
+	if cfg.Hooks.PreCreate.Shell != `test -n "$MEMO_NAME"` {
+		t.Errorf("PreCreate = %+v; want a shell command", cfg.Hooks.PreCreate)
+	}
+	if got := cfg.Hooks.PostEdit.Args; len(got) != 2 || got[0] != "prettier" {
+		t.Errorf("PostEdit = %+v; want an executable and its arguments", cfg.Hooks.PostEdit)
+	}
+	if !cfg.Hooks.PostCreate.IsZero() || !cfg.Hooks.PreDelete.IsZero() {
+		t.Errorf("Hooks = %+v; want unset hooks to be zero", cfg.Hooks)
+	}
+	if cfg.HookTimeout() != 5*time.Second {
+		t.Errorf("HookTimeout() = %v; want 5s", cfg.HookTimeout())
+	}
+
+	for _, content := range []string{
+		"[hooks]\ntimeout = \"soon\"\n",
+		"[hooks]\npost-edit = []\n",
+		"[hooks]\npost-edit = 1\n",
+	} {
+		writeConfigFile(t, content)
+		if _, err := config.New(); err == nil {
+			t.Errorf("New() with %q succeeded; want an error", content)
+		}
+	}
+}
+
+func TestNew_ConfigFileAliases(t *testing.T) {
+	writeConfigFile(t, `[alias]
+bug = "new --ext md bug-report"
+today = "daily"
+`)
+
+	cfg, err := config.New()
+	if err != nil {
+		t.Fatalf("New() failed: %v", err)
+	}
+	if len(cfg.Aliases) != 2 || cfg.Aliases["bug"] != "new --ext md bug-report" {
+		t.Errorf("Aliases = %v; want both aliases", cfg.Aliases)
+	}
+
+	for _, content := range []string{
+		"[alias]\n\"--bug\" = \"new\"\n",
+		"[alias]\n\"my bug\" = \"new\"\n",
+		"[alias]\nbug = [\"new\"]\n",
+	} {
+		writeConfigFile(t, content)
+		if _, err := config.New(); err == nil {
+			t.Errorf("New() with %q succeeded; want an error", content)
+		}
+	}
+}
+
+func TestIndexPath(t *testing.T) {
+	t.Setenv("XDG_DATA_HOME", "/data")
+
+	a, err := (&config.Config{BaseDir: "/work/a/memo"}).IndexPath()
+	if err != nil {
+		t.Fatalf("IndexPath() failed: %v", err)
+	}
+	b, _ := (&config.Config{BaseDir: "/work/b/memo/"}).IndexPath()
+	again, _ := (&config.Config{BaseDir: "/work/a/memo/"}).IndexPath()
+
+	if filepath.Dir(a) != filepath.Join("/data", "memo", "index") {
+		t.Errorf("IndexPath() = %q; want a file under XDG_DATA_HOME", a)
+	}
+	if a == b || a != again {
+		t.Errorf("IndexPath() = %q, %q, %q; want one index per memo root", a, b, again)
+	}
+}
+
+func TestNew_ConfigFileDaily(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("XDG_CONFIG_HOME", filepath.Join(home, "config"))
+	writeConfigFile(t, "")
+
+	cfg, err := config.New()
+	if err != nil {
+		t.Fatalf("New() failed: %v", err)
+	}
+	if got, _ := cfg.DailyTemplatePath(); got != filepath.Join(home, "config", "memo", "templates", "daily.md") {
+		t.Errorf("DailyTemplatePath() = %q; want daily.md in the template directory", got)
+	}
+
+	writeConfigFile(t, "[daily]\ntemplate = \"~/daily.tmpl\"\ncarry_forward = true\n")
+	cfg, err = config.New()
+	if err != nil {
+		t.Fatalf("New() failed: %v", err)
+	}
+	if got, _ := cfg.DailyTemplatePath(); got != filepath.Join(home, "daily.tmpl") {
+		t.Errorf("DailyTemplatePath() = %q; want configured template", got)
+	}
+	if !cfg.Daily.CarryForward {
+		t.Error("Daily.CarryForward = false; want true")
+	}
+}
@@ -0,0 +1,208 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/BurntSushi/toml"
+
+	"github.com/sushichan044/memo-cli/internal/xdg"
+)
+
+const configFileEnv = "MEMO_CONFIG_FILE"
+
+// fileConfig mirrors the on-disk TOML configuration file.
+type fileConfig struct {
+	Permissions permissionsConfig `toml:"permissions"`
+	Encryption  encryptionConfig  `toml:"encryption"`
+	Backup      backupConfig      `toml:"backup"`
+	Daily       dailyConfig       `toml:"daily"`
+	Hooks       hooksConfig       `toml:"hooks"`
+	Alias       map[string]string `toml:"alias"`
+}
+
+type permissionsConfig struct {
+	// FileMode and DirMode are octal strings such as "0600" so that they read naturally in TOML.
+	FileMode string `toml:"file_mode"`
+	DirMode  string `toml:"dir_mode"`
+}
+
+type encryptionConfig struct {
+	Recipients     []string `toml:"recipients"`
+	RecipientsFile string   `toml:"recipients_file"`
+	IdentityFile   string   `toml:"identity_file"`
+}
+
+type backupConfig struct {
+	Dir string `toml:"dir"`
+}
+
+type dailyConfig struct {
+	Template     string `toml:"template"`
+	CarryForward bool   `toml:"carry_forward"`
+}
+
+type hooksConfig struct {
+	PreCreate  hookCommand `toml:"pre-create"`
+	PostCreate hookCommand `toml:"post-create"`
+	PostEdit   hookCommand `toml:"post-edit"`
+	PreDelete  hookCommand `toml:"pre-delete"`
+	// Timeout is a duration such as "10s".
+	Timeout string `toml:"timeout"`
+}
+
+// hookCommand is a hook written either as a shell command line or as an array of an executable and its arguments.
+type hookCommand Hook
+
+func (h *hookCommand) UnmarshalTOML(v any) error {
+	switch v := v.(type) {
+	case string:
+		h.Shell = v
+		return nil
+	case []any:
+		for _, arg := range v {
+			s, ok := arg.(string)
+			if !ok {
+				return fmt.Errorf("invalid hook argument %v: must be a string", arg)
+			}
+			h.Args = append(h.Args, s)
+		}
+		if len(h.Args) == 0 {
+			return errors.New("invalid hook: the array must name an executable")
+		}
+		return nil
+	default:
+		return fmt.Errorf("invalid hook %v: must be a command line or an array of an executable and its arguments", v)
+	}
+}
+
+// Path returns the location of the configuration file.
+// MEMO_CONFIG_FILE takes precedence over $XDG_CONFIG_HOME/memo/config.toml.
+func Path() (string, error) {
+	if envPath := os.Getenv(configFileEnv); envPath != "" {
+		return filepath.Clean(envPath), nil
+	}
+
+	configHome, err := xdg.ConfigHome()
+	if err != nil {
+		return "", err
+	}
+
+	return filepath.Join(configHome, "memo", "config.toml"), nil
+}
+
+// loadFile reads the configuration file at path.
+// A missing file is not an error and yields the zero configuration.
+func loadFile(path string) (*fileConfig, error) {
+	var fc fileConfig
+	if _, err := toml.DecodeFile(path, &fc); err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return &fc, nil
+		}
+		return nil, fmt.Errorf("failed to read config file %s: %w", path, err)
+	}
+
+	return &fc, nil
+}
+
+// apply copies the values set in the file onto cfg.
+func (fc *fileConfig) apply(cfg *Config) error {
+	if fc.Permissions.FileMode != "" {
+		mode, err := parseMode(fc.Permissions.FileMode, 0o600)
+		if err != nil {
+			return fmt.Errorf("permissions.file_mode: %w", err)
+		}
+		cfg.FileMode = mode
+	}
+
+	if fc.Permissions.DirMode != "" {
+		mode, err := parseMode(fc.Permissions.DirMode, 0o700)
+		if err != nil {
+			return fmt.Errorf("permissions.dir_mode: %w", err)
+		}
+		cfg.DirMode = mode
+	}
+
+	cfg.Encryption = Encryption{
+		Recipients:     fc.Encryption.Recipients,
+		RecipientsFile: fc.Encryption.RecipientsFile,
+		IdentityFile:   fc.Encryption.IdentityFile,
+	}
+	for _, path := range []*string{&cfg.Encryption.RecipientsFile, &cfg.Encryption.IdentityFile} {
+		expanded, err := expandHome(*path)
+		if err != nil {
+			return fmt.Errorf("encryption: %w", err)
+		}
+		*path = expanded
+	}
+
+	backupDir, err := expandHome(fc.Backup.Dir)
+	if err != nil {
+		return fmt.Errorf("backup.dir: %w", err)
+	}
+	cfg.BackupDir = backupDir
+
+	template, err := expandHome(fc.Daily.Template)
+	if err != nil {
+		return fmt.Errorf("daily.template: %w", err)
+	}
+	cfg.Daily = Daily{Template: template, CarryForward: fc.Daily.CarryForward}
+
+	cfg.Hooks = Hooks{
+		PreCreate:  Hook(fc.Hooks.PreCreate),
+		PostCreate: Hook(fc.Hooks.PostCreate),
+		PostEdit:   Hook(fc.Hooks.PostEdit),
+		PreDelete:  Hook(fc.Hooks.PreDelete),
+	}
+	if fc.Hooks.Timeout != "" {
+		timeout, parseErr := time.ParseDuration(fc.Hooks.Timeout)
+		if parseErr != nil || timeout <= 0 {
+			return fmt.Errorf("hooks.timeout: invalid duration %q: must be positive, such as \"10s\"", fc.Hooks.Timeout)
+		}
+		cfg.Hooks.Timeout = timeout
+	}
+
+	for name := range fc.Alias {
+		if name == "" || strings.HasPrefix(name, "-") || strings.ContainsFunc(name, unicode.IsSpace) {
+			return fmt.Errorf("alias: invalid command name %q", name)
+		}
+	}
+	cfg.Aliases = fc.Alias
+
+	return nil
+}
+
+// expandHome resolves a leading "~/" to the current user's home directory.
+func expandHome(path string) (string, error) {
+	if !strings.HasPrefix(path, "~/") {
+		return path, nil
+	}
+
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+
+	return filepath.Join(home, path[2:]), nil
+}
+
+// parseMode parses an octal permission such as "0600". The mode must keep some of the owner bits,
+// so that a mode such as "0" cannot lock the owner out of their memos.
+func parseMode(s string, owner os.FileMode) (os.FileMode, error) {
+	mode, err := strconv.ParseUint(s, 8, 32)
+	if err != nil || mode&^uint64(fs.ModePerm) != 0 {
+		return 0, fmt.Errorf("invalid permission %q: must be an octal mode such as 0600", s)
+	}
+	if os.FileMode(mode)&owner == 0 {
+		return 0, fmt.Errorf("invalid permission %q: must give the owner access (%04o)", s, owner)
+	}
+
+	return os.FileMode(mode), nil
+}
@@ -0,0 +1,162 @@
+// Package crypt encrypts and decrypts memos at rest with age (https://age-encryption.org).
+package crypt
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"filippo.io/age"
+
+	"github.com/sushichan044/memo-cli/internal/config"
+	"github.com/sushichan044/memo-cli/internal/memofs"
+)
+
+// Suffix is appended to the memo file name of encrypted memos (e.g. "14-30-45-notes.md.age").
+const Suffix = ".age"
+
+var (
+	// ErrNoRecipients is returned when encrypting without any configured recipient.
+	ErrNoRecipients = errors.New("no age recipients configured: set encryption.recipients or encryption.recipients_file")
+	// ErrNoIdentity is returned when decrypting without a configured identity file.
+	ErrNoIdentity = errors.New("no age identity configured: set encryption.identity_file")
+)
+
+// IsEncrypted reports whether path names an encrypted memo.
+func IsEncrypted(path string) bool {
+	return strings.HasSuffix(path, Suffix)
+}
+
+// Keyring lazily loads the age keys described by the configuration.
+type Keyring struct {
+	config config.Encryption
+}
+
+// NewKeyring creates a new Keyring instance.
+func NewKeyring(cfg config.Encryption) *Keyring {
+	return &Keyring{config: cfg}
+}
+
+// Recipients parses the configured recipients and recipients file.
+func (k *Keyring) Recipients() ([]age.Recipient, error) {
+	var recipients []age.Recipient
+	for _, r := range k.config.Recipients {
+		recipient, err := age.ParseX25519Recipient(strings.TrimSpace(r))
+		if err != nil {
+			return nil, fmt.Errorf("invalid recipient %q: %w", r, err)
+		}
+		recipients = append(recipients, recipient)
+	}
+
+	if k.config.RecipientsFile != "" {
+		file, err := os.Open(k.config.RecipientsFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to open recipients file: %w", err)
+		}
+		defer file.Close()
+
+		fromFile, err := age.ParseRecipients(file)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse recipients file %s: %w", k.config.RecipientsFile, err)
+		}
+		recipients = append(recipients, fromFile...)
+	}
+
+	if len(recipients) == 0 {
+		return nil, ErrNoRecipients
+	}
+
+	return recipients, nil
+}
+
+// Identities parses the configured identity file.
+func (k *Keyring) Identities() ([]age.Identity, error) {
+	if k.config.IdentityFile == "" {
+		return nil, ErrNoIdentity
+	}
+
+	file, err := os.Open(k.config.IdentityFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open identity file: %w", err)
+	}
+	defer file.Close()
+
+	identities, err := age.ParseIdentities(file)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse identity file %s: %w", k.config.IdentityFile, err)
+	}
+
+	return identities, nil
+}
+
+// ReadFile returns the plaintext content of the memo name in fsys.
+// Encrypted memos are decrypted with the keyring's identities; other memos are read as is,
+// so callers do not need to care whether a memo is encrypted.
+func (k *Keyring) ReadFile(fsys memofs.FS, name string) ([]byte, error) {
+	data, err := fsys.ReadFile(name)
+	if err != nil {
+		return nil, err
+	}
+
+	if !IsEncrypted(name) {
+		return data, nil
+	}
+
+	plaintext, err := k.Decrypt(data)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", name, err)
+	}
+
+	return plaintext, nil
+}
+
+// WriteFile replaces the content of the encrypted memo name in fsys with data encrypted to the keyring's recipients.
+func (k *Keyring) WriteFile(fsys memofs.FS, name string, data []byte, perm os.FileMode) error {
+	recipients, err := k.Recipients()
+	if err != nil {
+		return err
+	}
+
+	var buf bytes.Buffer
+	if encryptErr := Encrypt(&buf, data, recipients...); encryptErr != nil {
+		return encryptErr
+	}
+
+	return fsys.WriteFile(name, buf.Bytes(), perm)
+}
+
+// Decrypt decrypts age ciphertext with the keyring's identities.
+func (k *Keyring) Decrypt(ciphertext []byte) ([]byte, error) {
+	identities, err := k.Identities()
+	if err != nil {
+		return nil, err
+	}
+
+	r, err := age.Decrypt(bytes.NewReader(ciphertext), identities...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decrypt: %w", err)
+	}
+
+	return io.ReadAll(r)
+}
+
+// Encrypt writes data encrypted to recipients into w.
+func Encrypt(w io.Writer, data []byte, recipients ...age.Recipient) error {
+	if len(recipients) == 0 {
+		return ErrNoRecipients
+	}
+
+	enc, err := age.Encrypt(w, recipients...)
+	if err != nil {
+		return fmt.Errorf("failed to encrypt: %w", err)
+	}
+
+	if _, writeErr := enc.Write(data); writeErr != nil {
+		return fmt.Errorf("failed to encrypt: %w", writeErr)
+	}
+
+	return enc.Close()
+}
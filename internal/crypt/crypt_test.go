@@ -0,0 +1,168 @@
+package crypt_test
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"filippo.io/age"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/sushichan044/memo-cli/internal/config"
+	"github.com/sushichan044/memo-cli/internal/crypt"
+	"github.com/sushichan044/memo-cli/internal/memofs"
+)
+
+const secretName = "14-30-45-secret.md.age"
+
+// newKeyring generates a fresh identity and returns a keyring configured with it.
+func newKeyring(t *testing.T) *crypt.Keyring {
+	t.Helper()
+
+	identity, err := age.GenerateX25519Identity()
+	require.NoError(t, err)
+
+	identityFile := filepath.Join(t.TempDir(), "identity.txt")
+	require.NoError(t, os.WriteFile(identityFile, []byte(identity.String()+"\n"), 0o600))
+
+	return crypt.NewKeyring(config.Encryption{
+		Recipients:   []string{identity.Recipient().String()},
+		IdentityFile: identityFile,
+	})
+}
+
+func writeEncrypted(t *testing.T, keyring *crypt.Keyring, content string) memofs.FS {
+	t.Helper()
+
+	fsys := memofs.NewMem("/memo")
+	require.NoError(t, keyring.WriteFile(fsys, secretName, []byte(content), 0o600))
+
+	return fsys
+}
+
+func TestIsEncrypted(t *testing.T) {
+	assert.True(t, crypt.IsEncrypted("20251031/14-30-45.md.age"))
+	assert.False(t, crypt.IsEncrypted("20251031/14-30-45.md"))
+}
+
+func TestKeyring_RoundTrip(t *testing.T) {
+	keyring := newKeyring(t)
+	fsys := writeEncrypted(t, keyring, "incident details")
+
+	raw, err := fsys.ReadFile(secretName)
+	require.NoError(t, err)
+	assert.NotContains(t, string(raw), "incident details", "content must be encrypted at rest")
+
+	content, err := keyring.ReadFile(fsys, secretName)
+	require.NoError(t, err)
+	assert.Equal(t, "incident details", string(content))
+}
+
+func TestKeyring_ReadFilePlaintext(t *testing.T) {
+	fsys := memofs.NewMem("/memo")
+	require.NoError(t, fsys.WriteFile("14-30-45.md", []byte("plain"), 0o600))
+
+	// Plain memos must be readable without any key configured.
+	content, err := crypt.NewKeyring(config.Encryption{}).ReadFile(fsys, "14-30-45.md")
+	require.NoError(t, err)
+	assert.Equal(t, "plain", string(content))
+}
+
+func TestKeyring_MissingKeys(t *testing.T) {
+	keyring := crypt.NewKeyring(config.Encryption{})
+
+	_, err := keyring.Recipients()
+	require.ErrorIs(t, err, crypt.ErrNoRecipients)
+
+	_, err = keyring.Identities()
+	require.ErrorIs(t, err, crypt.ErrNoIdentity)
+}
+
+func TestKeyring_RecipientsFile(t *testing.T) {
+	identity, err := age.GenerateX25519Identity()
+	require.NoError(t, err)
+
+	recipientsFile := filepath.Join(t.TempDir(), "recipients.txt")
+	content := "# team\n" + identity.Recipient().String() + "\n"
+	require.NoError(t, os.WriteFile(recipientsFile, []byte(content), 0o600))
+
+	recipients, err := crypt.NewKeyring(config.Encryption{RecipientsFile: recipientsFile}).Recipients()
+	require.NoError(t, err)
+	assert.Len(t, recipients, 1)
+}
+
+func TestKeyring_InvalidRecipient(t *testing.T) {
+	_, err := crypt.NewKeyring(config.Encryption{Recipients: []string{"not-a-key"}}).Recipients()
+	require.Error(t, err)
+}
+
+func TestKeyring_Edit(t *testing.T) {
+	keyring := newKeyring(t)
+	fsys := writeEncrypted(t, keyring, "before")
+
+	var plainPath string
+	changed, err := keyring.Edit(fsys, secretName, 0o600, func(p string) error {
+		plainPath = p
+
+		info, statErr := os.Stat(filepath.Dir(p))
+		require.NoError(t, statErr)
+		assert.Equal(t, os.FileMode(0o700), info.Mode().Perm(), "temp dir must be private")
+
+		return os.WriteFile(p, []byte("after"), 0o600)
+	})
+	require.NoError(t, err)
+	assert.True(t, changed)
+
+	assert.NoFileExists(t, plainPath, "decrypted copy must be removed")
+	assert.NoDirExists(t, filepath.Dir(plainPath))
+
+	content, err := keyring.ReadFile(fsys, secretName)
+	require.NoError(t, err)
+	assert.Equal(t, "after", string(content))
+}
+
+func TestKeyring_EditUnchanged(t *testing.T) {
+	keyring := newKeyring(t)
+	fsys := writeEncrypted(t, keyring, "same")
+
+	before, err := fsys.ReadFile(secretName)
+	require.NoError(t, err)
+
+	changed, err := keyring.Edit(fsys, secretName, 0o600, func(string) error { return nil })
+	require.NoError(t, err)
+	assert.False(t, changed)
+
+	after, err := fsys.ReadFile(secretName)
+	require.NoError(t, err)
+	assert.True(t, bytes.Equal(before, after), "unchanged memo must not be re-encrypted")
+}
+
+func TestKeyring_EditFailureRemovesPlaintext(t *testing.T) {
+	keyring := newKeyring(t)
+	fsys := writeEncrypted(t, keyring, "secret")
+
+	editorErr := errors.New("editor crashed")
+	var plainPath string
+	_, err := keyring.Edit(fsys, secretName, 0o600, func(p string) error {
+		plainPath = p
+		return editorErr
+	})
+	require.ErrorIs(t, err, editorErr)
+	assert.NoFileExists(t, plainPath)
+
+	content, err := keyring.ReadFile(fsys, secretName)
+	require.NoError(t, err)
+	assert.Equal(t, "secret", string(content))
+}
+
+func TestSecureRemoveAll(t *testing.T) {
+	dir, err := crypt.PrivateTempDir()
+	require.NoError(t, err)
+
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "plain.md"), []byte("secret"), 0o600))
+	require.NoError(t, crypt.SecureRemoveAll(dir))
+	assert.NoDirExists(t, dir)
+}
@@ -0,0 +1,141 @@
+package crypt
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"runtime"
+	"strings"
+
+	"github.com/sushichan044/memo-cli/internal/memofs"
+)
+
+const privateDirMode os.FileMode = 0o700
+
+// PrivateTempDir creates a directory accessible by the current user only,
+// used to hold decrypted memos while they are being edited.
+// On Linux it prefers the RAM-backed /dev/shm so that plaintext never reaches the disk.
+// The caller must remove it with SecureRemoveAll.
+func PrivateTempDir() (string, error) {
+	parent := ""
+	if runtime.GOOS == "linux" {
+		if info, err := os.Stat("/dev/shm"); err == nil && info.IsDir() {
+			parent = "/dev/shm"
+		}
+	}
+
+	dir, err := os.MkdirTemp(parent, "memo-")
+	if err != nil {
+		return "", err
+	}
+
+	// MkdirTemp already uses 0700, but be explicit regardless of the umask.
+	if chmodErr := os.Chmod(dir, privateDirMode); chmodErr != nil {
+		_ = os.RemoveAll(dir)
+		return "", chmodErr
+	}
+
+	return dir, nil
+}
+
+// SecureRemoveAll overwrites every regular file under dir with zeros before removing the tree.
+// Overwriting is best effort: journaling and copy-on-write filesystems may keep older blocks,
+// which is why PrivateTempDir prefers memory-backed storage.
+func SecureRemoveAll(dir string) error {
+	var errs []error
+	_ = filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			errs = append(errs, err)
+			return nil
+		}
+		if d.Type().IsRegular() {
+			errs = append(errs, overwrite(path))
+		}
+		return nil
+	})
+
+	errs = append(errs, os.RemoveAll(dir))
+
+	return errors.Join(errs...)
+}
+
+func overwrite(path string) error {
+	file, err := os.OpenFile(path, os.O_WRONLY, 0)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	info, err := file.Stat()
+	if err != nil {
+		return err
+	}
+
+	const chunkSize = 32 * 1024
+	zeros := make([]byte, chunkSize)
+	for remaining := info.Size(); remaining > 0; {
+		n := min(remaining, chunkSize)
+		if _, writeErr := file.Write(zeros[:n]); writeErr != nil {
+			return writeErr
+		}
+		remaining -= n
+	}
+
+	return file.Sync()
+}
+
+// Edit decrypts the memo name in fsys into a private temporary directory, lets edit modify the plaintext copy,
+// and re-encrypts it in place when the content changed.
+// The plaintext copy is securely removed afterwards, even when edit fails.
+// Reports whether the memo was rewritten.
+func (k *Keyring) Edit(fsys memofs.FS, name string, perm os.FileMode, edit func(plainPath string) error) (bool, error) {
+	plaintext, err := k.ReadFile(fsys, name)
+	if err != nil {
+		return false, err
+	}
+
+	// Check recipients before the user spends time editing.
+	if _, recipientsErr := k.Recipients(); recipientsErr != nil {
+		return false, recipientsErr
+	}
+
+	dir, err := PrivateTempDir()
+	if err != nil {
+		return false, fmt.Errorf("failed to create private directory: %w", err)
+	}
+
+	edited, err := editInDir(dir, strings.TrimSuffix(path.Base(name), Suffix), plaintext, edit)
+	if removeErr := SecureRemoveAll(dir); removeErr != nil {
+		err = errors.Join(err, fmt.Errorf("failed to remove decrypted copy in %s: %w", dir, removeErr))
+	}
+	if err != nil {
+		return false, err
+	}
+
+	if bytes.Equal(edited, plaintext) {
+		return false, nil
+	}
+
+	if writeErr := k.WriteFile(fsys, name, edited, perm); writeErr != nil {
+		return false, writeErr
+	}
+
+	return true, nil
+}
+
+func editInDir(dir, name string, plaintext []byte, edit func(plainPath string) error) ([]byte, error) {
+	plainPath := filepath.Join(dir, name)
+	if err := os.WriteFile(plainPath, plaintext, 0o600); err != nil {
+		return nil, err
+	}
+
+	if err := edit(plainPath); err != nil {
+		return nil, err
+	}
+
+	return os.ReadFile(plainPath)
+}
@@ -0,0 +1,222 @@
+// Package daily manages daily notes: a single running memo per day, stored as daily.md
+// in the date directory next to the other memos of that day.
+package daily
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"path"
+	"path/filepath"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/sushichan044/memo-cli/internal/hook"
+	"github.com/sushichan044/memo-cli/internal/markdown"
+	"github.com/sushichan044/memo-cli/internal/memo"
+	"github.com/sushichan044/memo-cli/internal/memofs"
+)
+
+const (
+	ext = "md"
+
+	// DefaultTemplate is used when no daily template file exists.
+	DefaultTemplate = "# {{.Date}} ({{.Weekday}})\n"
+
+	carriedHeading = "## Carried over"
+)
+
+// ErrInvalidDate is returned by ParseDate for dates it does not understand.
+var ErrInvalidDate = errors.New("invalid date")
+
+// Options customizes Open.
+type Options struct {
+	// Template is the text/template source of new daily notes. Empty means DefaultTemplate.
+	Template string
+	// CarryForward copies the unchecked tasks of the previous daily note into a new one.
+	CarryForward bool
+	// FilePerm and DirPerm are applied to the daily note and its date directory when they are created.
+	FilePerm fs.FileMode
+	DirPerm  fs.FileMode
+	// Hooks run the pre-create and post-create hooks around a new note. Nil runs none.
+	Hooks *hook.Runner
+}
+
+// Note is the daily note of a day.
+type Note struct {
+	Entry memo.Entry
+	// Created reports whether the note was created by Open rather than reused.
+	Created bool
+	// Carried is the number of tasks carried forward into a new note.
+	Carried int
+}
+
+// TemplateData is available to daily templates, e.g. {{.Date}} or {{.Time.Format "Jan 2"}}.
+type TemplateData struct {
+	// Date is the day in ISO format (2006-01-02).
+	Date string
+	// Weekday is the English name of the day of the week.
+	Weekday string
+	// Time is midnight of the day, in local time.
+	Time time.Time
+}
+
+// Open returns the daily note of date's day, creating it from the template if it does not exist yet.
+func Open(fsys memofs.FS, date time.Time, opts Options) (Note, error) {
+	dateDir := memo.DateDir(date)
+	name := path.Join(dateDir, memo.DailyName+"."+ext)
+
+	if _, err := fsys.Stat(name); err == nil {
+		return reuse(fsys, name)
+	} else if !errors.Is(err, fs.ErrNotExist) {
+		return Note{}, fmt.Errorf("failed to open daily note: %w", err)
+	}
+
+	content, err := Render(opts.Template, date)
+	if err != nil {
+		return Note{}, err
+	}
+
+	carried := 0
+	if opts.CarryForward {
+		tasks, carryErr := openTasksBefore(fsys, dateDir)
+		if carryErr != nil {
+			return Note{}, carryErr
+		}
+		if len(tasks) > 0 {
+			content = appendCarried(content, tasks)
+			carried = len(tasks)
+		}
+	}
+
+	if mkdirErr := fsys.MkdirAll(dateDir, opts.DirPerm); mkdirErr != nil {
+		return Note{}, fmt.Errorf("failed to create date directory: %w", mkdirErr)
+	}
+
+	entry, err := memo.CreateFile(fsys, name, opts.FilePerm, opts.Hooks, func(w io.Writer) error {
+		if _, writeErr := w.Write(content); writeErr != nil {
+			return fmt.Errorf("failed to write daily note: %w", writeErr)
+		}
+		return nil
+	})
+	if errors.Is(err, fs.ErrExist) {
+		// Created concurrently, e.g. by another `memo today`.
+		return reuse(fsys, name)
+	}
+	if err != nil {
+		return Note{}, err
+	}
+
+	return Note{Entry: entry, Created: true, Carried: carried}, nil
+}
+
+// Render executes the daily template src for date. An empty src renders DefaultTemplate.
+func Render(src string, date time.Time) ([]byte, error) {
+	if src == "" {
+		src = DefaultTemplate
+	}
+
+	tmpl, err := template.New("daily").Option("missingkey=error").Parse(src)
+	if err != nil {
+		return nil, fmt.Errorf("invalid daily template: %w", err)
+	}
+
+	day := time.Date(date.Year(), date.Month(), date.Day(), 0, 0, 0, 0, time.Local)
+	var buf bytes.Buffer
+	if execErr := tmpl.Execute(&buf, TemplateData{
+		Date:    day.Format(time.DateOnly),
+		Weekday: day.Weekday().String(),
+		Time:    day,
+	}); execErr != nil {
+		return nil, fmt.Errorf("invalid daily template: %w", execErr)
+	}
+
+	return buf.Bytes(), nil
+}
+
+// ParseDate parses a day given as 2006-01-02, 20060102, "today", "yesterday" or "tomorrow",
+// relative to now and in local time.
+func ParseDate(s string, now time.Time) (time.Time, error) {
+	switch strings.ToLower(s) {
+	case "today":
+		return now, nil
+	case "yesterday":
+		return now.AddDate(0, 0, -1), nil
+	case "tomorrow":
+		return now.AddDate(0, 0, 1), nil
+	}
+
+	for _, layout := range []string{time.DateOnly, "20060102"} {
+		if t, err := time.ParseInLocation(layout, s, time.Local); err == nil {
+			return t, nil
+		}
+	}
+
+	return time.Time{}, fmt.Errorf("%w: %q (use YYYY-MM-DD)", ErrInvalidDate, s)
+}
+
+// ParseOptionalDate is ParseDate for optional bounds such as --since: an empty s yields the zero time.
+func ParseOptionalDate(s string, now time.Time) (time.Time, error) {
+	if s == "" {
+		return time.Time{}, nil
+	}
+	return ParseDate(s, now)
+}
+
+func reuse(fsys memofs.FS, name string) (Note, error) {
+	entry, err := memo.NewEntry(fsys, memoPath(fsys, name))
+	if err != nil {
+		return Note{}, err
+	}
+	return Note{Entry: entry}, nil
+}
+
+// openTasksBefore returns the unchecked tasks of the newest daily note older than dateDir.
+func openTasksBefore(fsys memofs.FS, dateDir string) ([]markdown.Task, error) {
+	entries, err := memo.List(fsys)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, entry := range entries {
+		if !entry.Daily || entry.Encrypted || entry.DateDir >= dateDir {
+			continue
+		}
+
+		content, readErr := fsys.ReadFile(entry.RelPath)
+		if readErr != nil {
+			return nil, fmt.Errorf("failed to read previous daily note: %w", readErr)
+		}
+
+		var open []markdown.Task
+		for _, task := range markdown.Tasks(content) {
+			if !task.Done {
+				open = append(open, task)
+			}
+		}
+		return open, nil
+	}
+
+	return nil, nil
+}
+
+func appendCarried(content []byte, tasks []markdown.Task) []byte {
+	var buf bytes.Buffer
+	buf.Write(content)
+	if len(content) > 0 && !bytes.HasSuffix(content, []byte("\n")) {
+		buf.WriteByte('\n')
+	}
+	buf.WriteString("\n" + carriedHeading + "\n\n")
+	for _, task := range tasks {
+		buf.WriteString(task.Raw + "\n")
+	}
+	return buf.Bytes()
+}
+
+// memoPath converts a name in fsys into the path reported to users.
+func memoPath(fsys memofs.FS, name string) string {
+	return filepath.Join(fsys.Root(), filepath.FromSlash(name))
+}
@@ -0,0 +1,105 @@
+package daily_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/sushichan044/memo-cli/internal/daily"
+	"github.com/sushichan044/memo-cli/internal/memofs"
+)
+
+func TestOpen_CreatesThenReuses(t *testing.T) {
+	fsys := memofs.NewMem("/memo")
+	date := time.Date(2025, 10, 31, 15, 4, 5, 0, time.Local)
+
+	note, err := daily.Open(fsys, date, daily.Options{FilePerm: 0o600, DirPerm: 0o700})
+	require.NoError(t, err)
+	assert.True(t, note.Created)
+	assert.True(t, note.Entry.Daily)
+	assert.Equal(t, "20251031/daily.md", note.Entry.RelPath)
+
+	content, err := fsys.ReadFile(note.Entry.RelPath)
+	require.NoError(t, err)
+	assert.Equal(t, "# 2025-10-31 (Friday)\n", string(content))
+
+	require.NoError(t, fsys.WriteFile(note.Entry.RelPath, []byte("edited\n"), 0o600))
+
+	again, err := daily.Open(fsys, date, daily.Options{Template: "ignored"})
+	require.NoError(t, err)
+	assert.False(t, again.Created)
+	assert.Equal(t, note.Entry.RelPath, again.Entry.RelPath)
+
+	content, err = fsys.ReadFile(note.Entry.RelPath)
+	require.NoError(t, err)
+	assert.Equal(t, "edited\n", string(content))
+}
+
+func TestOpen_CarryForward(t *testing.T) {
+	fsys := memofs.NewMem("/memo")
+	require.NoError(t, fsys.MkdirAll("20251029", 0o700))
+	require.NoError(t, fsys.WriteFile("20251029/daily.md", []byte("- [ ] too old\n"), 0o600))
+	require.NoError(t, fsys.MkdirAll("20251030", 0o700))
+	require.NoError(t, fsys.WriteFile("20251030/daily.md", []byte(
+		"# Thursday\n- [x] done\n- [ ] open one\n  - [ ] nested open\n",
+	), 0o600))
+
+	opts := daily.Options{Template: "# {{.Weekday}}", CarryForward: true, FilePerm: 0o600, DirPerm: 0o700}
+	note, err := daily.Open(fsys, time.Date(2025, 10, 31, 0, 0, 0, 0, time.Local), opts)
+	require.NoError(t, err)
+	assert.Equal(t, 2, note.Carried)
+
+	content, err := fsys.ReadFile(note.Entry.RelPath)
+	require.NoError(t, err)
+	assert.Equal(t, "# Friday\n\n## Carried over\n\n- [ ] open one\n  - [ ] nested open\n", string(content))
+}
+
+func TestRender_InvalidTemplate(t *testing.T) {
+	_, err := daily.Render("{{.Missing}}", time.Now())
+	require.Error(t, err)
+
+	_, err = daily.Render("{{", time.Now())
+	require.Error(t, err)
+}
+
+func TestParseDate(t *testing.T) {
+	now := time.Date(2025, 11, 1, 9, 0, 0, 0, time.Local)
+
+	tests := []struct {
+		in   string
+		want string
+	}{
+		{"today", "20251101"},
+		{"yesterday", "20251031"},
+		{"Tomorrow", "20251102"},
+		{"2025-10-15", "20251015"},
+		{"20251015", "20251015"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.in, func(t *testing.T) {
+			got, err := daily.ParseDate(tt.in, now)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got.Format("20060102"))
+		})
+	}
+
+	_, err := daily.ParseDate("next friday", now)
+	require.ErrorIs(t, err, daily.ErrInvalidDate)
+}
+
+func TestParseOptionalDate(t *testing.T) {
+	now := time.Date(2025, 11, 1, 9, 0, 0, 0, time.Local)
+
+	got, err := daily.ParseOptionalDate("", now)
+	require.NoError(t, err)
+	assert.True(t, got.IsZero())
+
+	got, err = daily.ParseOptionalDate("yesterday", now)
+	require.NoError(t, err)
+	assert.Equal(t, "20251031", got.Format("20060102"))
+
+	_, err = daily.ParseOptionalDate("next friday", now)
+	require.ErrorIs(t, err, daily.ErrInvalidDate)
+}
@@ -0,0 +1,138 @@
+// Package docs generates man pages and a Markdown reference from the kong model of the memo CLI,
+// so that the documentation always matches the commands, flags, defaults and environment variables
+// of the binary.
+package docs
+
+import (
+	"strings"
+
+	"github.com/alecthomas/kong"
+)
+
+// Page is a generated documentation file.
+type Page struct {
+	// Name is the file name, e.g. "memo-new.1".
+	Name    string
+	Content []byte
+}
+
+// commands returns the visible commands of app, depth first in declaration order.
+func commands(node *kong.Node) []*kong.Node {
+	var out []*kong.Node
+	for _, child := range node.Children {
+		if child.Type != kong.CommandNode || child.Hidden {
+			continue
+		}
+		out = append(out, child)
+		out = append(out, commands(child)...)
+	}
+	return out
+}
+
+// subcommands returns the visible direct subcommands of node.
+func subcommands(node *kong.Node) []*kong.Node {
+	var out []*kong.Node
+	for _, child := range node.Children {
+		if child.Type == kong.CommandNode && !child.Hidden {
+			out = append(out, child)
+		}
+	}
+	return out
+}
+
+// flags returns the visible flags declared on node itself, leaving out --help which every command has.
+func flags(app *kong.Application, node *kong.Node) []*kong.Flag {
+	var out []*kong.Flag
+	for _, flag := range node.Flags {
+		if !flag.Hidden && flag != app.HelpFlag {
+			out = append(out, flag)
+		}
+	}
+	return out
+}
+
+// usage returns the synopsis of node, e.g. "memo new [<name>] [flags]".
+func usage(app *kong.Application, node *kong.Node) string {
+	if node == app.Node {
+		return app.Name + " <command> [flags]"
+	}
+	return app.Name + " " + node.Summary()
+}
+
+// pageName returns the name of the man page of node without its section, e.g. "memo-tasks-list".
+func pageName(node *kong.Node) string {
+	return strings.ReplaceAll(node.FullPath(), " ", "-")
+}
+
+// flagSyntax returns how a flag is typed, e.g. "-e, --ext=EXT".
+func flagSyntax(flag *kong.Flag) string {
+	syntax := "--" + flag.Name
+	if flag.Tag.Negatable != "" {
+		syntax = "--[no-]" + flag.Name
+	}
+	if flag.Short != 0 {
+		syntax = "-" + string(flag.Short) + ", " + syntax
+	}
+	if !flag.IsBool() && !flag.IsCounter() {
+		syntax += "=" + placeholder(flag)
+	}
+	return syntax
+}
+
+func placeholder(flag *kong.Flag) string {
+	if flag.PlaceHolder != "" {
+		return flag.PlaceHolder
+	}
+	return strings.ToUpper(flag.Name)
+}
+
+// details returns the facts documented after the help of a flag or argument: its accepted values,
+// default and environment variables.
+func details(value *kong.Value, envs []string) []string {
+	var out []string
+	if value.Enum != "" {
+		out = append(out, "one of: "+strings.Join(value.EnumSlice(), ", "))
+	}
+	if value.HasDefault && value.Default != "" {
+		out = append(out, "default: "+value.Default)
+	}
+	for _, env := range envs {
+		out = append(out, "env: $"+env)
+	}
+	return out
+}
+
+// argumentName returns how a positional argument is written in the synopsis, e.g. "<name>".
+func argumentName(arg *kong.Positional) string {
+	name := "<" + arg.Name + ">"
+	if arg.IsCumulative() {
+		name += " ..."
+	}
+	return name
+}
+
+// withDetails returns description as a sentence ending with details, e.g. "Memo file extension (default: md).".
+func withDetails(description string, facts []string) string {
+	description = strings.TrimSuffix(description, ".")
+	if len(facts) > 0 {
+		description = strings.TrimSpace(description + " (" + strings.Join(facts, "; ") + ")")
+	}
+	return sentence(description)
+}
+
+// argumentHelp returns the description of a positional argument, noting whether it may be left out.
+func argumentHelp(arg *kong.Positional) string {
+	help := withDetails(arg.Help, details(arg, nil))
+	if arg.Required {
+		return help
+	}
+	return strings.TrimSpace("Optional. " + help)
+}
+
+// sentence returns s ending with a period, for help strings that are written without one.
+func sentence(s string) string {
+	if s == "" || strings.HasSuffix(s, ".") {
+		return s
+	}
+	return s + "."
+}
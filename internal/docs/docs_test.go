@@ -0,0 +1,84 @@
+package docs_test
+
+import (
+	"testing"
+
+	"github.com/alecthomas/kong"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/sushichan044/memo-cli/internal/docs"
+)
+
+type cli struct {
+	Format string `help:"Output format" enum:"text,json" default:"text" env:"MEMO_FORMAT"`
+
+	New struct {
+		Name string `arg:"" optional:"" help:"Memo name"`
+		Ext  string `                   help:"Memo file extension" short:"e" default:"md"`
+		Edit *bool  `                   help:"Open the memo in $EDITOR"   negatable:""`
+	} `cmd:"" help:"Create a new memo."`
+	Tasks struct {
+		List struct {
+			Tag string `help:"Only show tasks with this tag | pipes escaped"`
+		} `cmd:"" default:"withargs" help:"List tasks."`
+		Done struct {
+			ID string `arg:"" help:".hidden-looking help"`
+		} `cmd:"" help:"Toggle a task."`
+	} `cmd:"" help:"Manage tasks."`
+	Secret struct{} `cmd:"" hidden:""`
+}
+
+func app(t *testing.T) *kong.Application {
+	t.Helper()
+	parser, err := kong.New(&cli{}, kong.Name("memo"), kong.Description("A CLI tool to create and manage markdown memos"))
+	require.NoError(t, err)
+	return parser.Model
+}
+
+func TestMan(t *testing.T) {
+	pages := docs.Man(app(t), "memo-cli v1.2.0")
+
+	names := make([]string, 0, len(pages))
+	content := make(map[string]string)
+	for _, page := range pages {
+		names = append(names, page.Name)
+		content[page.Name] = string(page.Content)
+	}
+	assert.Equal(t, []string{"memo.1", "memo-new.1", "memo-tasks.1", "memo-tasks-list.1", "memo-tasks-done.1"}, names)
+
+	newPage := content["memo-new.1"]
+	assert.Contains(t, newPage, `.TH "MEMO\-NEW" 1 "" "memo\-cli v1.2.0" "memo manual"`)
+	assert.Contains(t, newPage, "memo\\-new \\- Create a new memo.\n")
+	assert.Contains(t, newPage, ".B memo new [<name>] [flags]\n")
+	assert.Contains(t, newPage, ".B \\-e, \\-\\-ext=EXT\nMemo file extension (default: md).\n")
+	assert.Contains(t, newPage, ".I <name>\nOptional. Memo name.\n")
+	assert.Contains(t, newPage, ".SH GLOBAL OPTIONS\n")
+	assert.Contains(t, newPage, "(one of: text, json; default: text; env: $MEMO_FORMAT)")
+	assert.Contains(t, newPage, ".SH ENVIRONMENT\n.TP\n.B MEMO_FORMAT\n")
+	assert.Contains(t, newPage, ".SH SEE ALSO\nmemo(1)\n")
+
+	assert.Contains(t, content["memo-tasks.1"], ".BR memo\\-tasks\\-list (1)\n")
+	assert.Contains(t, content["memo-tasks-list.1"], "default subcommand of \\fBmemo tasks\\fR")
+	assert.Contains(t, content["memo-tasks-done.1"], "\n\\&.hidden\\-looking help.\n")
+	assert.NotContains(t, content["memo.1"], "secret")
+}
+
+func TestMarkdown(t *testing.T) {
+	pages := docs.Markdown(app(t))
+	require.Len(t, pages, 1)
+	assert.Equal(t, docs.MarkdownName, pages[0].Name)
+
+	content := string(pages[0].Content)
+	assert.Contains(t, content, "# memo CLI reference\n")
+	assert.Contains(t, content, "| `--format=FORMAT` | Output format (one of: text, json; default: text; env: $MEMO_FORMAT). |\n")
+	assert.Contains(t, content, "- [`memo new`](#memo-new): Create a new memo.\n")
+	assert.Contains(t, content, "  - [`memo tasks list`](#memo-tasks-list): List tasks.\n")
+	assert.Contains(t, content, "## memo new\n\nCreate a new memo.\n\n```\nmemo new [<name>] [flags]\n```\n")
+	assert.Contains(t, content, "| `<name>` | Optional. Memo name. |\n")
+	assert.Contains(t, content, "| `-e, --ext=EXT` | Memo file extension (default: md). |\n")
+	assert.Contains(t, content, "| `--[no-]edit` | Open the memo in $EDITOR. |\n")
+	assert.Contains(t, content, "| [`done`](#memo-tasks-done) | Toggle a task. |\n")
+	assert.Contains(t, content, `Only show tasks with this tag \| pipes escaped.`)
+	assert.NotContains(t, content, "secret")
+}
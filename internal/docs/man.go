@@ -0,0 +1,129 @@
+package docs
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/alecthomas/kong"
+)
+
+// ManSection is the manual section of the generated pages, user commands.
+const ManSection = "1"
+
+// Man returns a man page for app and one for each of its visible commands, such as memo.1 and memo-new.1.
+// source names the software in the page footer, e.g. "memo-cli v1.2.0". Pages carry no date, so that
+// generating them twice gives the same files.
+func Man(app *kong.Application, source string) []Page {
+	nodes := append([]*kong.Node{app.Node}, commands(app.Node)...)
+	pages := make([]Page, 0, len(nodes))
+	for _, node := range nodes {
+		pages = append(pages, Page{
+			Name:    pageName(node) + "." + ManSection,
+			Content: []byte(manPage(app, node, source)),
+		})
+	}
+	return pages
+}
+
+func manPage(app *kong.Application, node *kong.Node, source string) string {
+	var b strings.Builder
+	name := pageName(node)
+	fmt.Fprintf(&b, ".TH %s %s \"\" %s %s\n", roffQuote(strings.ToUpper(name)), ManSection,
+		roffQuote(source), roffQuote(app.Name+" manual"))
+
+	b.WriteString(".SH NAME\n")
+	fmt.Fprintf(&b, "%s \\- %s\n", roff(name), roff(node.Help))
+
+	b.WriteString(".SH SYNOPSIS\n")
+	fmt.Fprintf(&b, ".B %s\n", roff(usage(app, node)))
+
+	if description := strings.TrimSpace(node.Detail); description != "" || node.Help != "" {
+		if description == "" {
+			description = sentence(node.Help)
+		}
+		b.WriteString(".SH DESCRIPTION\n")
+		writeParagraphs(&b, description)
+		if node.Parent != nil && node.Parent.DefaultCmd == node {
+			fmt.Fprintf(&b, ".PP\nThis is the default subcommand of \\fB%s\\fR.\n", roff(node.Parent.FullPath()))
+		}
+	}
+
+	if subs := subcommands(node); len(subs) > 0 {
+		b.WriteString(".SH COMMANDS\n")
+		for _, sub := range subs {
+			fmt.Fprintf(&b, ".TP\n.BR %s (%s)\n%s\n", roff(pageName(sub)), ManSection, roff(sentence(sub.Help)))
+		}
+	}
+
+	if len(node.Positional) > 0 {
+		b.WriteString(".SH ARGUMENTS\n")
+		for _, arg := range node.Positional {
+			fmt.Fprintf(&b, ".TP\n.I %s\n%s\n", roff(argumentName(arg)), roff(argumentHelp(arg)))
+		}
+	}
+
+	writeManFlags(&b, "OPTIONS", flags(app, node))
+	if node != app.Node {
+		writeManFlags(&b, "GLOBAL OPTIONS", flags(app, app.Node))
+	}
+
+	var envs []string
+	for _, flag := range append(flags(app, node), flags(app, app.Node)...) {
+		for _, env := range flag.Envs {
+			envs = append(envs, fmt.Sprintf(".TP\n.B %s\nSets \\fB\\-\\-%s\\fR.\n", roff(env), roff(flag.Name)))
+		}
+	}
+	if len(envs) > 0 {
+		b.WriteString(".SH ENVIRONMENT\n")
+		b.WriteString(strings.Join(envs, ""))
+	}
+
+	var related []string
+	for parent := node.Parent; parent != nil; parent = parent.Parent {
+		related = append(related, roff(pageName(parent))+"("+ManSection+")")
+	}
+	if len(related) > 0 {
+		b.WriteString(".SH SEE ALSO\n")
+		b.WriteString(strings.Join(related, ", ") + "\n")
+	}
+	return b.String()
+}
+
+func writeManFlags(b *strings.Builder, title string, list []*kong.Flag) {
+	if len(list) == 0 {
+		return
+	}
+	fmt.Fprintf(b, ".SH %s\n", title)
+	for _, flag := range list {
+		fmt.Fprintf(b, ".TP\n.B %s\n%s\n", roff(flagSyntax(flag)),
+			roff(withDetails(flag.Help, details(flag.Value, flag.Envs))))
+	}
+}
+
+// writeParagraphs writes text as roff paragraphs separated by blank lines.
+func writeParagraphs(b *strings.Builder, text string) {
+	for i, paragraph := range strings.Split(text, "\n\n") {
+		if i > 0 {
+			b.WriteString(".PP\n")
+		}
+		b.WriteString(roff(strings.TrimSpace(paragraph)) + "\n")
+	}
+}
+
+// roff escapes s for the text of a roff document: backslashes and dashes are escaped, and lines that would
+// start with a control character are protected.
+func roff(s string) string {
+	s = strings.NewReplacer(`\`, `\e`, "-", `\-`).Replace(s)
+	lines := strings.Split(s, "\n")
+	for i, line := range lines {
+		if strings.HasPrefix(line, ".") || strings.HasPrefix(line, "'") {
+			lines[i] = `\&` + line
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+// roffQuote returns s as a quoted argument of a roff request.
+func roffQuote(s string) string {
+	return `"` + strings.ReplaceAll(roff(s), `"`, `\(dq`) + `"`
+}
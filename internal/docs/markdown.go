@@ -0,0 +1,103 @@
+package docs
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/alecthomas/kong"
+)
+
+// MarkdownName is the file name of the Markdown reference.
+const MarkdownName = "cli.md"
+
+// Markdown returns a single-page Markdown reference of every visible command of app.
+func Markdown(app *kong.Application) []Page {
+	var b strings.Builder
+	fmt.Fprintf(&b, "# %s CLI reference\n\n", app.Name)
+	fmt.Fprintf(&b, "<!-- Generated by `%s gen-docs --doc-format markdown`. Do not edit. -->\n\n", app.Name)
+	if app.Help != "" {
+		fmt.Fprintf(&b, "%s\n\n", sentence(app.Help))
+	}
+	fmt.Fprintf(&b, "```\n%s\n```\n\n", usage(app, app.Node))
+
+	writeFlagTable(&b, "Global flags", flags(app, app.Node))
+
+	b.WriteString("## Commands\n\n")
+	for _, node := range commands(app.Node) {
+		indent := strings.Repeat("  ", node.Depth())
+		fmt.Fprintf(&b, "%s- [`%s`](#%s): %s\n", indent, node.FullPath(), anchor(node.FullPath()), sentence(node.Help))
+	}
+	b.WriteString("\n")
+
+	for _, node := range commands(app.Node) {
+		writeCommand(&b, app, node)
+	}
+
+	return []Page{{Name: MarkdownName, Content: []byte(strings.TrimRight(b.String(), "\n") + "\n")}}
+}
+
+func writeCommand(b *strings.Builder, app *kong.Application, node *kong.Node) {
+	fmt.Fprintf(b, "## %s\n\n", node.FullPath())
+	if node.Help != "" {
+		fmt.Fprintf(b, "%s\n\n", sentence(node.Help))
+	}
+	if node.Detail != "" {
+		fmt.Fprintf(b, "%s\n\n", node.Detail)
+	}
+	fmt.Fprintf(b, "```\n%s\n```\n\n", usage(app, node))
+	if node.Parent != nil && node.Parent.DefaultCmd == node {
+		fmt.Fprintf(b, "This is the default subcommand of `%s`.\n\n", node.Parent.FullPath())
+	}
+
+	if subs := subcommands(node); len(subs) > 0 {
+		b.WriteString("| Command | Description |\n| --- | --- |\n")
+		for _, sub := range subs {
+			fmt.Fprintf(b, "| [`%s`](#%s) | %s |\n", sub.Name, anchor(sub.FullPath()), cell(sentence(sub.Help)))
+		}
+		b.WriteString("\n")
+	}
+
+	if len(node.Positional) > 0 {
+		b.WriteString("| Argument | Description |\n| --- | --- |\n")
+		for _, arg := range node.Positional {
+			fmt.Fprintf(b, "| `%s` | %s |\n", argumentName(arg), cell(argumentHelp(arg)))
+		}
+		b.WriteString("\n")
+	}
+
+	writeFlagTable(b, "", flags(app, node))
+}
+
+func writeFlagTable(b *strings.Builder, title string, list []*kong.Flag) {
+	if len(list) == 0 {
+		return
+	}
+	if title != "" {
+		fmt.Fprintf(b, "## %s\n\n", title)
+	}
+	b.WriteString("| Flag | Description |\n| --- | --- |\n")
+	for _, flag := range list {
+		fmt.Fprintf(b, "| `%s` | %s |\n", flagSyntax(flag), cell(withDetails(flag.Help, details(flag.Value, flag.Envs))))
+	}
+	b.WriteString("\n")
+}
+
+// cell escapes s for a Markdown table cell.
+func cell(s string) string {
+	s = strings.ReplaceAll(s, "|", `\|`)
+	return strings.ReplaceAll(s, "\n", " ")
+}
+
+// anchor returns the GitHub anchor of a heading, e.g. "memo-tasks-list" for "memo tasks list".
+func anchor(heading string) string {
+	var b strings.Builder
+	for _, r := range strings.ToLower(heading) {
+		switch {
+		case r == ' ':
+			b.WriteRune('-')
+		case r == '-' || r == '_' || r >= 'a' && r <= 'z' || r >= '0' && r <= '9':
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
@@ -0,0 +1,52 @@
+// Package editor launches the user's text editor.
+package editor
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"runtime"
+	"strings"
+)
+
+// Command returns the user's editor command line from $VISUAL or $EDITOR.
+// It falls back to vi (notepad on Windows) when neither is set.
+func Command() string {
+	for _, env := range []string{"VISUAL", "EDITOR"} {
+		if editor := strings.TrimSpace(os.Getenv(env)); editor != "" {
+			return editor
+		}
+	}
+
+	if runtime.GOOS == "windows" {
+		return "notepad"
+	}
+	return "vi"
+}
+
+// Open opens path in the user's editor and waits for it to exit.
+// The editor inherits the terminal of the current process.
+// Like git, the editor command is interpreted by the shell on Unix,
+// so values such as `code --wait` or quoted paths work as expected.
+func Open(path string) error {
+	command := Command()
+
+	var cmd *exec.Cmd
+	if runtime.GOOS == "windows" {
+		fields := strings.Fields(command)
+		//nolint:gosec // the editor command is chosen by the user on purpose
+		cmd = exec.Command(fields[0], append(fields[1:], path)...)
+	} else {
+		//nolint:gosec // the editor command is chosen by the user on purpose
+		cmd = exec.Command("sh", "-c", command+` "$@"`, command, path)
+	}
+	cmd.Stdin = os.Stdin
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("editor %q failed: %w", command, err)
+	}
+
+	return nil
+}
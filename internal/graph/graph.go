@@ -0,0 +1,199 @@
+// Package graph builds the graph of related memos: memos are nodes, joined by
+// [[wiki-links]] and by the tags they share. It renders the graph for Graphviz and Mermaid.
+package graph
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/sushichan044/memo-cli/internal/links"
+	"github.com/sushichan044/memo-cli/internal/markdown"
+	"github.com/sushichan044/memo-cli/internal/memo"
+)
+
+// EdgeKind tells why two memos are joined.
+type EdgeKind string
+
+const (
+	// EdgeLink is a wiki-link from one memo to another.
+	EdgeLink EdgeKind = "link"
+	// EdgeTag joins two memos sharing at least one tag. It has no direction.
+	EdgeTag EdgeKind = "tag"
+)
+
+// Node is a memo of the graph.
+type Node struct {
+	Entry memo.Entry
+	Tags  []string
+}
+
+// Edge joins two nodes, identified by the index of their node in Graph.Nodes.
+type Edge struct {
+	From int
+	To   int
+	Kind EdgeKind
+	// Tags are the tags shared by both memos of an EdgeTag edge.
+	Tags []string
+}
+
+// Graph is the graph of a set of memos.
+type Graph struct {
+	Nodes []Node
+	Edges []Edge
+}
+
+// Options selects the memos and edges of a graph.
+type Options struct {
+	// Since and Until restrict the graph to memos created on or between their days. Zero values are unbounded.
+	Since time.Time
+	Until time.Time
+	// NoLinks and NoTags leave out link and shared tag edges.
+	NoLinks bool
+	NoTags  bool
+}
+
+// Build builds the graph of entries, which must be sorted newest first as returned by memo.List.
+// read returns the plaintext content of a memo; returning nil content with a nil error leaves the memo
+// without tags and outgoing links.
+// Links are resolved against every entry, so that a link means the same in a graph of any date range,
+// but only links between memos of the graph become edges.
+func Build(entries []memo.Entry, read func(memo.Entry) ([]byte, error), opts Options) (*Graph, error) {
+	g := &Graph{}
+	index := map[string]int{}
+	contents := map[string][]byte{}
+
+	for _, entry := range entries {
+		if !opts.Since.IsZero() && entry.DateDir < memo.DateDir(opts.Since) {
+			continue
+		}
+		if !opts.Until.IsZero() && entry.DateDir > memo.DateDir(opts.Until) {
+			continue
+		}
+
+		content, err := read(entry)
+		if err != nil {
+			return nil, err
+		}
+		contents[entry.RelPath] = content
+		index[entry.RelPath] = len(g.Nodes)
+		g.Nodes = append(g.Nodes, Node{Entry: entry, Tags: markdown.Tags(content)})
+	}
+
+	if !opts.NoLinks {
+		linkIndex, err := links.Build(entries, func(entry memo.Entry) ([]byte, error) {
+			return contents[entry.RelPath], nil
+		})
+		if err != nil {
+			return nil, err
+		}
+		g.addLinks(linkIndex.All(), index)
+	}
+
+	if !opts.NoTags {
+		g.addSharedTags()
+	}
+
+	return g, nil
+}
+
+// addLinks adds one edge per linked pair of nodes. Broken links and links to the memo itself are skipped.
+func (g *Graph) addLinks(all []links.Link, index map[string]int) {
+	seen := map[[2]int]bool{}
+	for _, link := range all {
+		if link.Broken {
+			continue
+		}
+		from, fromOK := index[link.From.RelPath]
+		to, toOK := index[link.To.RelPath]
+		if !fromOK || !toOK || from == to || seen[[2]int{from, to}] {
+			continue
+		}
+		seen[[2]int{from, to}] = true
+		g.Edges = append(g.Edges, Edge{From: from, To: to, Kind: EdgeLink})
+	}
+}
+
+// addSharedTags adds one edge per pair of nodes sharing tags.
+func (g *Graph) addSharedTags() {
+	for i := range g.Nodes {
+		for j := i + 1; j < len(g.Nodes); j++ {
+			var shared []string
+			for _, tag := range g.Nodes[i].Tags {
+				if slices.Contains(g.Nodes[j].Tags, tag) {
+					shared = append(shared, tag)
+				}
+			}
+			if len(shared) > 0 {
+				g.Edges = append(g.Edges, Edge{From: i, To: j, Kind: EdgeTag, Tags: shared})
+			}
+		}
+	}
+}
+
+// DOT renders the graph in the Graphviz DOT language.
+// Links are solid arrows and shared tags are dashed lines labeled with the tags.
+func (g *Graph) DOT() string {
+	var b strings.Builder
+	b.WriteString("digraph memos {\n")
+	b.WriteString("  node [shape=box];\n")
+	for _, node := range g.Nodes {
+		fmt.Fprintf(&b, "  %s [label=%s, tooltip=%s];\n", dotQuote(node.Entry.RelPath),
+			dotQuote(node.Entry.Title()+"\n"+node.Entry.CreatedAt.Format(time.DateOnly)),
+			dotQuote(hashtags(node.Tags)))
+	}
+	for _, edge := range g.Edges {
+		from, to := dotQuote(g.Nodes[edge.From].Entry.RelPath), dotQuote(g.Nodes[edge.To].Entry.RelPath)
+		if edge.Kind == EdgeTag {
+			fmt.Fprintf(&b, "  %s -> %s [dir=none, style=dashed, label=%s];\n", from, to, dotQuote(hashtags(edge.Tags)))
+			continue
+		}
+		fmt.Fprintf(&b, "  %s -> %s;\n", from, to)
+	}
+	b.WriteString("}\n")
+
+	return b.String()
+}
+
+// Mermaid renders the graph as a Mermaid flowchart.
+// Links are arrows and shared tags are dotted lines labeled with the tags.
+func (g *Graph) Mermaid() string {
+	var b strings.Builder
+	b.WriteString("graph LR\n")
+	for i, node := range g.Nodes {
+		fmt.Fprintf(&b, "  n%d[\"%s<br/>%s\"]\n", i, mermaidEscape(node.Entry.Title()),
+			node.Entry.CreatedAt.Format(time.DateOnly))
+	}
+	for _, edge := range g.Edges {
+		if edge.Kind == EdgeTag {
+			fmt.Fprintf(&b, "  n%d -. \"%s\" .- n%d\n", edge.From, mermaidEscape(strings.Join(edge.Tags, " ")), edge.To)
+			continue
+		}
+		fmt.Fprintf(&b, "  n%d --> n%d\n", edge.From, edge.To)
+	}
+
+	return b.String()
+}
+
+func hashtags(tags []string) string {
+	var b strings.Builder
+	for i, tag := range tags {
+		if i > 0 {
+			b.WriteByte(' ')
+		}
+		b.WriteString("#" + tag)
+	}
+	return b.String()
+}
+
+// dotQuote returns s as a DOT quoted string.
+func dotQuote(s string) string {
+	s = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`).Replace(s)
+	return `"` + s + `"`
+}
+
+// mermaidEscape escapes the characters that end or break a quoted Mermaid label.
+func mermaidEscape(s string) string {
+	return strings.NewReplacer(`"`, "#quot;", "<", "#lt;", ">", "#gt;").Replace(s)
+}
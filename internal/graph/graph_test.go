@@ -0,0 +1,89 @@
+package graph_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/sushichan044/memo-cli/internal/graph"
+	"github.com/sushichan044/memo-cli/internal/memo"
+	"github.com/sushichan044/memo-cli/internal/memofs"
+)
+
+func build(t *testing.T, opts graph.Options) *graph.Graph {
+	t.Helper()
+
+	fsys := memofs.NewMem("/memo")
+	files := map[string]string{
+		"20251029/08-00-00-old.md":    "#proj\n",
+		"20251030/09-00-00-design.md": "See [[plan]], [[plan]] and [[old]]. #proj #ux\n",
+		"20251031/10-00-00-plan.md":   "Back to [[design]] and [[nowhere]]. #proj #ux\n",
+	}
+	for name, content := range files {
+		require.NoError(t, fsys.MkdirAll(name[:8], 0o700))
+		require.NoError(t, fsys.WriteFile(name, []byte(content), 0o600))
+	}
+
+	entries, err := memo.List(fsys)
+	require.NoError(t, err)
+
+	g, err := graph.Build(entries, func(e memo.Entry) ([]byte, error) {
+		return fsys.ReadFile(e.RelPath)
+	}, opts)
+	require.NoError(t, err)
+
+	return g
+}
+
+func TestBuild(t *testing.T) {
+	g := build(t, graph.Options{})
+
+	require.Len(t, g.Nodes, 3)
+	assert.Equal(t, "plan", g.Nodes[0].Entry.Title())
+	assert.Equal(t, []string{"proj", "ux"}, g.Nodes[1].Tags)
+
+	assert.Equal(t, []graph.Edge{
+		{From: 0, To: 1, Kind: graph.EdgeLink},
+		{From: 1, To: 0, Kind: graph.EdgeLink},
+		{From: 1, To: 2, Kind: graph.EdgeLink},
+		{From: 0, To: 1, Kind: graph.EdgeTag, Tags: []string{"proj", "ux"}},
+		{From: 0, To: 2, Kind: graph.EdgeTag, Tags: []string{"proj"}},
+		{From: 1, To: 2, Kind: graph.EdgeTag, Tags: []string{"proj"}},
+	}, g.Edges)
+}
+
+func TestBuild_DateRange(t *testing.T) {
+	g := build(t, graph.Options{
+		Since:  time.Date(2025, 10, 30, 0, 0, 0, 0, time.Local),
+		Until:  time.Date(2025, 10, 30, 23, 0, 0, 0, time.Local),
+		NoTags: true,
+	})
+
+	require.Len(t, g.Nodes, 1)
+	assert.Equal(t, "design", g.Nodes[0].Entry.Title())
+	assert.Empty(t, g.Edges, "links leaving the range are dropped")
+}
+
+func TestRender(t *testing.T) {
+	g := build(t, graph.Options{Since: time.Date(2025, 10, 30, 0, 0, 0, 0, time.Local)})
+
+	assert.Equal(t, `digraph memos {
+  node [shape=box];
+  "20251031/10-00-00-plan.md" [label="plan\n2025-10-31", tooltip="#proj #ux"];
+  "20251030/09-00-00-design.md" [label="design\n2025-10-30", tooltip="#proj #ux"];
+  "20251031/10-00-00-plan.md" -> "20251030/09-00-00-design.md";
+  "20251030/09-00-00-design.md" -> "20251031/10-00-00-plan.md";
+  "20251031/10-00-00-plan.md" -> "20251030/09-00-00-design.md" [dir=none, style=dashed, label="#proj #ux"];
+}
+`, g.DOT())
+
+	assert.Equal(t, `graph LR
+  n0["plan<br/>2025-10-31"]
+  n1["design<br/>2025-10-30"]
+  n0 --> n1
+  n1 --> n0
+  n0 -. "proj ux" .- n1
+`, g.Mermaid())
+}
@@ -0,0 +1,237 @@
+// Package history records changes to the memo root in a git repository of its own.
+//
+// Memos are usually ignored by the enclosing project repository, so history is kept in
+// a separate repository whose work tree is the memo root itself. It is opt-in:
+// Init creates the repository, and Open reports ErrNotEnabled until then.
+package history
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+const (
+	// gitDir is the repository directory inside the memo root.
+	gitDir = ".git"
+
+	defaultUserName  = "memo"
+	defaultUserEmail = "memo@localhost"
+
+	recordSeparator = "\x1e"
+	fieldSeparator  = "\x1f"
+)
+
+// ErrNotEnabled is returned by Open when the memo root is not a history repository.
+var ErrNotEnabled = errors.New("history is not enabled: run `memo init --history`")
+
+// Repo is the history repository of a memo root.
+type Repo struct {
+	dir string
+}
+
+// Revision is a commit that changed a memo.
+type Revision struct {
+	// Hash is the full commit hash.
+	Hash string
+	// Time is the commit author date.
+	Time time.Time
+	// Subject is the first line of the commit message (e.g. "edit 20251031/14-30-45-notes.md").
+	Subject string
+	// Name is the slash-separated path of the memo in this revision, which differs
+	// from the current one if the memo was renamed since.
+	Name string
+}
+
+// Init turns dir into a history repository, or opens it if it already is one.
+// Repository files are created with perm, so the history is as private as the memos themselves.
+func Init(dir string, perm os.FileMode) (*Repo, error) {
+	if repo, err := Open(dir); err == nil {
+		return repo, nil
+	} else if !errors.Is(err, ErrNotEnabled) {
+		return nil, err
+	}
+
+	repo := &Repo{dir: dir}
+	if _, err := repo.git(nil, "init", "--quiet", "--template=", fmt.Sprintf("--shared=%04o", perm.Perm())); err != nil {
+		return nil, err
+	}
+
+	// No template: sample hooks are executable and would be reported by `memo doctor`.
+	// git creates COMMIT_EDITMSG with the process umask and keeps the mode of an existing file.
+	editMsg := filepath.Join(dir, gitDir, "COMMIT_EDITMSG")
+	if err := os.WriteFile(editMsg, nil, perm.Perm()); err != nil {
+		return nil, fmt.Errorf("failed to initialize history: %w", err)
+	}
+
+	// Commits must not fail on machines without a global git identity.
+	if email, _ := repo.git(nil, "config", "user.email"); strings.TrimSpace(email) == "" {
+		if _, err := repo.git(nil, "config", "user.name", defaultUserName); err != nil {
+			return nil, err
+		}
+		if _, err := repo.git(nil, "config", "user.email", defaultUserEmail); err != nil {
+			return nil, err
+		}
+	}
+
+	return repo, nil
+}
+
+// Open opens the history repository of dir.
+// Returns ErrNotEnabled if dir has not been initialized with Init.
+func Open(dir string) (*Repo, error) {
+	info, err := os.Stat(filepath.Join(dir, gitDir))
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil, ErrNotEnabled
+		}
+		return nil, fmt.Errorf("failed to open history: %w", err)
+	}
+	if !info.IsDir() {
+		return nil, ErrNotEnabled
+	}
+
+	return &Repo{dir: dir}, nil
+}
+
+// Internal reports whether name, relative to the memo root, belongs to the history repository itself.
+func Internal(name string) bool {
+	return name == gitDir || strings.HasPrefix(name, gitDir+"/")
+}
+
+// Commit records every change in the memo root with message.
+// Returns false without committing when nothing changed.
+func (r *Repo) Commit(message string) (bool, error) {
+	if _, err := r.git(nil, "add", "--all"); err != nil {
+		return false, err
+	}
+
+	if _, err := r.git(nil, "diff", "--cached", "--quiet"); err == nil {
+		return false, nil
+	}
+
+	if _, err := r.git(strings.NewReader(message), "commit", "--quiet", "--no-verify", "--file=-"); err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
+
+// Log returns the revisions that changed the memo name, newest first, following renames.
+func (r *Repo) Log(name string) ([]Revision, error) {
+	out, err := r.git(nil, "log", "--follow", "--name-only",
+		"--format="+recordSeparator+"%H"+fieldSeparator+"%aI"+fieldSeparator+"%s",
+		"--", name)
+	if err != nil {
+		return nil, err
+	}
+
+	var revisions []Revision
+	for record := range strings.SplitSeq(out, recordSeparator) {
+		header, files, _ := strings.Cut(strings.TrimSpace(record), "\n")
+		fields := strings.Split(header, fieldSeparator)
+		if len(fields) != 3 { //nolint:mnd // hash, date and subject
+			continue
+		}
+
+		date, parseErr := time.Parse(time.RFC3339, fields[1])
+		if parseErr != nil {
+			return nil, fmt.Errorf("failed to parse history: %w", parseErr)
+		}
+
+		revisions = append(revisions, Revision{
+			Hash:    fields[0],
+			Time:    date,
+			Subject: fields[2],
+			Name:    strings.TrimSpace(files),
+		})
+	}
+
+	return revisions, nil
+}
+
+// Show returns the content of the memo name as of rev, looking up its former name if it was renamed since.
+func (r *Repo) Show(name, rev string) ([]byte, error) {
+	oldName, err := r.nameAt(name, rev)
+	if err != nil {
+		return nil, err
+	}
+
+	out, err := r.git(nil, "show", rev+":"+oldName)
+	if err != nil {
+		return nil, err
+	}
+
+	return []byte(out), nil
+}
+
+// Diff returns a unified diff of the memo name.
+// With an empty rev it is the most recent change to the memo; otherwise it is
+// every change between rev and the current content.
+func (r *Repo) Diff(name, rev string) (string, error) {
+	if rev == "" {
+		return r.git(nil, "log", "-1", "--follow", "--patch", "--format=", "--", name)
+	}
+
+	oldName, err := r.nameAt(name, rev)
+	if err != nil {
+		return "", err
+	}
+
+	return r.git(nil, "diff", "--find-renames", rev, "--", oldName, name)
+}
+
+// nameAt returns the path the memo name had as of rev.
+func (r *Repo) nameAt(name, rev string) (string, error) {
+	commit, err := r.git(nil, "rev-parse", "--verify", "--quiet", rev+"^{commit}")
+	if err != nil {
+		return "", fmt.Errorf("unknown revision %q", rev)
+	}
+	commit = strings.TrimSpace(commit)
+
+	revisions, err := r.Log(name)
+	if err != nil {
+		return "", err
+	}
+
+	for _, revision := range revisions {
+		if _, ancestorErr := r.git(nil, "merge-base", "--is-ancestor", revision.Hash, commit); ancestorErr == nil {
+			return revision.Name, nil
+		}
+	}
+
+	return "", fmt.Errorf("%s did not exist in revision %q", name, rev)
+}
+
+// git runs a git command against the history repository and returns its standard output.
+func (r *Repo) git(stdin *strings.Reader, args ...string) (string, error) {
+	if _, err := exec.LookPath("git"); err != nil {
+		return "", fmt.Errorf("history requires git: %w", err)
+	}
+
+	cmd := exec.Command("git", append([]string{
+		"-C", r.dir, "--git-dir=" + gitDir, "--work-tree=.", "-c", "commit.gpgsign=false",
+	}, args...)...)
+	if stdin != nil {
+		cmd.Stdin = stdin
+	}
+
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	if err := cmd.Run(); err != nil {
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return "", fmt.Errorf("git %s: %s", args[0], msg)
+		}
+		return "", fmt.Errorf("git %s: %w", args[0], err)
+	}
+
+	return stdout.String(), nil
+}
@@ -0,0 +1,127 @@
+package history_test
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/sushichan044/memo-cli/internal/history"
+)
+
+func initRepo(t *testing.T) (*history.Repo, string) {
+	t.Helper()
+
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is not installed")
+	}
+
+	dir := t.TempDir()
+	repo, err := history.Init(dir, 0o600)
+	require.NoError(t, err)
+
+	return repo, dir
+}
+
+func writeMemo(t *testing.T, dir, name, content string) {
+	t.Helper()
+
+	path := filepath.Join(dir, filepath.FromSlash(name))
+	require.NoError(t, os.MkdirAll(filepath.Dir(path), 0o700))
+	require.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+}
+
+func TestOpen_NotEnabled(t *testing.T) {
+	_, err := history.Open(t.TempDir())
+	require.ErrorIs(t, err, history.ErrNotEnabled)
+}
+
+func TestInit_Idempotent(t *testing.T) {
+	_, dir := initRepo(t)
+
+	_, err := history.Init(dir, 0o600)
+	require.NoError(t, err)
+
+	_, err = history.Open(dir)
+	require.NoError(t, err)
+}
+
+func TestInit_PrivateRepository(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("permission bits are not meaningful on Windows")
+	}
+
+	repo, dir := initRepo(t)
+	writeMemo(t, dir, "20251031/14-30-45-notes.md", "secret\n")
+	_, err := repo.Commit("new 20251031/14-30-45-notes.md")
+	require.NoError(t, err)
+
+	err = filepath.WalkDir(filepath.Join(dir, ".git"), func(path string, d os.DirEntry, walkErr error) error {
+		require.NoError(t, walkErr)
+		info, infoErr := d.Info()
+		require.NoError(t, infoErr)
+		assert.Zero(t, info.Mode().Perm()&0o077, "mode of %s", path)
+		return nil
+	})
+	require.NoError(t, err)
+}
+
+func TestCommit_NothingChanged(t *testing.T) {
+	repo, dir := initRepo(t)
+	writeMemo(t, dir, "20251031/14-30-45-notes.md", "one\n")
+
+	committed, err := repo.Commit("new")
+	require.NoError(t, err)
+	assert.True(t, committed)
+
+	committed, err = repo.Commit("edit")
+	require.NoError(t, err)
+	assert.False(t, committed)
+}
+
+func TestLogShowDiff_FollowsRenames(t *testing.T) {
+	repo, dir := initRepo(t)
+
+	writeMemo(t, dir, "20251031/14-30-45-draft.md", "one\n")
+	_, err := repo.Commit("new 20251031/14-30-45-draft.md")
+	require.NoError(t, err)
+
+	writeMemo(t, dir, "20251031/14-30-45-draft.md", "one\ntwo\n")
+	_, err = repo.Commit("edit 20251031/14-30-45-draft.md")
+	require.NoError(t, err)
+
+	require.NoError(t, os.Rename(
+		filepath.Join(dir, "20251031", "14-30-45-draft.md"),
+		filepath.Join(dir, "20251031", "14-30-45-final.md"),
+	))
+	_, err = repo.Commit("mv 20251031/14-30-45-draft.md -> 20251031/14-30-45-final.md")
+	require.NoError(t, err)
+
+	revisions, err := repo.Log("20251031/14-30-45-final.md")
+	require.NoError(t, err)
+	require.Len(t, revisions, 3)
+	assert.Equal(t, "mv 20251031/14-30-45-draft.md -> 20251031/14-30-45-final.md", revisions[0].Subject)
+	assert.Equal(t, "20251031/14-30-45-final.md", revisions[0].Name)
+	assert.Equal(t, "20251031/14-30-45-draft.md", revisions[2].Name)
+	assert.False(t, revisions[2].Time.IsZero())
+
+	first := revisions[2].Hash
+	content, err := repo.Show("20251031/14-30-45-final.md", first)
+	require.NoError(t, err)
+	assert.Equal(t, "one\n", string(content))
+
+	diff, err := repo.Diff("20251031/14-30-45-final.md", first[:7])
+	require.NoError(t, err)
+	assert.Contains(t, diff, "+two")
+
+	diff, err = repo.Diff("20251031/14-30-45-final.md", "")
+	require.NoError(t, err)
+	assert.Contains(t, diff, "rename to 20251031/14-30-45-final.md")
+
+	_, err = repo.Show("20251031/14-30-45-final.md", "no-such-rev")
+	require.Error(t, err)
+}
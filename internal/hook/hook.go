@@ -0,0 +1,154 @@
+// Package hook runs the commands configured in the [hooks] section of the config file around memo operations.
+//
+// A hook receives the memo it runs for as a schema.Hook JSON document on stdin, and in the MEMO_HOOK,
+// MEMO_ROOT_DIR, MEMO_PATH, MEMO_REL_PATH, MEMO_NAME, MEMO_EXT and MEMO_ENCRYPTED environment variables.
+// Pre-hooks run before the operation and abort it by failing; post-hooks run after it.
+package hook
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"runtime"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/sushichan044/memo-cli/internal/config"
+	"github.com/sushichan044/memo-cli/schema"
+)
+
+// Event names the moment a hook runs at.
+type Event string
+
+const (
+	// PreCreate runs before a memo is created, with the memo it is about to become.
+	PreCreate Event = "pre-create"
+	// PostCreate runs after a memo is created.
+	PostCreate Event = "post-create"
+	// PostEdit runs after the content of a memo changed, by memo edit, memo append and the other editors.
+	PostEdit Event = "post-edit"
+	// PreDelete runs before a memo is deleted.
+	PreDelete Event = "pre-delete"
+)
+
+// waitDelay bounds how long a killed hook may keep its output open, as its own children may outlive it.
+const waitDelay = time.Second
+
+// ErrFailed is wrapped by the errors of pre-hooks that exit with a non-zero status or time out.
+var ErrFailed = errors.New("hook failed")
+
+// Runner runs the configured hooks.
+type Runner struct {
+	hooks   config.Hooks
+	timeout time.Duration
+	root    string
+	output  io.Writer
+}
+
+// New returns a Runner of the hooks configured in cfg, for the memos of the memo root root.
+// The output of hooks, and warnings about failed post-hooks, are written to output.
+func New(cfg *config.Config, root string, output io.Writer) *Runner {
+	return &Runner{hooks: cfg.Hooks, timeout: cfg.HookTimeout(), root: root, output: output}
+}
+
+// Run runs the hook of event for m, if one is configured. A nil Runner runs nothing.
+// A failing pre-hook returns an error wrapping ErrFailed, which must abort the operation.
+// Post-hooks run once the operation is done, so their failures are only reported to the output.
+func (r *Runner) Run(event Event, m schema.Memo) error {
+	if r == nil {
+		return nil
+	}
+
+	command := r.command(event)
+	if command.IsZero() {
+		return nil
+	}
+
+	err := r.run(event, command, m)
+	if err == nil {
+		return nil
+	}
+	if event == PreCreate || event == PreDelete {
+		return err
+	}
+	fmt.Fprintf(r.output, "⚠️  Warning: %v\n\n", err)
+	return nil
+}
+
+func (r *Runner) command(event Event) config.Hook {
+	switch event {
+	case PreCreate:
+		return r.hooks.PreCreate
+	case PostCreate:
+		return r.hooks.PostCreate
+	case PostEdit:
+		return r.hooks.PostEdit
+	case PreDelete:
+		return r.hooks.PreDelete
+	default:
+		return config.Hook{}
+	}
+}
+
+func (r *Runner) run(event Event, command config.Hook, m schema.Memo) error {
+	payload, err := json.Marshal(schema.Hook{Memo: m, Hook: string(event)})
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), r.timeout)
+	defer cancel()
+
+	var cmd *exec.Cmd
+	if command.Shell != "" {
+		cmd = ShellCommand(ctx, command.Shell)
+	} else {
+		//nolint:gosec // hooks are configured by the user on purpose
+		cmd = exec.CommandContext(ctx, command.Args[0], command.Args[1:]...)
+	}
+	cmd.Env = append(os.Environ(),
+		"MEMO_HOOK="+string(event),
+		"MEMO_ROOT_DIR="+r.root,
+		"MEMO_PATH="+m.Path,
+		"MEMO_REL_PATH="+m.RelPath,
+		"MEMO_NAME="+m.Name,
+		"MEMO_EXT="+m.Ext,
+		"MEMO_ENCRYPTED="+strconv.FormatBool(m.Encrypted),
+	)
+	cmd.Stdin = bytes.NewReader(append(payload, '\n'))
+	var output bytes.Buffer
+	cmd.Stdout = &output
+	cmd.Stderr = &output
+	cmd.WaitDelay = waitDelay
+
+	runErr := cmd.Run()
+	if runErr == nil {
+		_, _ = r.output.Write(output.Bytes())
+		return nil
+	}
+
+	if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+		runErr = fmt.Errorf("timed out after %s", r.timeout)
+	}
+	message := fmt.Sprintf("%s hook for %s: %v", event, m.RelPath, runErr)
+	if detail := strings.TrimSpace(output.String()); detail != "" {
+		message += ": " + detail
+	}
+	return fmt.Errorf("%w: %s", ErrFailed, message)
+}
+
+// ShellCommand returns a command running line in the shell: sh -c, or cmd /C on Windows.
+func ShellCommand(ctx context.Context, line string) *exec.Cmd {
+	if runtime.GOOS == "windows" {
+		//nolint:gosec // the command is chosen by the user on purpose
+		return exec.CommandContext(ctx, "cmd", "/C", line)
+	}
+	//nolint:gosec // the command is chosen by the user on purpose
+	return exec.CommandContext(ctx, "sh", "-c", line)
+}
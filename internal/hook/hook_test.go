@@ -0,0 +1,90 @@
+package hook_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/sushichan044/memo-cli/internal/config"
+	"github.com/sushichan044/memo-cli/internal/hook"
+	"github.com/sushichan044/memo-cli/schema"
+)
+
+//nolint:gochecknoglobals // test fixture
+var notes = schema.Memo{
+	Path:    "/memo/20251031/14-30-45-notes.md",
+	RelPath: "20251031/14-30-45-notes.md",
+	DateDir: "20251031",
+	Name:    "notes",
+	Ext:     "md",
+}
+
+func newRunner(t *testing.T, hooks config.Hooks) (*hook.Runner, *bytes.Buffer) {
+	t.Helper()
+	if runtime.GOOS == "windows" {
+		t.Skip("hooks are tested with sh")
+	}
+
+	var output bytes.Buffer
+	return hook.New(&config.Config{Hooks: hooks}, "/memo", &output), &output
+}
+
+func TestRunner_Run(t *testing.T) {
+	dir := t.TempDir()
+	runner, output := newRunner(t, config.Hooks{
+		PostCreate: config.Hook{Shell: `echo "$MEMO_HOOK $MEMO_REL_PATH $MEMO_NAME $MEMO_EXT $MEMO_ENCRYPTED $MEMO_ROOT_DIR"; cat > ` + filepath.Join(dir, "stdin.json")},
+	})
+
+	require.NoError(t, runner.Run(hook.PostCreate, notes))
+	assert.Equal(t, "post-create 20251031/14-30-45-notes.md notes md false /memo\n", output.String())
+
+	data, err := os.ReadFile(filepath.Join(dir, "stdin.json"))
+	require.NoError(t, err)
+	var payload schema.Hook
+	require.NoError(t, json.Unmarshal(data, &payload))
+	assert.Equal(t, schema.Hook{Memo: notes, Hook: "post-create"}, payload)
+
+	// Events without a hook run nothing, and neither does a nil Runner.
+	output.Reset()
+	require.NoError(t, runner.Run(hook.PreDelete, notes))
+	require.NoError(t, (*hook.Runner)(nil).Run(hook.PostCreate, notes))
+	assert.Empty(t, output.String())
+}
+
+func TestRunner_Args(t *testing.T) {
+	runner, output := newRunner(t, config.Hooks{PostEdit: config.Hook{Args: []string{"echo", "$MEMO_PATH", "edited"}}})
+
+	require.NoError(t, runner.Run(hook.PostEdit, notes))
+	assert.Equal(t, "$MEMO_PATH edited\n", output.String(), "executables run without a shell")
+}
+
+func TestRunner_Failure(t *testing.T) {
+	failing := config.Hook{Shell: "echo not on a Friday >&2; exit 3"}
+	runner, output := newRunner(t, config.Hooks{PreDelete: failing, PostEdit: failing})
+
+	err := runner.Run(hook.PreDelete, notes)
+	require.ErrorIs(t, err, hook.ErrFailed)
+	assert.Contains(t, err.Error(), "pre-delete hook for 20251031/14-30-45-notes.md: exit status 3: not on a Friday")
+	assert.Empty(t, output.String())
+
+	// A failed post-hook cannot undo the operation; it is reported as a warning.
+	require.NoError(t, runner.Run(hook.PostEdit, notes))
+	assert.Contains(t, output.String(), "Warning: hook failed: post-edit hook")
+}
+
+func TestRunner_Timeout(t *testing.T) {
+	runner, _ := newRunner(t, config.Hooks{PreCreate: config.Hook{Shell: "sleep 5"}, Timeout: 100 * time.Millisecond})
+
+	start := time.Now()
+	err := runner.Run(hook.PreCreate, notes)
+	require.ErrorIs(t, err, hook.ErrFailed)
+	assert.Contains(t, err.Error(), "timed out after 100ms")
+	assert.Less(t, time.Since(start), 3*time.Second)
+}
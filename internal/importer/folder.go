@@ -0,0 +1,47 @@
+package importer
+
+import (
+	"io/fs"
+)
+
+// Folder imports the Markdown and text files of any directory, such as a folder of date-named notes
+// ("2025-10-31.md", "2025-10-31 standup.md") or of notes grouped by day ("2025/10/31/standup.md").
+type Folder struct{}
+
+// Name implements Importer.
+func (Folder) Name() string { return "folder" }
+
+// Detect implements Importer.
+func (Folder) Detect(fsys fs.FS, name string) bool {
+	info, err := fs.Stat(fsys, name)
+	return err == nil && info.IsDir()
+}
+
+// Notes implements Importer.
+func (Folder) Notes(fsys fs.FS, name string) ([]Note, error) {
+	return dirNotes(fsys, name, []string{"md", "markdown", "txt"})
+}
+
+// dirNotes reads the files with one of exts under dir as notes.
+// Sources are relative to dir, so that they read the same wherever the directory is.
+func dirNotes(fsys fs.FS, dir string, exts []string) ([]Note, error) {
+	sub, err := fs.Sub(fsys, dir)
+	if err != nil {
+		return nil, err
+	}
+
+	var notes []Note
+	err = walkNotes(sub, ".", exts, func(name string) error {
+		note, noteErr := fileNote(sub, name)
+		if noteErr != nil {
+			return noteErr
+		}
+		notes = append(notes, note)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return notes, nil
+}
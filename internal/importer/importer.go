@@ -0,0 +1,278 @@
+// Package importer converts notes kept by other tools into memos.
+//
+// An Importer reads a source, such as an Obsidian vault or a jrnl file, into Notes. A Note carries
+// the time it was written, derived from front matter, the file name or the file modification time,
+// and maps onto the memo layout YYYYMMDD/HH-MM-SS-name.ext. Notes are written with transfer.Import,
+// which skips duplicates and renames memos whose file name is taken.
+package importer
+
+import (
+	"io/fs"
+	"path"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/sushichan044/memo-cli/internal/markdown"
+	"github.com/sushichan044/memo-cli/internal/memo"
+	"github.com/sushichan044/memo-cli/internal/transfer"
+)
+
+// TimeSource tells where the time of a Note comes from.
+type TimeSource string
+
+const (
+	// TimeFrontMatter is a "created" or "date" front matter field.
+	TimeFrontMatter TimeSource = "front matter"
+	// TimeFileName is a date, and optionally a time, in the file or directory name.
+	TimeFileName TimeSource = "file name"
+	// TimeEntry is the timestamp of a journal entry.
+	TimeEntry TimeSource = "entry"
+	// TimeModified is the modification time of the file, used when nothing else tells the time.
+	TimeModified TimeSource = "mtime"
+)
+
+// Importer reads the notes of another tool.
+type Importer interface {
+	// Name identifies the importer on the command line (e.g. "obsidian").
+	Name() string
+	// Detect reports whether name in fsys, a file or a directory, looks like input for this importer.
+	Detect(fsys fs.FS, name string) bool
+	// Notes reads the notes of name in fsys.
+	Notes(fsys fs.FS, name string) ([]Note, error)
+}
+
+// All returns the available importers, in the order Detect tries them.
+func All() []Importer {
+	return []Importer{Obsidian{}, Jrnl{}, Folder{}}
+}
+
+// Lookup returns the importer called name.
+func Lookup(name string) (Importer, bool) {
+	for _, imp := range All() {
+		if imp.Name() == name {
+			return imp, true
+		}
+	}
+	return nil, false
+}
+
+// Detect returns the first importer that recognizes name in fsys.
+func Detect(fsys fs.FS, name string) (Importer, bool) {
+	for _, imp := range All() {
+		if imp.Detect(fsys, name) {
+			return imp, true
+		}
+	}
+	return nil, false
+}
+
+// Note is a note to be imported as a memo.
+type Note struct {
+	// Source is the slash-separated path of the original note, with a "#N" suffix for the N-th
+	// entry of a file holding several notes.
+	Source string
+	// Name is the memo name, normalized like `memo new` names. It is empty for timestamp-only memos.
+	Name string
+	// Ext is the memo extension without the leading dot.
+	Ext string
+	// Time is when the note was written, and TimeSource where that time comes from.
+	Time       time.Time
+	TimeSource TimeSource
+	// Daily maps the note onto the daily note of its day (YYYYMMDD/daily.ext).
+	Daily   bool
+	Content []byte
+	// ModTime is the modification time the memo file is given.
+	ModTime time.Time
+}
+
+// RelPath returns the memo path of the note.
+func (n Note) RelPath() (string, error) {
+	if n.Daily {
+		return memo.DateDir(n.Time) + "/" + memo.DailyName + "." + n.Ext, nil
+	}
+	return memo.RelPath(n.Time, n.Name, n.Ext)
+}
+
+// Items converts notes into the memo files written by transfer.Import.
+// Notes that cannot be named as memos, for example because the name is too long, are returned as skipped.
+func Items(notes []Note) ([]transfer.Item, []transfer.Skipped) {
+	var (
+		items   []transfer.Item
+		skipped []transfer.Skipped
+	)
+	for _, note := range notes {
+		name, err := note.RelPath()
+		if err != nil {
+			skipped = append(skipped, transfer.Skipped{Name: note.Source, Reason: err.Error()})
+			continue
+		}
+		items = append(items, transfer.Item{Name: name, Content: note.Content, ModTime: note.ModTime})
+	}
+	return items, skipped
+}
+
+// fileNote builds the note of a Markdown or text file, taking its time from, in order,
+// a "created" or "date" front matter field, a date in the file name or in the names of its
+// parent directories, and the modification time. A file named only by a date becomes a daily note.
+func fileNote(fsys fs.FS, name string) (Note, error) {
+	content, err := fs.ReadFile(fsys, name)
+	if err != nil {
+		return Note{}, err
+	}
+	info, err := fs.Stat(fsys, name)
+	if err != nil {
+		return Note{}, err
+	}
+
+	ext := strings.TrimPrefix(path.Ext(name), ".")
+	base := strings.TrimSuffix(path.Base(name), path.Ext(name))
+	note := Note{Source: name, Name: base, Ext: strings.ToLower(ext), Content: content, ModTime: info.ModTime()}
+
+	date, hasTime, rest, dated := parseDatedName(base)
+	if !dated {
+		date, dated = parseDatedDirs(path.Dir(name))
+		hasTime, rest = false, base
+	}
+	if dated {
+		note.Name = rest
+	}
+
+	switch t, dateOnly, ok := frontMatterTime(content); {
+	case ok:
+		note.Time, note.TimeSource = t, TimeFrontMatter
+		note.Daily = dateOnly && note.Name == ""
+	case dated:
+		note.Time, note.TimeSource = date, TimeFileName
+		note.Daily = !hasTime && note.Name == ""
+	default:
+		note.Name = base
+		note.Time, note.TimeSource = info.ModTime(), TimeModified
+	}
+
+	return note, nil
+}
+
+// frontMatterTimeLayouts are the layouts accepted in "created" and "date" front matter fields.
+//
+//nolint:gochecknoglobals // constant table
+var frontMatterTimeLayouts = []string{
+	time.RFC3339,
+	"2006-01-02T15:04:05",
+	"2006-01-02 15:04:05",
+	"2006-01-02T15:04",
+	"2006-01-02 15:04",
+}
+
+// frontMatterTime returns the time of a "created" or "date" front matter field, and whether it has no time of day.
+func frontMatterTime(content []byte) (time.Time, bool, bool) {
+	for _, key := range []string{"created", "date"} {
+		value, ok := markdown.FrontMatterValue(content, key)
+		if !ok {
+			continue
+		}
+		for _, layout := range frontMatterTimeLayouts {
+			if t, err := time.ParseInLocation(layout, value, time.Local); err == nil {
+				return t.Local(), false, true
+			}
+		}
+		if t, err := time.ParseInLocation(time.DateOnly, value, time.Local); err == nil {
+			return t, true, true
+		}
+	}
+	return time.Time{}, false, false
+}
+
+// datedNamePattern matches a file name starting with a date and an optional time,
+// such as "2025-10-31", "20251031-1430 standup" or "2025-10-31_14-30-05-notes".
+var datedNamePattern = regexp.MustCompile(
+	`^(\d{4})-?(\d{2})-?(\d{2})(?:[ T_-]+(\d{2})[-:.h]?(\d{2})(?:[-:.]?(\d{2}))?)?(?:[ _-]+(.*))?$`)
+
+// datedDirPattern matches a directory named by a date, such as "2025-10-31" or "20251031".
+var datedDirPattern = regexp.MustCompile(`^(\d{4})-?(\d{2})-?(\d{2})$`)
+
+// parseDatedName parses a file name starting with a date.
+// It returns the time, whether it has a time of day, and the rest of the name.
+func parseDatedName(base string) (time.Time, bool, string, bool) {
+	m := datedNamePattern.FindStringSubmatch(base)
+	if m == nil {
+		return time.Time{}, false, "", false
+	}
+
+	hasTime := m[4] != ""
+	clock := []string{"00", "00", "00"}
+	if hasTime {
+		clock = []string{m[4], m[5], m[6]}
+		if clock[2] == "" {
+			clock[2] = "00"
+		}
+	}
+	t, ok := makeTime(m[1], m[2], m[3], clock[0], clock[1], clock[2])
+	if !ok {
+		return time.Time{}, false, "", false
+	}
+
+	return t, hasTime, strings.TrimSpace(m[7]), true
+}
+
+// parseDatedDirs finds a date in the directories of a path: "2025-10-31/", "20251031/" or "2025/10/31/".
+func parseDatedDirs(dir string) (time.Time, bool) {
+	parts := strings.Split(dir, "/")
+	for i := len(parts) - 1; i >= 0; i-- {
+		if m := datedDirPattern.FindStringSubmatch(parts[i]); m != nil {
+			return makeTime(m[1], m[2], m[3], "00", "00", "00")
+		}
+		if i >= 2 && len(parts[i-2]) == 4 && len(parts[i-1]) == 2 && len(parts[i]) == 2 {
+			if t, ok := makeTime(parts[i-2], parts[i-1], parts[i], "00", "00", "00"); ok {
+				return t, true
+			}
+		}
+	}
+	return time.Time{}, false
+}
+
+func makeTime(year, month, day, hour, minute, second string) (time.Time, bool) {
+	fields := make([]int, 0, 6) //nolint:mnd // year to second
+	for _, s := range []string{year, month, day, hour, minute, second} {
+		n, err := strconv.Atoi(s)
+		if err != nil {
+			return time.Time{}, false
+		}
+		fields = append(fields, n)
+	}
+
+	t := time.Date(fields[0], time.Month(fields[1]), fields[2], fields[3], fields[4], fields[5], 0, time.Local)
+	// time.Date normalizes out-of-range values, such as the 13th month; reject them instead.
+	if t.Month() != time.Month(fields[1]) || t.Day() != fields[2] || t.Hour() != fields[3] ||
+		t.Minute() != fields[4] || t.Second() != fields[5] {
+		return time.Time{}, false
+	}
+	return t, true
+}
+
+// walkNotes calls fn for every file with one of exts under dir, skipping hidden files and directories
+// such as ".obsidian" and ".trash".
+func walkNotes(fsys fs.FS, dir string, exts []string, fn func(name string) error) error {
+	return fs.WalkDir(fsys, dir, func(name string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if name != dir && strings.HasPrefix(d.Name(), ".") {
+			if d.IsDir() {
+				return fs.SkipDir
+			}
+			return nil
+		}
+		if !d.Type().IsRegular() {
+			return nil
+		}
+		ext := strings.ToLower(strings.TrimPrefix(path.Ext(name), "."))
+		for _, want := range exts {
+			if ext == want {
+				return fn(name)
+			}
+		}
+		return nil
+	})
+}
@@ -0,0 +1,151 @@
+package importer_test
+
+import (
+	"testing"
+	"testing/fstest"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/sushichan044/memo-cli/internal/importer"
+)
+
+var mtime = time.Date(2025, 11, 2, 18, 0, 0, 0, time.Local) //nolint:gochecknoglobals // test fixture
+
+func file(content string) *fstest.MapFile {
+	return &fstest.MapFile{Data: []byte(content), ModTime: mtime}
+}
+
+// relPaths maps the source of each note to its memo path and time source.
+func relPaths(t *testing.T, notes []importer.Note) map[string]string {
+	t.Helper()
+
+	paths := map[string]string{}
+	for _, note := range notes {
+		rel, err := note.RelPath()
+		require.NoError(t, err)
+		paths[note.Source] = rel + " (" + string(note.TimeSource) + ")"
+	}
+	return paths
+}
+
+func TestDetect(t *testing.T) {
+	fsys := fstest.MapFS{
+		"vault/.obsidian/app.json": file("{}"),
+		"vault/note.md":            file("# Note\n"),
+		"notes/2025-10-31.md":      file("# Day\n"),
+		"journal.txt":              file("\n[2025-10-31 09:15] Standup.\n"),
+		"export.jsonl":             file(`{"rel_path":"20251031/daily.md"}` + "\n"),
+	}
+
+	for name, want := range map[string]string{
+		"vault":        "obsidian",
+		"notes":        "folder",
+		"journal.txt":  "jrnl",
+		"export.jsonl": "",
+	} {
+		imp, ok := importer.Detect(fsys, name)
+		if want == "" {
+			assert.False(t, ok, name)
+			continue
+		}
+		require.True(t, ok, name)
+		assert.Equal(t, want, imp.Name(), name)
+	}
+}
+
+func TestLookup(t *testing.T) {
+	imp, ok := importer.Lookup("jrnl")
+	require.True(t, ok)
+	assert.Equal(t, "jrnl", imp.Name())
+
+	_, ok = importer.Lookup("evernote")
+	assert.False(t, ok)
+}
+
+func TestObsidian_Notes(t *testing.T) {
+	fsys := fstest.MapFS{
+		"vault/.obsidian/app.json":          file("{}"),
+		"vault/.trash/old.md":               file("# Old\n"),
+		"vault/Daily/2025-10-31.md":         file("# 2025-10-31\n"),
+		"vault/Meeting notes.md":            file("---\ncreated: 2025-10-30T14:30:00\n---\n# Meeting\n"),
+		"vault/Ideas/Dated.md":              file("---\ndate: \"2025-10-29\"\n---\n# Dated\n"),
+		"vault/Ideas/Undated.md":            file("# Undated\n"),
+		"vault/attachments/diagram.png":     file("\x89PNG"),
+		"vault/2025-10-28 1015 retro.md":    file("# Retro\n"),
+		"vault/Projects/2025-10-27 kick.md": file("# Kickoff\n"),
+	}
+
+	notes, err := importer.Obsidian{}.Notes(fsys, "vault")
+	require.NoError(t, err)
+
+	assert.Equal(t, map[string]string{
+		"Daily/2025-10-31.md":         "20251031/daily.md (file name)",
+		"Meeting notes.md":            "20251030/14-30-00-Meeting-notes.md (front matter)",
+		"Ideas/Dated.md":              "20251029/00-00-00-Dated.md (front matter)",
+		"Ideas/Undated.md":            "20251102/18-00-00-Undated.md (mtime)",
+		"2025-10-28 1015 retro.md":    "20251028/10-15-00-retro.md (file name)",
+		"Projects/2025-10-27 kick.md": "20251027/00-00-00-kick.md (file name)",
+	}, relPaths(t, notes))
+}
+
+func TestFolder_Notes(t *testing.T) {
+	fsys := fstest.MapFS{
+		"notes/2025/10/31/standup.md":  file("# Standup\n"),
+		"notes/20251030/todo.txt":      file("buy milk\n"),
+		"notes/2025-10-29_21-05-07.md": file("# Late\n"),
+		"notes/2025-13-01.md":          file("# Not a date\n"),
+		"notes/image.jpg":              file("\xff\xd8"),
+	}
+
+	notes, err := importer.Folder{}.Notes(fsys, "notes")
+	require.NoError(t, err)
+
+	assert.Equal(t, map[string]string{
+		"2025/10/31/standup.md":  "20251031/00-00-00-standup.md (file name)",
+		"20251030/todo.txt":      "20251030/00-00-00-todo.txt (file name)",
+		"2025-10-29_21-05-07.md": "20251029/21-05-07.md (file name)",
+		"2025-13-01.md":          "20251102/18-00-00-2025-13-01.md (mtime)",
+	}, relPaths(t, notes))
+}
+
+func TestJrnl_Notes(t *testing.T) {
+	fsys := fstest.MapFS{
+		"journal.txt": file(
+			"[2025-10-31 09:15] Standup with @team. Discussed the release.\n" +
+				"Blocked on review.\n" +
+				"\n" +
+				"[2025-10-31 02:30:05 PM] * Shipped it!\n" +
+				"\n" +
+				"[2025-11-01 12:00 AM] A very long title that goes on and on well past the name limit\n"),
+	}
+
+	notes, err := importer.Jrnl{}.Notes(fsys, "journal.txt")
+	require.NoError(t, err)
+	require.Len(t, notes, 3)
+
+	assert.Equal(t, map[string]string{
+		"journal.txt#1": "20251031/09-15-00-Standup-with-team.md (entry)",
+		"journal.txt#2": "20251031/14-30-05-Shipped-it.md (entry)",
+		"journal.txt#3": "20251101/00-00-00-A-very-long-title-that-goes-on-and-on-well-past.md (entry)",
+	}, relPaths(t, notes))
+
+	assert.Equal(t, "# Standup with #team.\n\nDiscussed the release.\nBlocked on review.\n", string(notes[0].Content))
+	assert.Equal(t, "# Shipped it!\n", string(notes[1].Content))
+	assert.Equal(t, notes[0].Time, notes[0].ModTime)
+}
+
+func TestItems(t *testing.T) {
+	at := time.Date(2025, 10, 31, 9, 15, 0, 0, time.Local)
+	items, skipped := importer.Items([]importer.Note{
+		{Source: "a.md", Name: "a", Ext: "md", Time: at, Content: []byte("a"), ModTime: mtime},
+		{Source: "b.md", Name: "b", Ext: "m?d", Time: at},
+	})
+
+	require.Len(t, items, 1)
+	assert.Equal(t, "20251031/09-15-00-a.md", items[0].Name)
+	assert.Equal(t, mtime, items[0].ModTime)
+	require.Len(t, skipped, 1)
+	assert.Equal(t, "b.md", skipped[0].Name)
+}
@@ -0,0 +1,178 @@
+package importer
+
+import (
+	"bufio"
+	"bytes"
+	"io/fs"
+	"path"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// maxJrnlNameRunes caps the memo name derived from the title of a jrnl entry.
+const maxJrnlNameRunes = 48
+
+// Jrnl imports a jrnl (https://jrnl.sh) plain text journal, in which each entry starts with a
+// "[2025-10-31 09:15]" timestamp followed by its title. Each entry becomes a memo named after its title,
+// with @tags rewritten as #tags.
+type Jrnl struct{}
+
+// jrnlHeaderPattern matches the first line of a jrnl entry, with or without seconds and AM/PM.
+var jrnlHeaderPattern = regexp.MustCompile(
+	`^\[(\d{4}-\d{2}-\d{2}) (\d{1,2}):(\d{2})(?::(\d{2}))?(?: ?([AaPp][Mm]))?\] ?(.*)$`)
+
+// jrnlTagPattern matches a jrnl @tag.
+var jrnlTagPattern = regexp.MustCompile(`(^|[\s(])@([\p{L}\p{N}_][\p{L}\p{N}_/-]*)`)
+
+// Name implements Importer.
+func (Jrnl) Name() string { return "jrnl" }
+
+// Detect implements Importer. A journal is a file whose first non-blank line is an entry header.
+func (Jrnl) Detect(fsys fs.FS, name string) bool {
+	f, err := fsys.Open(name)
+	if err != nil {
+		return false
+	}
+	defer f.Close()
+
+	if info, statErr := f.Stat(); statErr != nil || info.IsDir() {
+		return false
+	}
+
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+		_, _, ok := parseJrnlHeader(line)
+		return ok
+	}
+	return false
+}
+
+// Notes implements Importer.
+func (Jrnl) Notes(fsys fs.FS, name string) ([]Note, error) {
+	content, err := fs.ReadFile(fsys, name)
+	if err != nil {
+		return nil, err
+	}
+
+	var (
+		notes []Note
+		title string
+		at    time.Time
+		body  []string
+	)
+	flush := func() {
+		if at.IsZero() {
+			return
+		}
+		notes = append(notes, jrnlNote(path.Base(name)+"#"+strconv.Itoa(len(notes)+1), at, title, body))
+	}
+
+	for line := range strings.Lines(string(bytes.ReplaceAll(content, []byte("\r\n"), []byte("\n")))) {
+		line = strings.TrimSuffix(line, "\n")
+		if t, rest, ok := parseJrnlHeader(line); ok {
+			flush()
+			at = t
+			title, body = splitJrnlTitle(rest)
+			continue
+		}
+		body = append(body, line)
+	}
+	flush()
+
+	return notes, nil
+}
+
+// parseJrnlHeader parses an entry header line into its time and the text that follows it.
+func parseJrnlHeader(line string) (time.Time, string, bool) {
+	m := jrnlHeaderPattern.FindStringSubmatch(line)
+	if m == nil {
+		return time.Time{}, "", false
+	}
+
+	hour, _ := strconv.Atoi(m[2])
+	minute, _ := strconv.Atoi(m[3])
+	second, _ := strconv.Atoi(m[4])
+	switch strings.ToLower(m[5]) {
+	case "am":
+		if hour == 12 { //nolint:mnd // 12 AM is midnight
+			hour = 0
+		}
+	case "pm":
+		if hour < 12 { //nolint:mnd // 12 PM is noon
+			hour += 12
+		}
+	}
+
+	t, ok := makeTime(m[1][:4], m[1][5:7], m[1][8:], strconv.Itoa(hour), strconv.Itoa(minute), strconv.Itoa(second))
+	if !ok {
+		return time.Time{}, "", false
+	}
+	return t, m[6], true
+}
+
+// splitJrnlTitle splits the text of a header line into the entry title, its first sentence,
+// and the start of the body. Stars marking favorite entries are dropped.
+func splitJrnlTitle(text string) (string, []string) {
+	text = strings.TrimSpace(strings.TrimSuffix(strings.TrimPrefix(strings.TrimSpace(text), "*"), "*"))
+
+	for i := range len(text) - 1 {
+		if strings.ContainsRune(".?!", rune(text[i])) && text[i+1] == ' ' {
+			return text[:i+1], []string{strings.TrimSpace(text[i+1:])}
+		}
+	}
+	return text, nil
+}
+
+// jrnlNote builds the note of an entry.
+func jrnlNote(source string, at time.Time, title string, body []string) Note {
+	text := strings.TrimSpace(strings.Join(body, "\n"))
+
+	var content strings.Builder
+	if title != "" {
+		content.WriteString("# " + jrnlTags(title) + "\n")
+		if text != "" {
+			content.WriteString("\n")
+		}
+	}
+	if text != "" {
+		content.WriteString(jrnlTags(text) + "\n")
+	}
+
+	return Note{
+		Source:     source,
+		Name:       jrnlName(title),
+		Ext:        "md",
+		Time:       at,
+		TimeSource: TimeEntry,
+		Content:    []byte(content.String()),
+		ModTime:    at,
+	}
+}
+
+// jrnlTags rewrites jrnl @tags as #tags.
+func jrnlTags(s string) string {
+	return jrnlTagPattern.ReplaceAllString(s, "$1#$2")
+}
+
+// jrnlName derives a memo name from an entry title: the title without @tags and its final punctuation,
+// cut at a word boundary to at most maxJrnlNameRunes runes.
+func jrnlName(title string) string {
+	name := strings.Join(strings.Fields(jrnlTagPattern.ReplaceAllString(title, "$1$2")), " ")
+	name = strings.TrimRight(name, ".?!")
+
+	runes := []rune(name)
+	if len(runes) <= maxJrnlNameRunes {
+		return name
+	}
+	cut := string(runes[:maxJrnlNameRunes])
+	if i := strings.LastIndexByte(cut, ' '); i > 0 {
+		cut = cut[:i]
+	}
+	return strings.TrimSpace(cut)
+}
@@ -0,0 +1,24 @@
+package importer
+
+import (
+	"io/fs"
+	"path"
+)
+
+// Obsidian imports the notes of an Obsidian vault, a directory holding a ".obsidian" settings directory.
+// Daily notes named by their date (e.g. "2025-10-31.md") become memo daily notes.
+type Obsidian struct{}
+
+// Name implements Importer.
+func (Obsidian) Name() string { return "obsidian" }
+
+// Detect implements Importer.
+func (Obsidian) Detect(fsys fs.FS, name string) bool {
+	info, err := fs.Stat(fsys, path.Join(name, ".obsidian"))
+	return err == nil && info.IsDir()
+}
+
+// Notes implements Importer. Attachments and hidden directories, such as ".trash", are left out.
+func (Obsidian) Notes(fsys fs.FS, name string) ([]Note, error) {
+	return dirNotes(fsys, name, []string{"md"})
+}
@@ -0,0 +1,190 @@
+// Package index maintains a trigram index of memo content, which lets searches skip memos that cannot match.
+//
+// The index is a cache: every memo records the size and modification time it was indexed at,
+// and memos that changed since, or were never indexed, are always searched.
+// Encrypted memos are never indexed, so no trace of their plaintext is written to disk.
+package index
+
+import (
+	"bytes"
+	"encoding/gob"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"time"
+
+	"github.com/sushichan044/memo-cli/internal/memo"
+	"github.com/sushichan044/memo-cli/internal/memofs"
+)
+
+// version is bumped whenever the encoding of the index changes; older indexes are discarded.
+const version = 1
+
+// Index maps memos to the trigrams of their content.
+type Index struct {
+	docs map[string]doc
+}
+
+// doc is the indexed state of a memo.
+type doc struct {
+	Size    int64
+	ModTime time.Time
+	// Trigrams are the sorted, distinct trigrams of the lowercased content.
+	Trigrams []uint32
+}
+
+// file is the on-disk encoding of an Index.
+type file struct {
+	Version int
+	Docs    map[string]doc
+}
+
+// New returns an empty index.
+func New() *Index {
+	return &Index{docs: make(map[string]doc)}
+}
+
+// Load reads the index saved at path.
+// A missing, unreadable or outdated index yields an empty one, to be rebuilt by Sync.
+func Load(path string) (*Index, error) {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return New(), nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read search index: %w", err)
+	}
+
+	var f file
+	if decodeErr := gob.NewDecoder(bytes.NewReader(data)).Decode(&f); decodeErr != nil || f.Version != version {
+		return New(), nil //nolint:nilerr // a corrupt cache is rebuilt
+	}
+	if f.Docs == nil {
+		f.Docs = make(map[string]doc)
+	}
+	return &Index{docs: f.Docs}, nil
+}
+
+// Save writes the index to path, creating its directory. The file is replaced atomically.
+func (ix *Index) Save(path string) error {
+	var buf bytes.Buffer
+	if err := gob.NewEncoder(&buf).Encode(file{Version: version, Docs: ix.docs}); err != nil {
+		return fmt.Errorf("failed to encode search index: %w", err)
+	}
+
+	dir := filepath.Dir(path)
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return fmt.Errorf("failed to save search index: %w", err)
+	}
+	if err := memofs.NewOS(dir).WriteFile(filepath.Base(path), buf.Bytes(), 0o600); err != nil {
+		return fmt.Errorf("failed to save search index: %w", err)
+	}
+	return nil
+}
+
+// Len returns the number of indexed memos.
+func (ix *Index) Len() int {
+	return len(ix.docs)
+}
+
+// Update indexes the current content of entry, read from fsys.
+// Encrypted memos are removed from the index instead.
+func (ix *Index) Update(fsys memofs.FS, entry memo.Entry) error {
+	if entry.Encrypted {
+		ix.Remove(entry.RelPath)
+		return nil
+	}
+
+	info, err := fsys.Stat(entry.RelPath)
+	if err != nil {
+		return fmt.Errorf("failed to index %s: %w", entry.RelPath, err)
+	}
+	content, err := fsys.ReadFile(entry.RelPath)
+	if err != nil {
+		return fmt.Errorf("failed to index %s: %w", entry.RelPath, err)
+	}
+
+	ix.docs[entry.RelPath] = doc{Size: info.Size(), ModTime: info.ModTime(), Trigrams: trigrams(content)}
+	return nil
+}
+
+// Remove drops the memo at relPath from the index, reporting whether it was indexed.
+func (ix *Index) Remove(relPath string) bool {
+	_, ok := ix.docs[relPath]
+	delete(ix.docs, relPath)
+	return ok
+}
+
+// Sync brings the index up to date with entries, the complete list of memos:
+// memos that changed since they were indexed are reindexed and memos that are gone are removed.
+// It reports whether the index changed.
+func (ix *Index) Sync(fsys memofs.FS, entries []memo.Entry) (bool, error) {
+	changed := false
+	current := make(map[string]bool, len(entries))
+	for _, entry := range entries {
+		current[entry.RelPath] = true
+		if entry.Encrypted {
+			changed = ix.Remove(entry.RelPath) || changed
+			continue
+		}
+		if ix.fresh(fsys, entry) {
+			continue
+		}
+		if err := ix.Update(fsys, entry); err != nil {
+			return changed, err
+		}
+		changed = true
+	}
+
+	for relPath := range ix.docs {
+		if !current[relPath] {
+			delete(ix.docs, relPath)
+			changed = true
+		}
+	}
+	return changed, nil
+}
+
+// Candidates returns the entries that may contain a match of re, in order.
+// Memos that are not indexed, or changed since they were indexed, are always candidates.
+func (ix *Index) Candidates(fsys memofs.FS, entries []memo.Entry, re *regexp.Regexp) []memo.Entry {
+	required := requiredTrigrams(re)
+	if len(required) == 0 {
+		return entries
+	}
+
+	var candidates []memo.Entry
+	for _, entry := range entries {
+		if !ix.fresh(fsys, entry) || containsAll(ix.docs[entry.RelPath].Trigrams, required) {
+			candidates = append(candidates, entry)
+		}
+	}
+	return candidates
+}
+
+// fresh reports whether entry is indexed and unchanged since.
+func (ix *Index) fresh(fsys memofs.FS, entry memo.Entry) bool {
+	d, ok := ix.docs[entry.RelPath]
+	if !ok {
+		return false
+	}
+	info, err := fsys.Stat(entry.RelPath)
+	if err != nil {
+		return false
+	}
+	return info.Size() == d.Size && info.ModTime().Equal(d.ModTime)
+}
+
+// containsAll reports whether the sorted trigrams include every one of required.
+func containsAll(trigrams, required []uint32) bool {
+	for _, t := range required {
+		if _, found := slices.BinarySearch(trigrams, t); !found {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,134 @@
+package index_test
+
+import (
+	"os"
+	"path/filepath"
+	"regexp"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/sushichan044/memo-cli/internal/index"
+	"github.com/sushichan044/memo-cli/internal/memo"
+	"github.com/sushichan044/memo-cli/internal/memofs"
+)
+
+func setup(t *testing.T) (*memofs.Mem, []memo.Entry) {
+	t.Helper()
+
+	fsys := memofs.NewMem("/memo")
+	require.NoError(t, fsys.MkdirAll("20251031", 0o700))
+	for name, content := range map[string]string{
+		"20251031/09-00-00-plan.md":       "# Plan\n\nShip the Roadmap.\n",
+		"20251031/10-00-00-k8s.md":        "Upgrade the cluster.\n",
+		"20251031/11-00-00-secret.md.age": "ciphertext",
+	} {
+		require.NoError(t, fsys.WriteFile(name, []byte(content), 0o600))
+	}
+
+	entries, err := memo.List(fsys)
+	require.NoError(t, err)
+	return fsys, entries
+}
+
+func relPaths(entries []memo.Entry) []string {
+	paths := make([]string, 0, len(entries))
+	for _, e := range entries {
+		paths = append(paths, e.RelPath)
+	}
+	return paths
+}
+
+func TestIndex_Sync(t *testing.T) {
+	fsys, entries := setup(t)
+	ix := index.New()
+
+	changed, err := ix.Sync(fsys, entries)
+	require.NoError(t, err)
+	assert.True(t, changed)
+	assert.Equal(t, 2, ix.Len(), "encrypted memos are not indexed")
+
+	changed, err = ix.Sync(fsys, entries)
+	require.NoError(t, err)
+	assert.False(t, changed)
+
+	require.NoError(t, fsys.Remove("20251031/10-00-00-k8s.md"))
+	entries, err = memo.List(fsys)
+	require.NoError(t, err)
+	changed, err = ix.Sync(fsys, entries)
+	require.NoError(t, err)
+	assert.True(t, changed)
+	assert.Equal(t, 1, ix.Len())
+}
+
+func TestIndex_Candidates(t *testing.T) {
+	fsys, entries := setup(t)
+	ix := index.New()
+	_, err := ix.Sync(fsys, entries)
+	require.NoError(t, err)
+
+	tests := []struct {
+		pattern string
+		want    []string
+	}{
+		{"roadblock", []string{"20251031/11-00-00-secret.md.age"}},
+		{"Roadmap", []string{"20251031/11-00-00-secret.md.age", "20251031/09-00-00-plan.md"}},
+		{"(?i)ROADMAP", []string{"20251031/11-00-00-secret.md.age", "20251031/09-00-00-plan.md"}},
+		{"the (cluster|roadmap)", []string{"20251031/11-00-00-secret.md.age", "20251031/10-00-00-k8s.md", "20251031/09-00-00-plan.md"}},
+		{"Upgrade.*cluster", []string{"20251031/11-00-00-secret.md.age", "20251031/10-00-00-k8s.md"}},
+		{"(?:cluster)+", []string{"20251031/11-00-00-secret.md.age", "20251031/10-00-00-k8s.md"}},
+		{"xy", []string{"20251031/11-00-00-secret.md.age", "20251031/10-00-00-k8s.md", "20251031/09-00-00-plan.md"}},
+		// The Kelvin sign folds to k, whose lowercase form differs: the literal is split around it.
+		{"(?i)Keep", []string{"20251031/11-00-00-secret.md.age"}},
+		{"(?i)Kcluster", []string{"20251031/11-00-00-secret.md.age", "20251031/10-00-00-k8s.md"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.pattern, func(t *testing.T) {
+			got := ix.Candidates(fsys, entries, regexp.MustCompile(tt.pattern))
+			assert.Equal(t, tt.want, relPaths(got))
+		})
+	}
+}
+
+func TestIndex_CandidatesChanged(t *testing.T) {
+	fsys, entries := setup(t)
+	ix := index.New()
+	_, err := ix.Sync(fsys, entries)
+	require.NoError(t, err)
+
+	// A memo changed after it was indexed is searched until it is reindexed.
+	require.NoError(t, fsys.WriteFile("20251031/10-00-00-k8s.md", []byte("Ship the roadmap.\n"), 0o600))
+	re := regexp.MustCompile("roadmap")
+	assert.Contains(t, relPaths(ix.Candidates(fsys, entries, re)), "20251031/10-00-00-k8s.md")
+
+	require.NoError(t, ix.Update(fsys, entries[1]))
+	assert.Contains(t, relPaths(ix.Candidates(fsys, entries, re)), "20251031/10-00-00-k8s.md")
+	assert.True(t, ix.Remove("20251031/10-00-00-k8s.md"))
+	assert.False(t, ix.Remove("20251031/10-00-00-k8s.md"))
+}
+
+func TestIndex_SaveLoad(t *testing.T) {
+	fsys, entries := setup(t)
+	ix := index.New()
+	_, err := ix.Sync(fsys, entries)
+	require.NoError(t, err)
+
+	path := filepath.Join(t.TempDir(), "index", "memo.gob")
+	require.NoError(t, ix.Save(path))
+
+	loaded, err := index.Load(path)
+	require.NoError(t, err)
+	assert.Equal(t, 2, loaded.Len())
+	changed, err := loaded.Sync(fsys, entries)
+	require.NoError(t, err)
+	assert.False(t, changed)
+
+	// Missing and corrupt indexes are rebuilt from scratch.
+	require.NoError(t, os.WriteFile(path, []byte("garbage"), 0o600))
+	for _, p := range []string{path, filepath.Join(t.TempDir(), "missing.gob")} {
+		loaded, err = index.Load(p)
+		require.NoError(t, err)
+		assert.Zero(t, loaded.Len())
+	}
+}
@@ -0,0 +1,108 @@
+package index
+
+import (
+	"bytes"
+	"regexp"
+	"regexp/syntax"
+	"slices"
+	"strings"
+	"unicode"
+)
+
+// trigrams returns the sorted, distinct byte trigrams of content, lowercased.
+func trigrams(content []byte) []uint32 {
+	lower := bytes.ToLower(content)
+	if len(lower) < 3 { //nolint:mnd // trigram length
+		return nil
+	}
+
+	set := make(map[uint32]struct{}, len(lower))
+	for i := range len(lower) - 2 {
+		set[trigram(lower[i:])] = struct{}{}
+	}
+
+	result := make([]uint32, 0, len(set))
+	for t := range set {
+		result = append(result, t)
+	}
+	slices.Sort(result)
+	return result
+}
+
+// trigram packs the first three bytes of b.
+func trigram(b []byte) uint32 {
+	return uint32(b[0])<<16 | uint32(b[1])<<8 | uint32(b[2]) //nolint:mnd // byte shifts
+}
+
+// requiredTrigrams returns the trigrams that any text matching re contains, lowercased, sorted and distinct.
+// It is derived from the literal strings a match must include, and may be empty.
+func requiredTrigrams(re *regexp.Regexp) []uint32 {
+	parsed, err := syntax.Parse(re.String(), syntax.Perl)
+	if err != nil {
+		return nil
+	}
+
+	var result []uint32
+	for _, literal := range requiredLiterals(parsed.Simplify()) {
+		result = append(result, trigrams([]byte(literal))...)
+	}
+	slices.Sort(result)
+	return slices.Compact(result)
+}
+
+// requiredLiterals returns strings that every match of re contains.
+// Only concatenations, groups and repetitions of at least one are looked into;
+// alternations and optional parts contribute nothing, which keeps the result a safe under-approximation.
+func requiredLiterals(re *syntax.Regexp) []string {
+	switch re.Op { //nolint:exhaustive // other operators require no literal
+	case syntax.OpLiteral:
+		return literalParts(re)
+	case syntax.OpCapture, syntax.OpPlus:
+		return requiredLiterals(re.Sub[0])
+	case syntax.OpRepeat:
+		if re.Min >= 1 {
+			return requiredLiterals(re.Sub[0])
+		}
+	case syntax.OpConcat:
+		var result []string
+		// Adjacent literals with the same flags join into longer strings, which yield more trigrams.
+		for i := 0; i < len(re.Sub); i++ {
+			sub := re.Sub[i]
+			if sub.Op != syntax.OpLiteral {
+				result = append(result, requiredLiterals(sub)...)
+				continue
+			}
+			joined := &syntax.Regexp{Op: syntax.OpLiteral, Flags: sub.Flags, Rune: slices.Clone(sub.Rune)}
+			for i+1 < len(re.Sub) && re.Sub[i+1].Op == syntax.OpLiteral && re.Sub[i+1].Flags&syntax.FoldCase == sub.Flags&syntax.FoldCase {
+				i++
+				joined.Rune = append(joined.Rune, re.Sub[i].Rune...)
+			}
+			result = append(result, literalParts(joined)...)
+		}
+		return result
+	}
+	return nil
+}
+
+// literalParts returns the lowercased text of a literal. The index stores lowercased content, so case-sensitive
+// literals are found by their lowercase form too. Case-insensitive literals are split around characters
+// folding to more than one other character (such as k and the Kelvin sign), whose lowercase forms differ.
+func literalParts(re *syntax.Regexp) []string {
+	if re.Flags&syntax.FoldCase == 0 {
+		return []string{strings.ToLower(string(re.Rune))}
+	}
+
+	var (
+		parts   []string
+		current []rune
+	)
+	for _, r := range re.Rune {
+		if unicode.SimpleFold(unicode.SimpleFold(r)) != r {
+			parts = append(parts, strings.ToLower(string(current)))
+			current = current[:0]
+			continue
+		}
+		current = append(current, r)
+	}
+	return append(parts, strings.ToLower(string(current)))
+}
@@ -0,0 +1,210 @@
+// Package jsonrpc implements the server side of JSON-RPC 2.0 over a byte stream, as spoken by
+// the Model Context Protocol (one message per line) and the Language Server Protocol
+// (messages preceded by a Content-Length header).
+package jsonrpc
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// Framing tells how messages are delimited on the stream.
+type Framing int
+
+const (
+	// Lines delimits messages with newlines, as MCP over stdio does.
+	Lines Framing = iota
+	// Headers precedes each message with a "Content-Length" header block, as LSP does.
+	Headers
+)
+
+// Error codes defined by JSON-RPC 2.0.
+const (
+	CodeParseError     = -32700
+	CodeInvalidRequest = -32600
+	CodeMethodNotFound = -32601
+	CodeInvalidParams  = -32602
+	CodeInternalError  = -32603
+)
+
+const version = "2.0"
+
+// Error is a JSON-RPC error object. Handlers return it to choose the error code.
+type Error struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
+
+func (e *Error) Error() string {
+	return fmt.Sprintf("jsonrpc error %d: %s", e.Code, e.Message)
+}
+
+// ErrMethodNotFound is returned by handlers for methods they do not implement.
+var ErrMethodNotFound = &Error{Code: CodeMethodNotFound, Message: "method not found"}
+
+// InvalidParams returns the error reporting malformed parameters.
+func InvalidParams(err error) *Error {
+	return &Error{Code: CodeInvalidParams, Message: err.Error()}
+}
+
+// Message is a request, notification or response.
+type Message struct {
+	JSONRPC string          `json:"jsonrpc"`
+	ID      json.RawMessage `json:"id,omitempty"`
+	Method  string          `json:"method,omitempty"`
+	Params  json.RawMessage `json:"params,omitempty"`
+	Result  json.RawMessage `json:"result,omitempty"`
+	Error   *Error          `json:"error,omitempty"`
+}
+
+// Handler handles a request or notification. Its result is the response to a request and is discarded
+// for notifications. A returned *Error is sent as is; other errors are sent as internal errors.
+type Handler func(ctx context.Context, method string, params json.RawMessage) (any, error)
+
+// Conn is a JSON-RPC connection over a reader and a writer, such as stdin and stdout.
+type Conn struct {
+	r       *bufio.Reader
+	w       io.Writer
+	framing Framing
+	// mu serializes writes, so that notifications sent by handlers do not interleave with responses.
+	mu sync.Mutex
+}
+
+// NewConn returns a connection reading messages from r and writing them to w.
+func NewConn(r io.Reader, w io.Writer, framing Framing) *Conn {
+	return &Conn{r: bufio.NewReader(r), w: w, framing: framing}
+}
+
+// Serve reads messages and answers requests with handler, one at a time, until the reader is exhausted
+// or ctx is done. Responses from the client are ignored, since this side never sends requests.
+func (c *Conn) Serve(ctx context.Context, handler Handler) error {
+	for ctx.Err() == nil {
+		data, err := c.read()
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if len(bytes.TrimSpace(data)) == 0 {
+			continue
+		}
+
+		var msg Message
+		if unmarshalErr := json.Unmarshal(data, &msg); unmarshalErr != nil {
+			if writeErr := c.respond(json.RawMessage("null"), nil, &Error{Code: CodeParseError, Message: unmarshalErr.Error()}); writeErr != nil {
+				return writeErr
+			}
+			continue
+		}
+		if msg.Method == "" {
+			continue
+		}
+
+		result, handleErr := handler(ctx, msg.Method, msg.Params)
+		if msg.ID == nil {
+			continue
+		}
+		if writeErr := c.respond(msg.ID, result, handleErr); writeErr != nil {
+			return writeErr
+		}
+	}
+
+	return ctx.Err()
+}
+
+// Notify sends a notification.
+func (c *Conn) Notify(method string, params any) error {
+	data, err := json.Marshal(params)
+	if err != nil {
+		return err
+	}
+	return c.write(Message{JSONRPC: version, Method: method, Params: data})
+}
+
+func (c *Conn) respond(id json.RawMessage, result any, err error) error {
+	msg := Message{JSONRPC: version, ID: id}
+	if err != nil {
+		var rpcErr *Error
+		if !errors.As(err, &rpcErr) {
+			rpcErr = &Error{Code: CodeInternalError, Message: err.Error()}
+		}
+		msg.Error = rpcErr
+		return c.write(msg)
+	}
+
+	data, marshalErr := json.Marshal(result)
+	if marshalErr != nil {
+		msg.Error = &Error{Code: CodeInternalError, Message: marshalErr.Error()}
+		return c.write(msg)
+	}
+	msg.Result = data
+	return c.write(msg)
+}
+
+func (c *Conn) write(msg Message) error {
+	data, err := json.Marshal(msg)
+	if err != nil {
+		return err
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.framing == Headers {
+		if _, err = fmt.Fprintf(c.w, "Content-Length: %d\r\n\r\n", len(data)); err != nil {
+			return err
+		}
+		_, err = c.w.Write(data)
+		return err
+	}
+	_, err = c.w.Write(append(data, '\n'))
+	return err
+}
+
+// read reads the next message.
+func (c *Conn) read() ([]byte, error) {
+	if c.framing == Lines {
+		line, err := c.r.ReadBytes('\n')
+		if errors.Is(err, io.EOF) && len(bytes.TrimSpace(line)) > 0 {
+			return line, nil
+		}
+		return line, err
+	}
+
+	length := -1
+	for {
+		line, err := c.r.ReadString('\n')
+		if err != nil {
+			return nil, err
+		}
+		line = strings.TrimRight(line, "\r\n")
+		if line == "" {
+			break
+		}
+		name, value, ok := strings.Cut(line, ":")
+		if ok && strings.EqualFold(strings.TrimSpace(name), "Content-Length") {
+			length, err = strconv.Atoi(strings.TrimSpace(value))
+			if err != nil {
+				return nil, fmt.Errorf("invalid Content-Length %q", value)
+			}
+		}
+	}
+	if length < 0 {
+		return nil, errors.New("missing Content-Length header")
+	}
+
+	data := make([]byte, length)
+	if _, err := io.ReadFull(c.r, data); err != nil {
+		return nil, err
+	}
+	return data, nil
+}
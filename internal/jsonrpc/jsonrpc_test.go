@@ -0,0 +1,76 @@
+package jsonrpc_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/sushichan044/memo-cli/internal/jsonrpc"
+)
+
+func echo(_ context.Context, method string, params json.RawMessage) (any, error) {
+	switch method {
+	case "echo":
+		return params, nil
+	case "fail":
+		return nil, errors.New("boom")
+	case "bad":
+		return nil, jsonrpc.InvalidParams(errors.New("missing name"))
+	default:
+		return nil, jsonrpc.ErrMethodNotFound
+	}
+}
+
+func TestConn_Lines(t *testing.T) {
+	in := strings.Join([]string{
+		`{"jsonrpc":"2.0","id":1,"method":"echo","params":{"a":1}}`,
+		`{"jsonrpc":"2.0","method":"echo","params":{}}`,
+		``,
+		`{"jsonrpc":"2.0","id":"x","method":"fail"}`,
+		`{"jsonrpc":"2.0","id":3,"method":"bad"}`,
+		`{"jsonrpc":"2.0","id":4,"method":"nope"}`,
+		`not json`,
+		`{"jsonrpc":"2.0","id":5,"method":"echo","params":[]}`,
+	}, "\n")
+
+	var out bytes.Buffer
+	require.NoError(t, jsonrpc.NewConn(strings.NewReader(in), &out, jsonrpc.Lines).Serve(context.Background(), echo))
+
+	assert.Equal(t, strings.Join([]string{
+		`{"jsonrpc":"2.0","id":1,"result":{"a":1}}`,
+		`{"jsonrpc":"2.0","id":"x","error":{"code":-32603,"message":"boom"}}`,
+		`{"jsonrpc":"2.0","id":3,"error":{"code":-32602,"message":"missing name"}}`,
+		`{"jsonrpc":"2.0","id":4,"error":{"code":-32601,"message":"method not found"}}`,
+		`{"jsonrpc":"2.0","id":null,"error":{"code":-32700,"message":"invalid character 'o' in literal null (expecting 'u')"}}`,
+		`{"jsonrpc":"2.0","id":5,"result":[]}`,
+	}, "\n")+"\n", out.String())
+}
+
+func TestConn_Headers(t *testing.T) {
+	body := `{"jsonrpc":"2.0","id":1,"method":"echo","params":"héllo"}`
+	in := "Content-Length: " + strconv.Itoa(len(body)) + "\r\nContent-Type: application/vscode-jsonrpc; charset=utf-8\r\n\r\n" + body
+
+	var out bytes.Buffer
+	conn := jsonrpc.NewConn(strings.NewReader(in), &out, jsonrpc.Headers)
+	require.NoError(t, conn.Notify("window/logMessage", map[string]string{"message": "hi"}))
+	require.NoError(t, conn.Serve(context.Background(), echo))
+
+	note := `{"jsonrpc":"2.0","method":"window/logMessage","params":{"message":"hi"}}`
+	resp := `{"jsonrpc":"2.0","id":1,"result":"héllo"}`
+	assert.Equal(t,
+		"Content-Length: "+strconv.Itoa(len(note))+"\r\n\r\n"+note+"Content-Length: "+strconv.Itoa(len(resp))+"\r\n\r\n"+resp,
+		out.String())
+}
+
+func TestConn_HeadersMissingLength(t *testing.T) {
+	err := jsonrpc.NewConn(strings.NewReader("X: 1\r\n\r\n{}"), &bytes.Buffer{}, jsonrpc.Headers).
+		Serve(context.Background(), echo)
+	require.Error(t, err)
+}
@@ -0,0 +1,84 @@
+// Package links indexes the [[wiki-links]] between memos.
+package links
+
+import (
+	"github.com/sushichan044/memo-cli/internal/markdown"
+	"github.com/sushichan044/memo-cli/internal/memo"
+)
+
+// Link is a wiki-link from one memo to another.
+type Link struct {
+	markdown.Link
+
+	// From is the memo containing the link.
+	From memo.Entry
+	// To is the memo the link resolves to. It is the zero Entry when the link is broken.
+	To memo.Entry
+	// Broken reports whether the target does not resolve to any memo.
+	Broken bool
+}
+
+// Index holds the links of a set of memos.
+type Index struct {
+	resolver *memo.LinkResolver
+	links    []Link
+}
+
+// Build scans entries for links and resolves them against entries.
+// read returns the plaintext content of a memo; returning nil content with a nil error skips the memo,
+// which then has no outgoing links but can still be linked to.
+func Build(entries []memo.Entry, read func(memo.Entry) ([]byte, error)) (*Index, error) {
+	index := &Index{resolver: memo.NewLinkResolver(entries)}
+
+	for _, entry := range entries {
+		content, err := read(entry)
+		if err != nil {
+			return nil, err
+		}
+		if content == nil {
+			continue
+		}
+
+		for _, item := range markdown.Links(content) {
+			to, ok := index.resolver.Resolve(item.Target)
+			index.links = append(index.links, Link{Link: item, From: entry, To: to, Broken: !ok})
+		}
+	}
+
+	return index, nil
+}
+
+// Resolve returns the memo a link target refers to, as described by memo.LinkResolver.
+func (i *Index) Resolve(target string) (memo.Entry, bool) {
+	return i.resolver.Resolve(target)
+}
+
+// All returns every link, in the order of the entries given to Build and then by position.
+func (i *Index) All() []Link {
+	return i.links
+}
+
+// Outgoing returns the links found in entry.
+func (i *Index) Outgoing(entry memo.Entry) []Link {
+	return i.filter(func(l Link) bool { return l.From.RelPath == entry.RelPath })
+}
+
+// Backlinks returns the links that resolve to entry.
+func (i *Index) Backlinks(entry memo.Entry) []Link {
+	return i.filter(func(l Link) bool { return !l.Broken && l.To.RelPath == entry.RelPath })
+}
+
+// Broken returns the links whose target does not resolve to any memo.
+func (i *Index) Broken() []Link {
+	return i.filter(func(l Link) bool { return l.Broken })
+}
+
+func (i *Index) filter(keep func(Link) bool) []Link {
+	var links []Link
+	for _, l := range i.links {
+		if keep(l) {
+			links = append(links, l)
+		}
+	}
+	return links
+}
@@ -0,0 +1,59 @@
+package links_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/sushichan044/memo-cli/internal/links"
+	"github.com/sushichan044/memo-cli/internal/memo"
+	"github.com/sushichan044/memo-cli/internal/memofs"
+)
+
+func TestIndex(t *testing.T) {
+	fsys := memofs.NewMem("/memo")
+	files := map[string]string{
+		"20251030/09-00-00-design.md":  "# Design\n\nSee [[plan]] and [[nowhere]].\n",
+		"20251031/10-00-00-plan.md":    "Based on [[design|the design]].\n`[[not a link]]`\n",
+		"20251031/11-00-00-journal.md": "Reviewed [[plan#Risks]] with [[Design]].\n",
+	}
+	for name, content := range files {
+		require.NoError(t, fsys.MkdirAll(name[:8], 0o700))
+		require.NoError(t, fsys.WriteFile(name, []byte(content), 0o600))
+	}
+
+	entries, err := memo.List(fsys)
+	require.NoError(t, err)
+
+	index, err := links.Build(entries, func(e memo.Entry) ([]byte, error) {
+		return fsys.ReadFile(e.RelPath)
+	})
+	require.NoError(t, err)
+	assert.Len(t, index.All(), 5)
+
+	design, err := memo.Resolve(fsys, "design")
+	require.NoError(t, err)
+	plan, err := memo.Resolve(fsys, "plan")
+	require.NoError(t, err)
+
+	outgoing := index.Outgoing(design)
+	require.Len(t, outgoing, 2)
+	assert.Equal(t, plan.RelPath, outgoing[0].To.RelPath)
+	assert.Equal(t, 3, outgoing[0].Line)
+	assert.True(t, outgoing[1].Broken)
+
+	backlinks := index.Backlinks(design)
+	require.Len(t, backlinks, 2)
+	assert.Equal(t, "20251031/11-00-00-journal.md", backlinks[0].From.RelPath)
+	assert.Equal(t, plan.RelPath, backlinks[1].From.RelPath)
+	assert.Equal(t, "the design", backlinks[1].Alias)
+
+	broken := index.Broken()
+	require.Len(t, broken, 1)
+	assert.Equal(t, "nowhere", broken[0].Target)
+
+	resolved, ok := index.Resolve("journal")
+	assert.True(t, ok)
+	assert.Equal(t, "journal", resolved.Name)
+}
@@ -0,0 +1,258 @@
+package lsp
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/sushichan044/memo-cli/internal/markdown"
+	"github.com/sushichan044/memo-cli/internal/memo"
+)
+
+// previewLines is the number of lines of a linked memo shown on hover.
+const previewLines = 20
+
+var (
+	// openLinkPattern matches an unterminated wiki-link before the cursor, capturing the target typed so far.
+	openLinkPattern = regexp.MustCompile(`\[\[([^\[\]|#]*)$`)
+	// openTagPattern matches a #tag before the cursor, capturing the tag typed so far.
+	openTagPattern = regexp.MustCompile(`(?:^|[\s(])#(\pL[\pL\pN_/-]*)?$`)
+)
+
+func (s *Server) completion(path string, pos Position) ([]CompletionItem, error) {
+	text, err := s.text(path)
+	if err != nil {
+		return nil, err
+	}
+	line := lineAt(text, pos.Line)
+	offset := byteOffset(line, pos.Character)
+	before := line[:offset]
+
+	if m := openLinkPattern.FindStringSubmatchIndex(before); m != nil {
+		edit := Range{Start: Position{Line: pos.Line, Character: utf16Column(line, m[2])}, End: pos}
+		closing := "]]"
+		if strings.HasPrefix(line[offset:], "]]") {
+			closing = ""
+		}
+		return s.linkCompletion(edit, closing)
+	}
+
+	if m := openTagPattern.FindStringSubmatchIndex(before); m != nil {
+		start := len(before)
+		if m[2] >= 0 {
+			start = m[2]
+		}
+		edit := Range{Start: Position{Line: pos.Line, Character: utf16Column(line, start)}, End: pos}
+		return s.tagCompletion(edit, text, strings.ToLower(before[start:]))
+	}
+
+	return []CompletionItem{}, nil
+}
+
+// linkCompletion proposes every memo, newest first, by the shortest target resolving to it:
+// the date of daily notes and the name of other memos. A name shared by several memos resolves
+// to the newest, so older memos sharing it are proposed by their file name.
+func (s *Server) linkCompletion(edit Range, closing string) ([]CompletionItem, error) {
+	entries, err := memo.List(s.fsys)
+	if err != nil {
+		return nil, err
+	}
+
+	items := []CompletionItem{}
+	seen := map[string]bool{}
+	for i, entry := range entries {
+		label := entry.Title()
+		if entry.Daily {
+			label = entry.CreatedAt.Format(time.DateOnly)
+		} else if seen[label] {
+			label = entry.Stem()
+		}
+		seen[label] = true
+
+		items = append(items, CompletionItem{
+			Label:    label,
+			Kind:     completionKindFile,
+			Detail:   entry.RelPath,
+			SortText: fmt.Sprintf("%06d", i),
+			TextEdit: &TextEdit{Range: edit, NewText: label + closing},
+		})
+	}
+
+	return items, nil
+}
+
+// tagCompletion proposes the tags of every memo and of the document, most used first.
+// The partial tag being typed does not count as a use.
+func (s *Server) tagCompletion(edit Range, text, partial string) ([]CompletionItem, error) {
+	entries, err := memo.List(s.fsys)
+	if err != nil {
+		return nil, err
+	}
+
+	counts := map[string]int{}
+	for _, entry := range entries {
+		if _, open := s.docs[entry.Path]; open {
+			continue
+		}
+		content, readErr := s.read(entry)
+		if readErr != nil {
+			return nil, readErr
+		}
+		for _, tag := range markdown.Tags(content) {
+			counts[tag]++
+		}
+	}
+	for _, tag := range markdown.Tags([]byte(text)) {
+		counts[tag]++
+	}
+	if counts[partial]--; counts[partial] <= 0 {
+		delete(counts, partial)
+	}
+
+	tags := make([]string, 0, len(counts))
+	for tag := range counts {
+		tags = append(tags, tag)
+	}
+	slices.SortFunc(tags, func(a, b string) int {
+		if counts[a] != counts[b] {
+			return counts[b] - counts[a]
+		}
+		return strings.Compare(a, b)
+	})
+
+	items := make([]CompletionItem, 0, len(tags))
+	for i, tag := range tags {
+		items = append(items, CompletionItem{
+			Label:    tag,
+			Kind:     completionKindKeyword,
+			Detail:   fmt.Sprintf("%d memo(s)", counts[tag]),
+			SortText: fmt.Sprintf("%06d", i),
+			TextEdit: &TextEdit{Range: edit, NewText: tag},
+		})
+	}
+
+	return items, nil
+}
+
+// definition goes from a link to the memo it resolves to, at the linked heading if any.
+func (s *Server) definition(path string, pos Position) ([]Location, error) {
+	target, link, ok, err := s.linkTarget(path, pos)
+	if err != nil || !ok {
+		return []Location{}, err
+	}
+
+	line := 0
+	if link.Heading != "" {
+		content, readErr := s.read(target)
+		if readErr != nil {
+			return nil, readErr
+		}
+		line = headingLine(content, link.Heading)
+	}
+
+	return []Location{{URI: pathURI(target.Path), Range: Range{Start: Position{Line: line}, End: Position{Line: line}}}}, nil
+}
+
+// references lists the links to the memo of the link under the cursor or, elsewhere, to the document itself.
+func (s *Server) references(path string, pos Position) ([]Location, error) {
+	entries, index, err := s.index()
+	if err != nil {
+		return nil, err
+	}
+
+	target, _, ok, err := s.linkTarget(path, pos)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		i := slices.IndexFunc(entries, func(e memo.Entry) bool { return e.Path == path })
+		if i < 0 {
+			return []Location{}, nil
+		}
+		target = entries[i]
+	}
+
+	locations := []Location{}
+	for _, link := range index.Backlinks(target) {
+		locations = append(locations, Location{URI: pathURI(link.From.Path), Range: linkRange(link.Link)})
+	}
+	return locations, nil
+}
+
+// hover previews the memo a link resolves to.
+func (s *Server) hover(path string, pos Position) (*Hover, error) {
+	text, err := s.text(path)
+	if err != nil {
+		return nil, err
+	}
+	link, ok := linkAt(text, pos)
+	if !ok {
+		return nil, nil //nolint:nilnil // no hover
+	}
+	linkRange := linkRange(link)
+
+	entries, err := memo.List(s.fsys)
+	if err != nil {
+		return nil, err
+	}
+	target, ok := memo.NewLinkResolver(entries).Resolve(link.Target)
+	if !ok {
+		return &Hover{
+			Contents: MarkupContent{Kind: "markdown", Value: "No memo matches `" + link.Target + "`."},
+			Range:    &linkRange,
+		}, nil
+	}
+
+	content, err := s.read(target)
+	if err != nil {
+		return nil, err
+	}
+	preview := "_encrypted_"
+	if content != nil {
+		lines := strings.Split(strings.TrimSpace(string(markdown.Body(content))), "\n")
+		if len(lines) > previewLines {
+			lines = append(lines[:previewLines], "…")
+		}
+		preview = strings.Join(lines, "\n")
+	}
+
+	return &Hover{
+		Contents: MarkupContent{
+			Kind:  "markdown",
+			Value: fmt.Sprintf("**%s** · `%s`\n\n---\n\n%s", target.Title(), target.RelPath, preview),
+		},
+		Range: &linkRange,
+	}, nil
+}
+
+// linkTarget resolves the link under the cursor.
+func (s *Server) linkTarget(path string, pos Position) (memo.Entry, markdown.Link, bool, error) {
+	text, err := s.text(path)
+	if err != nil {
+		return memo.Entry{}, markdown.Link{}, false, err
+	}
+	link, ok := linkAt(text, pos)
+	if !ok {
+		return memo.Entry{}, markdown.Link{}, false, nil
+	}
+
+	entries, err := memo.List(s.fsys)
+	if err != nil {
+		return memo.Entry{}, markdown.Link{}, false, err
+	}
+	target, ok := memo.NewLinkResolver(entries).Resolve(link.Target)
+	return target, link, ok, nil
+}
+
+// headingLine returns the zero-based line of the Markdown heading named heading, or 0 if there is none.
+func headingLine(content []byte, heading string) int {
+	for _, line := range markdown.Lines(content) {
+		trimmed := strings.TrimLeft(line.Text, "#")
+		if len(trimmed) < len(line.Text) && strings.EqualFold(strings.TrimSpace(trimmed), heading) {
+			return line.Number - 1
+		}
+	}
+	return 0
+}
@@ -0,0 +1,249 @@
+// Package lsp implements a Language Server Protocol server for memos, so that editors get
+// completion of [[wiki-links]] and #tags, go-to-definition and references for links,
+// diagnostics for broken links and previews of linked memos on hover.
+//
+// Documents are identified by file:// URIs. Open documents are read from the editor,
+// which may hold unsaved changes, and every other memo from the memo root.
+package lsp
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"strings"
+
+	"github.com/sushichan044/memo-cli/internal/crypt"
+	"github.com/sushichan044/memo-cli/internal/jsonrpc"
+	"github.com/sushichan044/memo-cli/internal/links"
+	"github.com/sushichan044/memo-cli/internal/markdown"
+	"github.com/sushichan044/memo-cli/internal/memo"
+	"github.com/sushichan044/memo-cli/internal/memofs"
+)
+
+// Options configures a Server.
+type Options struct {
+	// Keyring reads encrypted memos linked from documents. Without an identity, they are linked to but not read.
+	Keyring *crypt.Keyring
+	// Version is reported as the server version.
+	Version string
+}
+
+// Server is a language server of the memos in a memo root.
+type Server struct {
+	fsys memofs.FS
+	opts Options
+	conn *jsonrpc.Conn
+	// docs holds the text of open documents by path.
+	docs map[string]string
+	// exit stops Serve once the client sends "exit".
+	exit context.CancelFunc
+}
+
+// New returns a Server of the memos in fsys.
+func New(fsys memofs.FS, opts Options) *Server {
+	return &Server{fsys: fsys, opts: opts, docs: map[string]string{}}
+}
+
+// Serve speaks LSP on r and w, usually stdin and stdout, until the client sends "exit" or closes r.
+func (s *Server) Serve(ctx context.Context, r io.Reader, w io.Writer) error {
+	ctx, s.exit = context.WithCancel(ctx)
+	defer s.exit()
+
+	s.conn = jsonrpc.NewConn(r, w, jsonrpc.Headers)
+	err := s.conn.Serve(ctx, s.handle)
+	if errors.Is(err, context.Canceled) {
+		return nil
+	}
+	return err
+}
+
+func (s *Server) handle(_ context.Context, method string, params json.RawMessage) (any, error) {
+	switch method {
+	case "initialize":
+		return s.initialize(), nil
+	case "shutdown":
+		return nil, nil
+	case "exit":
+		s.exit()
+		return nil, nil
+	case "textDocument/didOpen":
+		var p didOpenParams
+		if err := unmarshal(params, &p); err != nil {
+			return nil, err
+		}
+		if path, ok := uriPath(p.TextDocument.URI); ok {
+			s.docs[path] = p.TextDocument.Text
+		}
+		return nil, s.publishDiagnostics()
+	case "textDocument/didChange":
+		var p didChangeParams
+		if err := unmarshal(params, &p); err != nil {
+			return nil, err
+		}
+		if path, ok := uriPath(p.TextDocument.URI); ok && len(p.ContentChanges) > 0 {
+			s.docs[path] = p.ContentChanges[len(p.ContentChanges)-1].Text
+		}
+		return nil, s.publishDiagnostics()
+	case "textDocument/didSave":
+		// Saving may create the memo a link of another document is waiting for.
+		return nil, s.publishDiagnostics()
+	case "textDocument/didClose":
+		var p didCloseParams
+		if err := unmarshal(params, &p); err != nil {
+			return nil, err
+		}
+		if path, ok := uriPath(p.TextDocument.URI); ok {
+			delete(s.docs, path)
+		}
+		return nil, s.conn.Notify("textDocument/publishDiagnostics",
+			publishDiagnosticsParams{URI: p.TextDocument.URI, Diagnostics: []Diagnostic{}})
+	case "textDocument/completion":
+		return withPosition(params, s.completion)
+	case "textDocument/definition":
+		return withPosition(params, s.definition)
+	case "textDocument/references":
+		return withPosition(params, s.references)
+	case "textDocument/hover":
+		return withPosition(params, s.hover)
+	default:
+		// Notifications the server does not need, such as "initialized" and "$/cancelRequest", are ignored.
+		return nil, jsonrpc.ErrMethodNotFound
+	}
+}
+
+func (s *Server) initialize() any {
+	return map[string]any{
+		"capabilities": map[string]any{
+			// Full document sync: documents are small, and links are re-scanned on every change anyway.
+			"textDocumentSync": map[string]any{"openClose": true, "change": 1, "save": true},
+			"completionProvider": map[string]any{
+				"triggerCharacters": []string{"[", "#"},
+			},
+			"definitionProvider": true,
+			"referencesProvider": true,
+			"hoverProvider":      true,
+		},
+		"serverInfo": map[string]any{"name": "memo", "version": s.opts.Version},
+	}
+}
+
+// withPosition decodes the document and position of a request and runs fn on them.
+func withPosition[T any](params json.RawMessage, fn func(path string, pos Position) (T, error)) (any, error) {
+	var p positionParams
+	if err := unmarshal(params, &p); err != nil {
+		return nil, err
+	}
+	path, ok := uriPath(p.TextDocument.URI)
+	if !ok {
+		return nil, nil
+	}
+	return fn(path, p.Position)
+}
+
+func unmarshal(params json.RawMessage, v any) error {
+	if err := json.Unmarshal(params, v); err != nil {
+		return jsonrpc.InvalidParams(err)
+	}
+	return nil
+}
+
+// text returns the content of the document at path: the editor's if it is open, otherwise the file's.
+func (s *Server) text(path string) (string, error) {
+	if text, ok := s.docs[path]; ok {
+		return text, nil
+	}
+	entry, err := memo.NewEntry(s.fsys, path)
+	if err != nil {
+		return "", err
+	}
+	content, err := s.read(entry)
+	return string(content), err
+}
+
+// read returns the content of a memo, preferring unsaved changes of open documents.
+// Encrypted memos that cannot be decrypted read as nil, like missing content.
+func (s *Server) read(entry memo.Entry) ([]byte, error) {
+	if text, ok := s.docs[entry.Path]; ok {
+		return []byte(text), nil
+	}
+	content, err := s.opts.Keyring.ReadFile(s.fsys, entry.RelPath)
+	if errors.Is(err, crypt.ErrNoIdentity) {
+		return nil, nil
+	}
+	return content, err
+}
+
+// index builds the link index of every memo.
+func (s *Server) index() ([]memo.Entry, *links.Index, error) {
+	entries, err := memo.List(s.fsys)
+	if err != nil {
+		return nil, nil, err
+	}
+	index, err := links.Build(entries, s.read)
+	if err != nil {
+		return nil, nil, err
+	}
+	return entries, index, nil
+}
+
+// publishDiagnostics reports the broken links of every open document.
+func (s *Server) publishDiagnostics() error {
+	entries, err := memo.List(s.fsys)
+	if err != nil {
+		return err
+	}
+	resolver := memo.NewLinkResolver(entries)
+
+	for path, text := range s.docs {
+		diagnostics := []Diagnostic{}
+		for _, link := range markdown.Links([]byte(text)) {
+			if _, ok := resolver.Resolve(link.Target); ok {
+				continue
+			}
+			diagnostics = append(diagnostics, Diagnostic{
+				Range:    linkRange(link),
+				Severity: SeverityWarning,
+				Source:   "memo",
+				Message:  "no memo matches [[" + link.Target + "]]",
+			})
+		}
+		if notifyErr := s.conn.Notify("textDocument/publishDiagnostics",
+			publishDiagnosticsParams{URI: pathURI(path), Diagnostics: diagnostics}); notifyErr != nil {
+			return notifyErr
+		}
+	}
+
+	return nil
+}
+
+// linkAt returns the link of text under pos.
+func linkAt(text string, pos Position) (markdown.Link, bool) {
+	for _, link := range markdown.Links([]byte(text)) {
+		if link.Line-1 != pos.Line {
+			continue
+		}
+		offset := byteOffset(link.Text, pos.Character)
+		if start := link.Column - 1; offset >= start && offset <= start+len(link.Raw) {
+			return link, true
+		}
+	}
+	return markdown.Link{}, false
+}
+
+func linkRange(link markdown.Link) Range {
+	start := link.Column - 1
+	return Range{
+		Start: Position{Line: link.Line - 1, Character: utf16Column(link.Text, start)},
+		End:   Position{Line: link.Line - 1, Character: utf16Column(link.Text, start+len(link.Raw))},
+	}
+}
+
+// lineAt returns the line of text at a zero-based index.
+func lineAt(text string, line int) string {
+	lines := strings.Split(text, "\n")
+	if line < 0 || line >= len(lines) {
+		return ""
+	}
+	return strings.TrimSuffix(lines[line], "\r")
+}
@@ -0,0 +1,351 @@
+package lsp_test
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/textproto"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/sushichan044/memo-cli/internal/config"
+	"github.com/sushichan044/memo-cli/internal/crypt"
+	"github.com/sushichan044/memo-cli/internal/lsp"
+	"github.com/sushichan044/memo-cli/internal/memofs"
+)
+
+// client talks to a server running on the other end of in-process pipes, as an editor does over stdio.
+type client struct {
+	t  *testing.T
+	in *io.PipeWriter
+	// out receives the messages of the server, read as soon as written so that the server never blocks.
+	out    chan message
+	nextID int
+	done   chan error
+	// diagnostics holds the latest diagnostics published for each document URI.
+	diagnostics map[string][]lsp.Diagnostic
+}
+
+type message struct {
+	ID     int             `json:"id"`
+	Method string          `json:"method"`
+	Params json.RawMessage `json:"params"`
+	Result json.RawMessage `json:"result"`
+	Error  *struct {
+		Code    int    `json:"code"`
+		Message string `json:"message"`
+	} `json:"error"`
+}
+
+func start(t *testing.T, files map[string]string) *client {
+	t.Helper()
+
+	fsys := memofs.NewMem("/memo")
+	for name, content := range files {
+		require.NoError(t, fsys.MkdirAll(name[:8], 0o700))
+		require.NoError(t, fsys.WriteFile(name, []byte(content), 0o600))
+	}
+	srv := lsp.New(fsys, lsp.Options{Keyring: crypt.NewKeyring(config.Encryption{}), Version: "test"})
+
+	stdinR, stdinW := io.Pipe()
+	stdoutR, stdoutW := io.Pipe()
+	c := &client{
+		t:           t,
+		in:          stdinW,
+		out:         make(chan message, 64),
+		done:        make(chan error, 1),
+		diagnostics: map[string][]lsp.Diagnostic{},
+	}
+	go func() {
+		c.done <- srv.Serve(context.Background(), stdinR, stdoutW)
+		stdoutW.Close()
+	}()
+	go readMessages(textproto.NewReader(bufio.NewReader(stdoutR)), c.out)
+	t.Cleanup(func() {
+		stdinW.Close()
+		<-c.done
+	})
+
+	c.call("initialize", map[string]any{"capabilities": map[string]any{}})
+	c.notify("initialized", map[string]any{})
+	return c
+}
+
+func (c *client) send(msg map[string]any) {
+	c.t.Helper()
+	msg["jsonrpc"] = "2.0"
+	data, err := json.Marshal(msg)
+	require.NoError(c.t, err)
+	_, err = fmt.Fprintf(c.in, "Content-Length: %d\r\n\r\n%s", len(data), data)
+	require.NoError(c.t, err)
+}
+
+func (c *client) notify(method string, params any) {
+	c.t.Helper()
+	c.send(map[string]any{"method": method, "params": params})
+}
+
+// call sends a request and reads messages up to its response, recording the diagnostics published meanwhile.
+func (c *client) call(method string, params any) message {
+	c.t.Helper()
+	c.nextID++
+	c.send(map[string]any{"id": c.nextID, "method": method, "params": params})
+
+	for {
+		msg := c.read()
+		if msg.Method == "textDocument/publishDiagnostics" {
+			var p struct {
+				URI         string           `json:"uri"`
+				Diagnostics []lsp.Diagnostic `json:"diagnostics"`
+			}
+			require.NoError(c.t, json.Unmarshal(msg.Params, &p))
+			c.diagnostics[p.URI] = p.Diagnostics
+			continue
+		}
+		require.Equal(c.t, c.nextID, msg.ID)
+		return msg
+	}
+}
+
+func (c *client) read() message {
+	c.t.Helper()
+	msg, ok := <-c.out
+	require.True(c.t, ok, "server closed its output")
+	return msg
+}
+
+// readMessages decodes Content-Length framed messages until r is closed.
+func readMessages(r *textproto.Reader, out chan<- message) {
+	defer close(out)
+	for {
+		header, err := r.ReadMIMEHeader()
+		if err != nil {
+			return
+		}
+		length, err := strconv.Atoi(header.Get("Content-Length"))
+		if err != nil {
+			return
+		}
+		data := make([]byte, length)
+		if _, err = io.ReadFull(r.R, data); err != nil {
+			return
+		}
+		var msg message
+		if err = json.Unmarshal(data, &msg); err != nil {
+			return
+		}
+		out <- msg
+	}
+}
+
+// result calls a position request and decodes its result into v.
+func (c *client) result(method, uri string, line, character int, v any) {
+	c.t.Helper()
+	resp := c.call(method, map[string]any{
+		"textDocument": map[string]any{"uri": uri},
+		"position":     map[string]any{"line": line, "character": character},
+		"context":      map[string]any{"includeDeclaration": false},
+	})
+	require.Nil(c.t, resp.Error)
+	require.NoError(c.t, json.Unmarshal(resp.Result, v))
+}
+
+func (c *client) open(uri, text string) {
+	c.t.Helper()
+	c.notify("textDocument/didOpen", map[string]any{
+		"textDocument": map[string]any{"uri": uri, "languageId": "markdown", "version": 1, "text": text},
+	})
+}
+
+const (
+	planURI  = "file:///memo/20251030/09-00-00-plan.md"
+	notesURI = "file:///memo/20251031/14-30-45-notes.md"
+)
+
+var memos = map[string]string{
+	"20251030/09-00-00-plan.md":  "# Plan\n\n## Goals\n\nShip it. #work\n",
+	"20251030/daily.md":          "See [[plan]]. #journal\n",
+	"20251031/14-30-45-notes.md": "Notes on [[plan#Goals]] and [[missing]].\n",
+}
+
+func TestServer_Initialize(t *testing.T) {
+	c := start(t, nil)
+
+	resp := c.call("initialize", map[string]any{"capabilities": map[string]any{}})
+	require.Nil(t, resp.Error)
+	var result struct {
+		Capabilities map[string]json.RawMessage `json:"capabilities"`
+		ServerInfo   struct{ Name string }      `json:"serverInfo"`
+	}
+	require.NoError(t, json.Unmarshal(resp.Result, &result))
+	for _, capability := range []string{"completionProvider", "definitionProvider", "referencesProvider", "hoverProvider"} {
+		assert.Contains(t, result.Capabilities, capability)
+	}
+	assert.Equal(t, "memo", result.ServerInfo.Name)
+
+	assert.Equal(t, -32601, c.call("workspace/symbol", map[string]any{}).Error.Code)
+}
+
+func TestServer_Exit(t *testing.T) {
+	c := start(t, nil)
+
+	require.Nil(t, c.call("shutdown", nil).Error)
+	c.notify("exit", nil)
+	require.NoError(t, <-c.done)
+	c.done <- nil
+}
+
+func TestServer_Diagnostics(t *testing.T) {
+	c := start(t, memos)
+
+	c.open(notesURI, memos["20251031/14-30-45-notes.md"])
+	c.call("shutdown", nil)
+	require.Len(t, c.diagnostics[notesURI], 1)
+	diagnostic := c.diagnostics[notesURI][0]
+	assert.Equal(t, lsp.Range{
+		Start: lsp.Position{Line: 0, Character: 28},
+		End:   lsp.Position{Line: 0, Character: 39},
+	}, diagnostic.Range)
+	assert.Equal(t, lsp.SeverityWarning, diagnostic.Severity)
+	assert.Contains(t, diagnostic.Message, "[[missing]]")
+
+	c.notify("textDocument/didChange", map[string]any{
+		"textDocument":   map[string]any{"uri": notesURI, "version": 2},
+		"contentChanges": []map[string]any{{"text": "Fixed: [[plan]]\n"}},
+	})
+	c.call("shutdown", nil)
+	assert.Empty(t, c.diagnostics[notesURI])
+}
+
+func TestServer_Completion(t *testing.T) {
+	c := start(t, memos)
+
+	tests := []struct {
+		name   string
+		text   string
+		line   int
+		column int
+		want   []string
+		edit   lsp.TextEdit
+	}{
+		{
+			name:   "link",
+			text:   "See [[pl",
+			column: 8,
+			want:   []string{"notes", "plan", "2025-10-30"},
+			edit: lsp.TextEdit{
+				Range:   lsp.Range{Start: lsp.Position{Character: 6}, End: lsp.Position{Character: 8}},
+				NewText: "notes]]",
+			},
+		},
+		{
+			name:   "link before closing brackets",
+			text:   "x\n[[]] ✓",
+			line:   1,
+			column: 2,
+			want:   []string{"notes", "plan", "2025-10-30"},
+			edit: lsp.TextEdit{
+				Range:   lsp.Range{Start: lsp.Position{Line: 1, Character: 2}, End: lsp.Position{Line: 1, Character: 2}},
+				NewText: "notes",
+			},
+		},
+		{
+			name:   "tag",
+			text:   "Done #wo",
+			column: 8,
+			want:   []string{"journal", "work"},
+			edit: lsp.TextEdit{
+				Range:   lsp.Range{Start: lsp.Position{Character: 6}, End: lsp.Position{Character: 8}},
+				NewText: "journal",
+			},
+		},
+		{
+			name:   "neither",
+			text:   "plain text",
+			column: 5,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c.open(notesURI, tt.text)
+
+			var items []lsp.CompletionItem
+			c.result("textDocument/completion", notesURI, tt.line, tt.column, &items)
+			labels := make([]string, 0, len(items))
+			for _, item := range items {
+				labels = append(labels, item.Label)
+			}
+			assert.ElementsMatch(t, tt.want, labels)
+			if len(items) > 0 {
+				assert.Equal(t, tt.edit, *items[0].TextEdit)
+			}
+		})
+	}
+}
+
+func TestServer_Definition(t *testing.T) {
+	c := start(t, memos)
+	c.open(notesURI, memos["20251031/14-30-45-notes.md"])
+
+	var locations []lsp.Location
+	c.result("textDocument/definition", notesURI, 0, 12, &locations)
+	require.Len(t, locations, 1)
+	assert.Equal(t, planURI, locations[0].URI)
+	assert.Equal(t, 2, locations[0].Range.Start.Line, "the linked heading")
+
+	c.result("textDocument/definition", notesURI, 0, 30, &locations)
+	assert.Empty(t, locations, "broken link")
+
+	c.result("textDocument/definition", notesURI, 0, 2, &locations)
+	assert.Empty(t, locations, "not on a link")
+}
+
+func TestServer_References(t *testing.T) {
+	c := start(t, memos)
+	c.open(notesURI, memos["20251031/14-30-45-notes.md"])
+
+	var fromLink []lsp.Location
+	c.result("textDocument/references", notesURI, 0, 12, &fromLink)
+
+	var fromTarget []lsp.Location
+	c.result("textDocument/references", planURI, 0, 0, &fromTarget)
+
+	for _, locations := range [][]lsp.Location{fromLink, fromTarget} {
+		uris := make([]string, 0, len(locations))
+		for _, location := range locations {
+			uris = append(uris, location.URI)
+		}
+		assert.ElementsMatch(t, []string{"file:///memo/20251030/daily.md", notesURI}, uris)
+	}
+}
+
+func TestServer_Hover(t *testing.T) {
+	c := start(t, memos)
+	c.open(notesURI, memos["20251031/14-30-45-notes.md"])
+
+	var hover lsp.Hover
+	c.result("textDocument/hover", notesURI, 0, 12, &hover)
+	assert.Equal(t, "markdown", hover.Contents.Kind)
+	assert.True(t, strings.HasPrefix(hover.Contents.Value, "**plan** · `20251030/09-00-00-plan.md`"), hover.Contents.Value)
+	assert.Contains(t, hover.Contents.Value, "Ship it.")
+	assert.Equal(t, &lsp.Range{
+		Start: lsp.Position{Line: 0, Character: 9},
+		End:   lsp.Position{Line: 0, Character: 23},
+	}, hover.Range)
+
+	c.result("textDocument/hover", notesURI, 0, 30, &hover)
+	assert.Contains(t, hover.Contents.Value, "No memo matches")
+
+	resp := c.call("textDocument/hover", map[string]any{
+		"textDocument": map[string]any{"uri": notesURI},
+		"position":     map[string]any{"line": 0, "character": 2},
+	})
+	assert.Equal(t, "null", string(resp.Result))
+}
@@ -0,0 +1,151 @@
+package lsp
+
+import (
+	"net/url"
+	"path/filepath"
+	"unicode/utf8"
+)
+
+// The subset of Language Server Protocol types used by the server.
+
+// Position is a zero-based line and UTF-16 code unit offset in a document.
+type Position struct {
+	Line      int `json:"line"`
+	Character int `json:"character"`
+}
+
+// Range is a span of a document, with an exclusive end.
+type Range struct {
+	Start Position `json:"start"`
+	End   Position `json:"end"`
+}
+
+// Location is a range in a document.
+type Location struct {
+	URI   string `json:"uri"`
+	Range Range  `json:"range"`
+}
+
+// Diagnostic severities.
+const (
+	SeverityError   = 1
+	SeverityWarning = 2
+)
+
+// Diagnostic is a problem reported in a document.
+type Diagnostic struct {
+	Range    Range  `json:"range"`
+	Severity int    `json:"severity"`
+	Source   string `json:"source"`
+	Message  string `json:"message"`
+}
+
+// Completion item kinds.
+const (
+	completionKindFile    = 17
+	completionKindKeyword = 14
+)
+
+// CompletionItem is a completion proposal.
+type CompletionItem struct {
+	Label    string    `json:"label"`
+	Kind     int       `json:"kind"`
+	Detail   string    `json:"detail,omitempty"`
+	SortText string    `json:"sortText,omitempty"`
+	TextEdit *TextEdit `json:"textEdit,omitempty"`
+}
+
+// TextEdit replaces a range of a document.
+type TextEdit struct {
+	Range   Range  `json:"range"`
+	NewText string `json:"newText"`
+}
+
+// Hover is the content shown when hovering a range.
+type Hover struct {
+	Contents MarkupContent `json:"contents"`
+	Range    *Range        `json:"range,omitempty"`
+}
+
+// MarkupContent is Markdown or plain text shown by the editor.
+type MarkupContent struct {
+	Kind  string `json:"kind"`
+	Value string `json:"value"`
+}
+
+type textDocumentIdentifier struct {
+	URI string `json:"uri"`
+}
+
+type positionParams struct {
+	TextDocument textDocumentIdentifier `json:"textDocument"`
+	Position     Position               `json:"position"`
+}
+
+type didOpenParams struct {
+	TextDocument struct {
+		URI  string `json:"uri"`
+		Text string `json:"text"`
+	} `json:"textDocument"`
+}
+
+type didChangeParams struct {
+	TextDocument   textDocumentIdentifier `json:"textDocument"`
+	ContentChanges []struct {
+		Text string `json:"text"`
+	} `json:"contentChanges"`
+}
+
+type didCloseParams struct {
+	TextDocument textDocumentIdentifier `json:"textDocument"`
+}
+
+type publishDiagnosticsParams struct {
+	URI         string       `json:"uri"`
+	Diagnostics []Diagnostic `json:"diagnostics"`
+}
+
+// utf16Column converts a byte offset in line into the UTF-16 offset positions use.
+func utf16Column(line string, offset int) int {
+	column := 0
+	for i, r := range line {
+		if i >= offset {
+			break
+		}
+		column += utf16Len(r)
+	}
+	return column
+}
+
+// byteOffset converts a UTF-16 offset in line into a byte offset, clamped to the line length.
+func byteOffset(line string, column int) int {
+	units := 0
+	for i, r := range line {
+		if units >= column {
+			return i
+		}
+		units += utf16Len(r)
+	}
+	return len(line)
+}
+
+func utf16Len(r rune) int {
+	if r >= 0x10000 && utf8.ValidRune(r) {
+		return 2 //nolint:mnd // a surrogate pair
+	}
+	return 1
+}
+
+// uriPath returns the file path of a file:// URI.
+func uriPath(uri string) (string, bool) {
+	u, err := url.Parse(uri)
+	if err != nil || u.Scheme != "file" {
+		return "", false
+	}
+	return filepath.FromSlash(u.Path), true
+}
+
+// pathURI returns the file:// URI of an absolute path.
+func pathURI(path string) string {
+	return (&url.URL{Scheme: "file", Path: filepath.ToSlash(path)}).String()
+}
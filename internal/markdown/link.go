@@ -0,0 +1,54 @@
+package markdown
+
+import (
+	"regexp"
+	"strings"
+)
+
+// linkPattern matches a wiki-link: [[target]], [[target|alias]] or [[target#heading]].
+var linkPattern = regexp.MustCompile(`\[\[([^\[\]|#\n]+)(?:#([^\[\]|\n]*))?(?:\|([^\[\]\n]*))?\]\]`)
+
+// Link is a [[wiki-link]] to another memo.
+type Link struct {
+	// Line is the 1-based line number.
+	Line int
+	// Column is the 1-based byte offset of the opening brackets in the line.
+	Column int
+	// Raw is the link as written (e.g. "[[plan|the plan]]").
+	Raw string
+	// Target is the memo the link refers to, as written.
+	Target string
+	// Heading is the optional section after '#'.
+	Heading string
+	// Alias is the optional display text after '|'.
+	Alias string
+	// Text is the whole line containing the link.
+	Text string
+}
+
+// Links returns the wiki-links of content in order.
+func Links(content []byte) []Link {
+	var links []Link
+	for _, line := range Lines(content) {
+		for _, m := range linkPattern.FindAllStringSubmatchIndex(stripCodeSpans(line.Text), -1) {
+			link := Link{
+				Line:   line.Number,
+				Column: m[0] + 1,
+				Raw:    line.Text[m[0]:m[1]],
+				Target: strings.TrimSpace(line.Text[m[2]:m[3]]),
+				Text:   line.Text,
+			}
+			if m[4] >= 0 {
+				link.Heading = strings.TrimSpace(line.Text[m[4]:m[5]])
+			}
+			if m[6] >= 0 {
+				link.Alias = strings.TrimSpace(line.Text[m[6]:m[7]])
+			}
+			if link.Target != "" {
+				links = append(links, link)
+			}
+		}
+	}
+
+	return links
+}
@@ -0,0 +1,23 @@
+package markdown_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/sushichan044/memo-cli/internal/markdown"
+)
+
+func TestLinks(t *testing.T) {
+	content := []byte("See [[release notes]] and [[ 20251031/daily#Standup | today ]].\n" +
+		"`[[コード]]` [[]] [[a|b]]\n" +
+		"```\n[[fenced]]\n```\n")
+
+	links := markdown.Links(content)
+
+	assert.Equal(t, []markdown.Link{
+		{Line: 1, Column: 5, Raw: "[[release notes]]", Target: "release notes", Text: string(content[:63])},
+		{Line: 1, Column: 27, Raw: "[[ 20251031/daily#Standup | today ]]", Target: "20251031/daily", Heading: "Standup", Alias: "today", Text: string(content[:63])},
+		{Line: 2, Column: 22, Raw: "[[a|b]]", Target: "a", Alias: "b", Text: "`[[コード]]` [[]] [[a|b]]"},
+	}, links)
+}
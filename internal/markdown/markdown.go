@@ -0,0 +1,129 @@
+// Package markdown extracts structure from memo content without a full Markdown parser.
+//
+// Memos are scanned line by line. Front matter and fenced code blocks are skipped,
+// so examples inside code are never mistaken for real tasks, tags or links.
+package markdown
+
+import (
+	"bufio"
+	"bytes"
+	"strings"
+	"unicode/utf8"
+)
+
+const frontMatterDelimiter = "---"
+
+// Line is a line of Markdown content.
+type Line struct {
+	// Number is the 1-based line number.
+	Number int
+	// Text is the line without its line terminator.
+	Text string
+}
+
+// Lines returns the body lines of content, outside front matter and fenced code blocks, in order.
+func Lines(content []byte) []Line {
+	var lines []Line
+	fence := ""
+	_, bodyStart := frontMatter(content)
+
+	scanner := bufio.NewScanner(bytes.NewReader(content))
+	scanner.Buffer(nil, len(content)+1)
+	for n := 1; scanner.Scan(); n++ {
+		if n < bodyStart {
+			continue
+		}
+		line := scanner.Text()
+
+		trimmed := strings.TrimLeft(line, " ")
+		if marker := FenceMarker(trimmed); marker != "" {
+			switch {
+			case fence == "":
+				fence = marker
+				continue
+			case strings.HasPrefix(trimmed, fence) && strings.TrimSpace(trimmed[len(fence):]) == "":
+				fence = ""
+				continue
+			}
+		}
+		if fence != "" {
+			continue
+		}
+
+		lines = append(lines, Line{Number: n, Text: line})
+	}
+
+	return lines
+}
+
+// Body returns content without its front matter.
+func Body(content []byte) []byte {
+	if _, start := frontMatter(content); start > 1 {
+		lines := bytes.SplitAfterN(content, []byte("\n"), start)
+		return lines[len(lines)-1]
+	}
+	return content
+}
+
+// FrontMatterValue returns the scalar value of a top-level front matter field (e.g. "created: 2025-10-31"),
+// with surrounding quotes removed.
+func FrontMatterValue(content []byte, key string) (string, bool) {
+	lines, _ := frontMatter(content)
+	for _, line := range lines {
+		value, ok := strings.CutPrefix(line, key+":")
+		if ok {
+			return unquote(strings.TrimSpace(value)), true
+		}
+	}
+	return "", false
+}
+
+// frontMatter returns the lines of a leading "---" delimited front matter block
+// and the 1-based number of the first body line. Content without front matter starts at line 1.
+func frontMatter(content []byte) ([]string, int) {
+	text := strings.ReplaceAll(string(content), "\r\n", "\n")
+	if !strings.HasPrefix(text, frontMatterDelimiter+"\n") {
+		return nil, 1
+	}
+
+	lines := strings.Split(text, "\n")
+	for i := 1; i < len(lines); i++ {
+		if strings.TrimRight(lines[i], " ") == frontMatterDelimiter {
+			return lines[1:i], i + 2 //nolint:mnd // skip both delimiters, 1-based
+		}
+	}
+
+	return nil, 1
+}
+
+// FenceMarker returns the opening run of backticks or tildes of a code fence line, or "".
+// line must have its indentation trimmed.
+func FenceMarker(line string) string {
+	for _, c := range []string{"`", "~"} {
+		n := len(line) - len(strings.TrimLeft(line, c))
+		if n >= 3 { //nolint:mnd // a code fence is at least three characters
+			return line[:n]
+		}
+	}
+	return ""
+}
+
+// stripCodeSpans blanks out `inline code` so that its content is not scanned.
+func stripCodeSpans(line string) string {
+	var b strings.Builder
+	inCode := false
+	for _, r := range line {
+		if r == '`' {
+			inCode = !inCode
+			b.WriteRune(' ')
+			continue
+		}
+		if inCode {
+			// Keep byte offsets intact for callers that map matches back to the line.
+			b.WriteString(strings.Repeat(" ", utf8.RuneLen(r)))
+			continue
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
@@ -0,0 +1,50 @@
+package markdown_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/sushichan044/memo-cli/internal/markdown"
+)
+
+func TestLines_NestedFences(t *testing.T) {
+	content := []byte("a\n~~~~\n```\nb\n~~~~\nc\n")
+
+	assert.Equal(t, []markdown.Line{{Number: 1, Text: "a"}, {Number: 6, Text: "c"}}, markdown.Lines(content))
+}
+
+func TestLines_SkipsFrontMatter(t *testing.T) {
+	content := []byte("---\ntitle: x\n- [ ] not a task\n---\nbody\n")
+
+	assert.Equal(t, []markdown.Line{{Number: 5, Text: "body"}}, markdown.Lines(content))
+}
+
+func TestLines_UnterminatedFrontMatter(t *testing.T) {
+	content := []byte("---\nbody\n")
+
+	assert.Len(t, markdown.Lines(content), 2)
+}
+
+func TestBody(t *testing.T) {
+	assert.Equal(t, "# Title\n", string(markdown.Body([]byte("---\ntags: [a]\n---\n# Title\n"))))
+	assert.Equal(t, "# Title\n", string(markdown.Body([]byte("# Title\n"))))
+	assert.Equal(t, "---\nunclosed\n", string(markdown.Body([]byte("---\nunclosed\n"))))
+}
+
+func TestFrontMatterValue(t *testing.T) {
+	content := []byte("---\ntitle: \"Plan\"\ncreated: 2025-10-31 14:30\n  nested: x\n---\ncreated: body\n")
+
+	value, ok := markdown.FrontMatterValue(content, "created")
+	assert.True(t, ok)
+	assert.Equal(t, "2025-10-31 14:30", value)
+
+	value, ok = markdown.FrontMatterValue(content, "title")
+	assert.True(t, ok)
+	assert.Equal(t, "Plan", value)
+
+	_, ok = markdown.FrontMatterValue(content, "nested")
+	assert.False(t, ok)
+	_, ok = markdown.FrontMatterValue([]byte("created: body\n"), "created")
+	assert.False(t, ok)
+}
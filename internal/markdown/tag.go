@@ -0,0 +1,161 @@
+package markdown
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+)
+
+// tagPattern matches a #hashtag. Tags start with a letter so that issue references like #123 are not tags.
+var tagPattern = regexp.MustCompile(`(?:^|[\s(])#(\pL[\pL\pN_/-]*)`)
+
+// Tags returns the tags of a memo, lowercased, deduplicated and sorted.
+// Tags come from a "tags" front matter field, written as "tags: [a, b]", "tags: a, b" or a YAML list,
+// and from #hashtags in the body. Headings ("# Title") are not tags.
+func Tags(content []byte) []string {
+	tags := frontMatterTags(content)
+	for _, line := range Lines(content) {
+		tags = append(tags, lineTags(line.Text)...)
+	}
+
+	return normalizeTags(tags)
+}
+
+// HasTag reports whether tags contains tag, ignoring case and a leading '#'.
+func HasTag(tags []string, tag string) bool {
+	tag = strings.ToLower(strings.TrimPrefix(tag, "#"))
+	return slices.Contains(tags, tag)
+}
+
+func lineTags(line string) []string {
+	var tags []string
+	for _, m := range tagPattern.FindAllStringSubmatch(stripCodeSpans(line), -1) {
+		tags = append(tags, strings.TrimRight(m[1], "/-"))
+	}
+	return normalizeTags(tags)
+}
+
+func frontMatterTags(content []byte) []string {
+	lines, _ := frontMatter(content)
+
+	var tags []string
+	inList := false
+	for _, line := range lines {
+		if inList {
+			item, ok := strings.CutPrefix(strings.TrimSpace(line), "- ")
+			if ok {
+				tags = append(tags, unquote(item))
+				continue
+			}
+			inList = false
+		}
+
+		value, ok := strings.CutPrefix(line, "tags:")
+		if !ok {
+			continue
+		}
+		value = strings.TrimSpace(value)
+		if value == "" {
+			inList = true
+			continue
+		}
+
+		value = strings.TrimSuffix(strings.TrimPrefix(value, "["), "]")
+		for item := range strings.SplitSeq(value, ",") {
+			tags = append(tags, unquote(strings.TrimSpace(item)))
+		}
+	}
+
+	return tags
+}
+
+func unquote(s string) string {
+	return strings.Trim(s, `"'`)
+}
+
+func normalizeTags(tags []string) []string {
+	var normalized []string
+	for _, tag := range tags {
+		tag = strings.ToLower(strings.TrimPrefix(tag, "#"))
+		if tag != "" {
+			normalized = append(normalized, tag)
+		}
+	}
+	slices.Sort(normalized)
+	return slices.Compact(normalized)
+}
+
+// ErrInvalidTag is returned when a tag to add is not a valid #hashtag.
+var ErrInvalidTag = errors.New("invalid tag")
+
+// tagNamePattern matches a tag name without its '#'.
+var tagNamePattern = regexp.MustCompile(`^\pL[\pL\pN_/-]*$`)
+
+// ValidTag reports whether tag, without its '#', is a tag name that AddTag accepts. Front matter tags are
+// taken as written, so they may not be.
+func ValidTag(tag string) bool {
+	return tagNamePattern.MatchString(tag)
+}
+
+// AddTag returns content with tag added, without checking whether content already has it.
+// The tag joins the "tags" front matter field if there is one, or the front matter as a new "tags" field;
+// memos without front matter get it as a #hashtag on a last line of their own.
+func AddTag(content []byte, tag string) ([]byte, error) {
+	tag = strings.TrimPrefix(strings.TrimSpace(tag), "#")
+	if !ValidTag(tag) {
+		return nil, fmt.Errorf("%w: %q", ErrInvalidTag, tag)
+	}
+
+	text := string(content)
+	newline := "\n"
+	if strings.Contains(text, "\r\n") {
+		newline = "\r\n"
+	}
+
+	fields, bodyStart := frontMatter(content)
+	if bodyStart == 1 {
+		if text != "" && !strings.HasSuffix(text, "\n") {
+			text += newline
+		}
+		return []byte(text + "#" + tag + newline), nil
+	}
+
+	// lines[1:closing] are the front matter fields, as returned by frontMatter.
+	lines := strings.Split(text, "\n")
+	closing := len(fields) + 1
+	suffix := strings.TrimSuffix(newline, "\n")
+	for i := 1; i < closing; i++ {
+		value, ok := strings.CutPrefix(strings.TrimSuffix(lines[i], "\r"), "tags:")
+		if !ok {
+			continue
+		}
+		value = strings.TrimSpace(value)
+		switch {
+		case value == "":
+			// A block list: add an item after the last one, with the same indentation.
+			last, indent := i, "  "
+			for j := i + 1; j < closing; j++ {
+				item := strings.TrimSuffix(lines[j], "\r")
+				if !strings.HasPrefix(strings.TrimSpace(item), "- ") {
+					break
+				}
+				last, indent = j, item[:len(item)-len(strings.TrimLeft(item, " "))]
+			}
+			lines = slices.Insert(lines, last+1, indent+"- "+tag+suffix)
+		case strings.HasPrefix(value, "[") && strings.HasSuffix(value, "]"):
+			items := strings.TrimSpace(value[1 : len(value)-1])
+			if items != "" {
+				items += ", "
+			}
+			lines[i] = "tags: [" + items + tag + "]" + suffix
+		default:
+			lines[i] = "tags: " + value + ", " + tag + suffix
+		}
+		return []byte(strings.Join(lines, "\n")), nil
+	}
+
+	lines = slices.Insert(lines, closing, "tags: ["+tag+"]"+suffix)
+	return []byte(strings.Join(lines, "\n")), nil
+}
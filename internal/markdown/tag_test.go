@@ -0,0 +1,118 @@
+package markdown_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/sushichan044/memo-cli/internal/markdown"
+)
+
+func TestTags(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		want    []string
+	}{
+		{
+			name:    "inline hashtags",
+			content: "# Heading\nFixed #Bug in (#backend) see #123 and `#code`\n",
+			want:    []string{"backend", "bug"},
+		},
+		{
+			name:    "flow list front matter",
+			content: "---\ntags: [work, \"Q4\"]\n---\n#work again\n",
+			want:    []string{"q4", "work"},
+		},
+		{
+			name:    "block list front matter",
+			content: "---\ntitle: x\ntags:\n  - alpha\n  - beta\nauthor: me\n---\n",
+			want:    []string{"alpha", "beta"},
+		},
+		{
+			name:    "comma separated front matter",
+			content: "---\ntags: one, two\n---\n",
+			want:    []string{"one", "two"},
+		},
+		{
+			name:    "nested tags and fences",
+			content: "#project/memo-cli\n```\n#not-a-tag\n```\n",
+			want:    []string{"project/memo-cli"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, markdown.Tags([]byte(tt.content)))
+		})
+	}
+}
+
+func TestHasTag(t *testing.T) {
+	tags := []string{"bug", "ops"}
+
+	assert.True(t, markdown.HasTag(tags, "#Bug"))
+	assert.False(t, markdown.HasTag(tags, "feature"))
+}
+
+func TestAddTag(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		tag     string
+		want    string
+	}{
+		{
+			name:    "no front matter",
+			content: "# Notes\nbody",
+			tag:     "#work",
+			want:    "# Notes\nbody\n#work\n",
+		},
+		{
+			name: "empty memo",
+			tag:  "work",
+			want: "#work\n",
+		},
+		{
+			name:    "flow list",
+			content: "---\ntags: [a, b]\n---\nbody\n",
+			tag:     "work",
+			want:    "---\ntags: [a, b, work]\n---\nbody\n",
+		},
+		{
+			name:    "empty flow list",
+			content: "---\ntags: []\n---\n",
+			tag:     "work",
+			want:    "---\ntags: [work]\n---\n",
+		},
+		{
+			name:    "comma separated",
+			content: "---\ntags: a\n---\n",
+			tag:     "work",
+			want:    "---\ntags: a, work\n---\n",
+		},
+		{
+			name:    "block list",
+			content: "---\ntags:\n    - a\n    - b\nauthor: me\n---\n",
+			tag:     "work",
+			want:    "---\ntags:\n    - a\n    - b\n    - work\nauthor: me\n---\n",
+		},
+		{
+			name:    "front matter without tags",
+			content: "---\r\ncreated: 2025-10-31\r\n---\r\nbody\r\n",
+			tag:     "work",
+			want:    "---\r\ncreated: 2025-10-31\r\ntags: [work]\r\n---\r\nbody\r\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := markdown.AddTag([]byte(tt.content), tt.tag)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, string(got))
+			assert.True(t, markdown.HasTag(markdown.Tags(got), tt.tag))
+		})
+	}
+
+	_, err := markdown.AddTag(nil, "#123")
+	require.ErrorIs(t, err, markdown.ErrInvalidTag)
+}
@@ -0,0 +1,81 @@
+package markdown
+
+import (
+	"regexp"
+	"time"
+)
+
+var (
+	// taskPattern matches a list item with a checkbox, such as "- [ ] write report" or "  * [x] done".
+	taskPattern = regexp.MustCompile(`^(\s*)([-*+]|\d+[.)]) \[([ xX])\] (.*)$`)
+	// duePattern matches a due date written as @due(2025-11-01).
+	duePattern = regexp.MustCompile(`@due\((\d{4}-\d{2}-\d{2})\)`)
+)
+
+// Task is a Markdown task list item.
+type Task struct {
+	// Line is the 1-based line number.
+	Line int
+	// Done reports whether the checkbox is checked.
+	Done bool
+	// Text is the item text after the checkbox.
+	Text string
+	// Raw is the whole line, including indentation and the list marker.
+	Raw string
+	// Due is the date of an @due(YYYY-MM-DD) annotation in local time, or zero.
+	Due time.Time
+	// Tags are the #hashtags in the item text.
+	Tags []string
+}
+
+// Tasks returns the task list items of content in order.
+func Tasks(content []byte) []Task {
+	var tasks []Task
+	for _, line := range Lines(content) {
+		if task, ok := ParseTask(line.Text); ok {
+			task.Line = line.Number
+			tasks = append(tasks, task)
+		}
+	}
+
+	return tasks
+}
+
+// ParseTask parses a single line as a task list item. Line is left zero.
+func ParseTask(line string) (Task, bool) {
+	m := taskPattern.FindStringSubmatch(line)
+	if m == nil {
+		return Task{}, false
+	}
+
+	task := Task{
+		Done: m[3] != " ",
+		Text: m[4],
+		Raw:  line,
+		Tags: lineTags(m[4]),
+	}
+	if due := duePattern.FindStringSubmatch(m[4]); due != nil {
+		if t, err := time.ParseInLocation(time.DateOnly, due[1], time.Local); err == nil {
+			task.Due = t
+		}
+	}
+
+	return task, true
+}
+
+// ToggleTask flips the checkbox of a task line and returns the new line.
+// The second result is false if line is not a task.
+func ToggleTask(line string) (string, bool) {
+	m := taskPattern.FindStringSubmatchIndex(line)
+	if m == nil {
+		return "", false
+	}
+
+	box := line[m[6]:m[7]]
+	checked := "x"
+	if box != " " {
+		checked = " "
+	}
+
+	return line[:m[6]] + checked + line[m[7]:], true
+}
@@ -0,0 +1,65 @@
+package markdown_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/sushichan044/memo-cli/internal/markdown"
+)
+
+func TestTasks(t *testing.T) {
+	content := []byte(`# Today
+
+- [ ] write report
+  * [x] send draft
+1. [X] numbered
+- [] not a task
+- plain item
+
+` + "```md" + `
+- [ ] inside a fence
+` + "```" + `
++ [ ] after the fence
+`)
+
+	tasks := markdown.Tasks(content)
+
+	assert.Equal(t, []markdown.Task{
+		{Line: 3, Done: false, Text: "write report", Raw: "- [ ] write report"},
+		{Line: 4, Done: true, Text: "send draft", Raw: "  * [x] send draft"},
+		{Line: 5, Done: true, Text: "numbered", Raw: "1. [X] numbered"},
+		{Line: 12, Done: false, Text: "after the fence", Raw: "+ [ ] after the fence"},
+	}, tasks)
+}
+
+func TestParseTask_DueAndTags(t *testing.T) {
+	task, ok := markdown.ParseTask("- [ ] renew cert @due(2025-11-01) #ops #Infra")
+
+	assert.True(t, ok)
+	assert.Equal(t, time.Date(2025, 11, 1, 0, 0, 0, 0, time.Local), task.Due)
+	assert.Equal(t, []string{"infra", "ops"}, task.Tags)
+
+	task, ok = markdown.ParseTask("- [ ] bad date @due(2025-13-40)")
+	assert.True(t, ok)
+	assert.True(t, task.Due.IsZero())
+}
+
+func TestToggleTask(t *testing.T) {
+	tests := []struct {
+		in, want string
+	}{
+		{"- [ ] ship", "- [x] ship"},
+		{"  * [x] ship [ ] literally", "  * [ ] ship [ ] literally"},
+		{"1. [X] ship", "1. [ ] ship"},
+	}
+	for _, tt := range tests {
+		got, ok := markdown.ToggleTask(tt.in)
+		assert.True(t, ok)
+		assert.Equal(t, tt.want, got)
+	}
+
+	_, ok := markdown.ToggleTask("- plain item")
+	assert.False(t, ok)
+}
@@ -0,0 +1,242 @@
+// Package mcp serves the memo root to AI agents over the Model Context Protocol (MCP) on stdio.
+//
+// Agents get tools to create, append to, read, list and search memos, and the most recent memos
+// as resources with "memo:///YYYYMMDD/HH-MM-SS-name.ext" URIs.
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/fs"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/sushichan044/memo-cli/internal/crypt"
+	"github.com/sushichan044/memo-cli/internal/hook"
+	"github.com/sushichan044/memo-cli/internal/jsonrpc"
+	"github.com/sushichan044/memo-cli/internal/memo"
+	"github.com/sushichan044/memo-cli/internal/memofs"
+)
+
+const (
+	// ProtocolVersion is the latest MCP revision implemented.
+	ProtocolVersion = "2025-06-18"
+	// URIScheme prefixes memo resource URIs, followed by the path of the memo relative to the memo root.
+	URIScheme = "memo:///"
+
+	// recentResources is the number of memos listed as resources.
+	recentResources = 20
+	// codeResourceNotFound is the MCP error code of unknown resources.
+	codeResourceNotFound = -32002
+)
+
+//nolint:gochecknoglobals // constant table
+var supportedVersions = []string{ProtocolVersion, "2025-03-26", "2024-11-05"}
+
+// instructions tells agents what the server is for.
+const instructions = "Memos are Markdown notes of the user, stored by day. " +
+	"Jot findings down with create_memo or append_memo, and look up earlier notes with search_memos, " +
+	"list_memos and read_memo. Memos are referred to by @latest, their path (YYYYMMDD/HH-MM-SS-name.md) or name."
+
+// Options configures a Server.
+type Options struct {
+	// Creator creates new memos.
+	Creator *memo.Creator
+	// Keyring reads memos, and encrypts memos created with "encrypt".
+	Keyring *crypt.Keyring
+	// FilePerm is applied to memos written by append_memo.
+	FilePerm fs.FileMode
+	// Version is reported as the server version.
+	Version string
+	// Record is called after a memo was created or changed, with a history message such as
+	// "append 20251031/14-30-45-notes.md". It may be nil.
+	Record func(message string)
+	// Hooks run the post-edit hook after append_memo. It may be nil; memos are created by Creator,
+	// which runs its own hooks.
+	Hooks *hook.Runner
+}
+
+// Server is an MCP server of the memos in a memo root.
+type Server struct {
+	fsys memofs.FS
+	opts Options
+	// mu serializes changes to memos.
+	mu sync.Mutex
+}
+
+// New returns a Server of the memos in fsys.
+func New(fsys memofs.FS, opts Options) *Server {
+	return &Server{fsys: fsys, opts: opts}
+}
+
+// Serve speaks MCP on r and w, usually stdin and stdout, until r is exhausted or ctx is done.
+func (s *Server) Serve(ctx context.Context, r io.Reader, w io.Writer) error {
+	return jsonrpc.NewConn(r, w, jsonrpc.Lines).Serve(ctx, s.handle)
+}
+
+func (s *Server) handle(_ context.Context, method string, params json.RawMessage) (any, error) {
+	switch method {
+	case "initialize":
+		return s.initialize(params)
+	case "ping":
+		return struct{}{}, nil
+	case "tools/list":
+		return map[string]any{"tools": tools}, nil
+	case "tools/call":
+		return s.callTool(params)
+	case "resources/list":
+		return s.listResources()
+	case "resources/templates/list":
+		return map[string]any{"resourceTemplates": []resourceTemplate{{
+			URITemplate: URIScheme + "{path}",
+			Name:        "memo",
+			Description: "A memo by its path relative to the memo root, e.g. " + URIScheme + "20251031/14-30-45-notes.md",
+		}}}, nil
+	case "resources/read":
+		return s.readResource(params)
+	default:
+		if strings.HasPrefix(method, "notifications/") {
+			return nil, nil
+		}
+		return nil, jsonrpc.ErrMethodNotFound
+	}
+}
+
+type implementation struct {
+	Name    string `json:"name"`
+	Version string `json:"version"`
+}
+
+type initializeResult struct {
+	ProtocolVersion string         `json:"protocolVersion"`
+	Capabilities    map[string]any `json:"capabilities"`
+	ServerInfo      implementation `json:"serverInfo"`
+	Instructions    string         `json:"instructions"`
+}
+
+// initialize agrees on the protocol version: the client's if supported, otherwise the latest one.
+func (s *Server) initialize(params json.RawMessage) (any, error) {
+	var req struct {
+		ProtocolVersion string `json:"protocolVersion"`
+	}
+	if err := unmarshal(params, &req); err != nil {
+		return nil, err
+	}
+
+	protocol := ProtocolVersion
+	if slices.Contains(supportedVersions, req.ProtocolVersion) {
+		protocol = req.ProtocolVersion
+	}
+
+	return initializeResult{
+		ProtocolVersion: protocol,
+		Capabilities: map[string]any{
+			"tools":     map[string]any{"listChanged": false},
+			"resources": map[string]any{"listChanged": false, "subscribe": false},
+		},
+		ServerInfo:   implementation{Name: "memo", Version: s.opts.Version},
+		Instructions: instructions,
+	}, nil
+}
+
+type resource struct {
+	URI         string `json:"uri"`
+	Name        string `json:"name"`
+	Title       string `json:"title,omitempty"`
+	Description string `json:"description,omitempty"`
+	MimeType    string `json:"mimeType"`
+}
+
+type resourceTemplate struct {
+	URITemplate string `json:"uriTemplate"`
+	Name        string `json:"name"`
+	Description string `json:"description"`
+}
+
+type resourceContents struct {
+	URI      string `json:"uri"`
+	MimeType string `json:"mimeType"`
+	Text     string `json:"text"`
+}
+
+func (s *Server) listResources() (any, error) {
+	entries, err := memo.List(s.fsys)
+	if err != nil {
+		return nil, err
+	}
+
+	resources := make([]resource, 0, recentResources)
+	for _, entry := range entries[:min(len(entries), recentResources)] {
+		description := "Created " + entry.CreatedAt.Format("2006-01-02 15:04")
+		if entry.Encrypted {
+			description += ", encrypted"
+		}
+		resources = append(resources, resource{
+			URI:         URIScheme + entry.RelPath,
+			Name:        entry.RelPath,
+			Title:       entry.Title(),
+			Description: description,
+			MimeType:    mimeType(entry),
+		})
+	}
+
+	return map[string]any{"resources": resources}, nil
+}
+
+func (s *Server) readResource(params json.RawMessage) (any, error) {
+	var req struct {
+		URI string `json:"uri"`
+	}
+	if err := unmarshal(params, &req); err != nil {
+		return nil, err
+	}
+
+	relPath, ok := strings.CutPrefix(req.URI, URIScheme)
+	if !ok {
+		return nil, &jsonrpc.Error{Code: codeResourceNotFound, Message: "not a memo URI: " + req.URI}
+	}
+	entries, err := memo.List(s.fsys)
+	if err != nil {
+		return nil, err
+	}
+	index := slices.IndexFunc(entries, func(e memo.Entry) bool { return e.RelPath == relPath })
+	if index < 0 {
+		return nil, &jsonrpc.Error{Code: codeResourceNotFound, Message: "memo not found: " + req.URI}
+	}
+
+	content, err := s.opts.Keyring.ReadFile(s.fsys, relPath)
+	if err != nil {
+		return nil, err
+	}
+
+	return map[string]any{"contents": []resourceContents{{
+		URI: req.URI, MimeType: mimeType(entries[index]), Text: string(content),
+	}}}, nil
+}
+
+func mimeType(entry memo.Entry) string {
+	if entry.Ext == "md" || entry.Ext == "markdown" {
+		return "text/markdown"
+	}
+	return "text/plain"
+}
+
+// unmarshal decodes params, reporting malformed ones as invalid params.
+func unmarshal(params json.RawMessage, v any) error {
+	if len(params) == 0 {
+		return nil
+	}
+	if err := json.Unmarshal(params, v); err != nil {
+		return jsonrpc.InvalidParams(err)
+	}
+	return nil
+}
+
+func (s *Server) record(format string, args ...any) {
+	if s.opts.Record != nil {
+		s.opts.Record(fmt.Sprintf(format, args...))
+	}
+}
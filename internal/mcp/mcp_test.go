@@ -0,0 +1,273 @@
+package mcp_test
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/sushichan044/memo-cli/internal/config"
+	"github.com/sushichan044/memo-cli/internal/crypt"
+	"github.com/sushichan044/memo-cli/internal/mcp"
+	"github.com/sushichan044/memo-cli/internal/memo"
+	"github.com/sushichan044/memo-cli/internal/memofs"
+)
+
+// client talks to a server running on the other end of in-process pipes, as an agent does over stdio.
+type client struct {
+	t      *testing.T
+	in     *io.PipeWriter
+	out    *bufio.Scanner
+	nextID int
+	done   chan error
+}
+
+type response struct {
+	ID     int             `json:"id"`
+	Result json.RawMessage `json:"result"`
+	Error  *struct {
+		Code    int    `json:"code"`
+		Message string `json:"message"`
+	} `json:"error"`
+}
+
+func start(t *testing.T, files map[string]string) (*client, *memofs.Mem, *[]string) {
+	t.Helper()
+
+	fsys := memofs.NewMem("/memo")
+	for name, content := range files {
+		require.NoError(t, fsys.MkdirAll(name[:8], 0o700))
+		require.NoError(t, fsys.WriteFile(name, []byte(content), 0o600))
+	}
+	cfg := &config.Config{BaseDir: "/memo"}
+	var recorded []string
+	srv := mcp.New(fsys, mcp.Options{
+		Creator:  memo.NewWithFS(cfg, fsys),
+		Keyring:  crypt.NewKeyring(cfg.Encryption),
+		FilePerm: 0o600,
+		Version:  "test",
+		Record:   func(message string) { recorded = append(recorded, message) },
+	})
+
+	stdinR, stdinW := io.Pipe()
+	stdoutR, stdoutW := io.Pipe()
+	c := &client{t: t, in: stdinW, out: bufio.NewScanner(stdoutR), done: make(chan error, 1)}
+	go func() {
+		c.done <- srv.Serve(context.Background(), stdinR, stdoutW)
+		stdoutW.Close()
+	}()
+	t.Cleanup(func() {
+		stdinW.Close()
+		require.NoError(t, <-c.done)
+	})
+
+	return c, fsys, &recorded
+}
+
+func (c *client) send(line string) {
+	c.t.Helper()
+	_, err := io.WriteString(c.in, line+"\n")
+	require.NoError(c.t, err)
+}
+
+func (c *client) call(method string, params any) response {
+	c.t.Helper()
+
+	c.nextID++
+	data, err := json.Marshal(map[string]any{"jsonrpc": "2.0", "id": c.nextID, "method": method, "params": params})
+	require.NoError(c.t, err)
+	c.send(string(data))
+
+	require.True(c.t, c.out.Scan(), "no response to %s", method)
+	var resp response
+	require.NoError(c.t, json.Unmarshal(c.out.Bytes(), &resp))
+	require.Equal(c.t, c.nextID, resp.ID)
+	return resp
+}
+
+type toolResult struct {
+	Content []struct {
+		Type string `json:"type"`
+		Text string `json:"text"`
+	} `json:"content"`
+	StructuredContent json.RawMessage `json:"structuredContent"`
+	IsError           bool            `json:"isError"`
+}
+
+func (c *client) tool(name string, args map[string]any) toolResult {
+	c.t.Helper()
+
+	resp := c.call("tools/call", map[string]any{"name": name, "arguments": args})
+	require.Nil(c.t, resp.Error)
+	var result toolResult
+	require.NoError(c.t, json.Unmarshal(resp.Result, &result))
+	require.Len(c.t, result.Content, 1)
+	return result
+}
+
+func TestServer_Initialize(t *testing.T) {
+	c, _, _ := start(t, nil)
+
+	resp := c.call("initialize", map[string]any{
+		"protocolVersion": "2024-11-05",
+		"capabilities":    map[string]any{},
+		"clientInfo":      map[string]any{"name": "test", "version": "1"},
+	})
+	require.Nil(t, resp.Error)
+	var result struct {
+		ProtocolVersion string                     `json:"protocolVersion"`
+		Capabilities    map[string]json.RawMessage `json:"capabilities"`
+		ServerInfo      struct{ Name, Version string }
+	}
+	require.NoError(t, json.Unmarshal(resp.Result, &result))
+	assert.Equal(t, "2024-11-05", result.ProtocolVersion)
+	assert.Contains(t, result.Capabilities, "tools")
+	assert.Contains(t, result.Capabilities, "resources")
+	assert.Equal(t, "memo", result.ServerInfo.Name)
+
+	// Notifications get no response; the next line read answers the ping.
+	c.send(`{"jsonrpc":"2.0","method":"notifications/initialized"}`)
+	assert.Nil(t, c.call("ping", nil).Error)
+
+	resp = c.call("initialize", map[string]any{"protocolVersion": "1999-01-01"})
+	require.NoError(t, json.Unmarshal(resp.Result, &result))
+	assert.Equal(t, mcp.ProtocolVersion, result.ProtocolVersion)
+
+	resp = c.call("prompts/list", nil)
+	require.NotNil(t, resp.Error)
+	assert.Equal(t, -32601, resp.Error.Code)
+}
+
+func TestServer_ToolsList(t *testing.T) {
+	c, _, _ := start(t, nil)
+
+	var result struct {
+		Tools []struct {
+			Name        string         `json:"name"`
+			InputSchema map[string]any `json:"inputSchema"`
+		} `json:"tools"`
+	}
+	require.NoError(t, json.Unmarshal(c.call("tools/list", nil).Result, &result))
+
+	var names []string
+	for _, tool := range result.Tools {
+		names = append(names, tool.Name)
+		assert.Equal(t, "object", tool.InputSchema["type"], tool.Name)
+	}
+	assert.Equal(t, []string{"create_memo", "append_memo", "read_memo", "list_memos", "search_memos"}, names)
+}
+
+func TestServer_CreateAppendRead(t *testing.T) {
+	c, fsys, recorded := start(t, nil)
+
+	created := c.tool("create_memo", map[string]any{"name": "findings", "content": "# Findings\n"})
+	require.False(t, created.IsError, created.Content[0].Text)
+	var entry struct {
+		RelPath string `json:"rel_path"`
+		Name    string `json:"name"`
+	}
+	require.NoError(t, json.Unmarshal(created.StructuredContent, &entry))
+	assert.Equal(t, "findings", entry.Name)
+	assert.Equal(t, "Created "+entry.RelPath, created.Content[0].Text)
+
+	appended := c.tool("append_memo", map[string]any{"ref": "findings", "text": "- flaky test in CI"})
+	require.False(t, appended.IsError, appended.Content[0].Text)
+
+	content, err := fsys.ReadFile(entry.RelPath)
+	require.NoError(t, err)
+	assert.Equal(t, "# Findings\n- flaky test in CI\n", string(content))
+
+	read := c.tool("read_memo", map[string]any{"ref": "@latest"})
+	assert.Equal(t, "# Findings\n- flaky test in CI\n", read.Content[0].Text)
+
+	assert.Equal(t, []string{"new " + entry.RelPath, "append " + entry.RelPath}, *recorded)
+}
+
+func TestServer_ToolErrors(t *testing.T) {
+	c, _, _ := start(t, nil)
+
+	missing := c.tool("read_memo", map[string]any{"ref": "nothing"})
+	assert.True(t, missing.IsError)
+	assert.Contains(t, missing.Content[0].Text, "memo not found")
+
+	invalid := c.tool("search_memos", map[string]any{"query": "("})
+	assert.True(t, invalid.IsError)
+
+	resp := c.call("tools/call", map[string]any{"name": "delete_everything"})
+	require.NotNil(t, resp.Error)
+	assert.Equal(t, -32602, resp.Error.Code)
+
+	resp = c.call("tools/call", map[string]any{"name": "read_memo", "arguments": map[string]any{"ref": 1}})
+	require.NotNil(t, resp.Error)
+	assert.Equal(t, -32602, resp.Error.Code)
+}
+
+func TestServer_ListAndSearch(t *testing.T) {
+	c, _, _ := start(t, map[string]string{
+		"20251030/09-00-00-design.md": "# Design #ux\nRelease plan\n",
+		"20251031/14-30-45-notes.md":  "# Notes\nrelease checklist\n",
+		"20251031/daily.md":           "# 2025-10-31\n",
+	})
+
+	list := c.tool("list_memos", map[string]any{"since": "2025-10-31"})
+	assert.Equal(t, "20251031/14-30-45-notes.md\n20251031/daily.md", list.Content[0].Text)
+
+	list = c.tool("list_memos", map[string]any{"tag": "ux"})
+	assert.Equal(t, "20251030/09-00-00-design.md", list.Content[0].Text)
+
+	list = c.tool("list_memos", map[string]any{"limit": 1})
+	var structured struct {
+		Memos []json.RawMessage `json:"memos"`
+	}
+	require.NoError(t, json.Unmarshal(list.StructuredContent, &structured))
+	assert.Len(t, structured.Memos, 1)
+
+	search := c.tool("search_memos", map[string]any{"query": "release", "ignore_case": true})
+	assert.Equal(t, "20251031/14-30-45-notes.md:2:release checklist\n20251030/09-00-00-design.md:2:Release plan",
+		search.Content[0].Text)
+
+	search = c.tool("search_memos", map[string]any{"query": "nothing"})
+	assert.Equal(t, "No matches.", search.Content[0].Text)
+}
+
+func TestServer_Resources(t *testing.T) {
+	files := map[string]string{}
+	for i := range 25 {
+		files[fmt.Sprintf("20251031/10-00-%02d-note.md", i)] = fmt.Sprintf("note %d\n", i)
+	}
+	c, _, _ := start(t, files)
+
+	var list struct {
+		Resources []struct {
+			URI      string `json:"uri"`
+			MimeType string `json:"mimeType"`
+		} `json:"resources"`
+	}
+	require.NoError(t, json.Unmarshal(c.call("resources/list", nil).Result, &list))
+	require.Len(t, list.Resources, 20)
+	assert.Equal(t, "memo:///20251031/10-00-24-note.md", list.Resources[0].URI)
+	assert.Equal(t, "text/markdown", list.Resources[0].MimeType)
+
+	var read struct {
+		Contents []struct {
+			URI  string `json:"uri"`
+			Text string `json:"text"`
+		} `json:"contents"`
+	}
+	resp := c.call("resources/read", map[string]any{"uri": list.Resources[0].URI})
+	require.NoError(t, json.Unmarshal(resp.Result, &read))
+	require.Len(t, read.Contents, 1)
+	assert.Equal(t, "note 24\n", read.Contents[0].Text)
+
+	resp = c.call("resources/read", map[string]any{"uri": "memo:///20251031/missing.md"})
+	require.NotNil(t, resp.Error)
+	assert.Equal(t, -32002, resp.Error.Code)
+
+	resp = c.call("resources/templates/list", nil)
+	assert.Contains(t, string(resp.Result), `"uriTemplate":"memo:///{path}"`)
+}
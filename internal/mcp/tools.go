@@ -0,0 +1,353 @@
+package mcp
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/sushichan044/memo-cli/internal/crypt"
+	"github.com/sushichan044/memo-cli/internal/daily"
+	"github.com/sushichan044/memo-cli/internal/hook"
+	"github.com/sushichan044/memo-cli/internal/jsonrpc"
+	"github.com/sushichan044/memo-cli/internal/markdown"
+	"github.com/sushichan044/memo-cli/internal/memo"
+	"github.com/sushichan044/memo-cli/schema"
+)
+
+const (
+	defaultListLimit   = 50
+	defaultSearchLimit = 100
+)
+
+type tool struct {
+	Name        string         `json:"name"`
+	Description string         `json:"description"`
+	InputSchema map[string]any `json:"inputSchema"`
+	Annotations annotations    `json:"annotations"`
+}
+
+type annotations struct {
+	ReadOnlyHint    bool `json:"readOnlyHint"`
+	DestructiveHint bool `json:"destructiveHint"`
+}
+
+type textContent struct {
+	Type string `json:"type"`
+	Text string `json:"text"`
+}
+
+type toolResult struct {
+	Content           []textContent `json:"content"`
+	StructuredContent any           `json:"structuredContent,omitempty"`
+	IsError           bool          `json:"isError,omitempty"`
+}
+
+// objectSchema returns the JSON Schema of tool arguments.
+func objectSchema(properties map[string]any, required ...string) map[string]any {
+	s := map[string]any{"type": "object", "properties": properties}
+	if len(required) > 0 {
+		s["required"] = required
+	}
+	return s
+}
+
+func prop(typ, description string) map[string]any {
+	return map[string]any{"type": typ, "description": description}
+}
+
+const refDescription = "Memo to use: @latest, a path relative to the memo root (YYYYMMDD/HH-MM-SS-name.md) or a memo name"
+
+//nolint:gochecknoglobals // constant table
+var tools = []tool{
+	{
+		Name:        "create_memo",
+		Description: "Create a new memo in today's directory and return its path.",
+		InputSchema: objectSchema(map[string]any{
+			"name":    prop("string", "Memo name, part of the file name (default: a HH-MM-SS timestamp only)"),
+			"content": prop("string", "Initial content, usually Markdown"),
+			"ext":     prop("string", "File extension (default: md)"),
+			"encrypt": prop("boolean", "Encrypt the memo with age to the configured recipients"),
+		}),
+	},
+	{
+		Name:        "append_memo",
+		Description: "Append text as new lines at the end of an existing memo.",
+		InputSchema: objectSchema(map[string]any{
+			"ref":  prop("string", refDescription),
+			"text": prop("string", "Text to append"),
+		}, "ref", "text"),
+	},
+	{
+		Name:        "read_memo",
+		Description: "Read the content of a memo, decrypted if needed.",
+		InputSchema: objectSchema(map[string]any{"ref": prop("string", refDescription)}, "ref"),
+		Annotations: annotations{ReadOnlyHint: true},
+	},
+	{
+		Name:        "list_memos",
+		Description: "List memos, newest first.",
+		InputSchema: objectSchema(map[string]any{
+			"since": prop("string", "Only memos created on or after this day (YYYY-MM-DD, today, yesterday)"),
+			"until": prop("string", "Only memos created on or before this day (YYYY-MM-DD, today, yesterday)"),
+			"tag":   prop("string", "Only memos with this #tag or front matter tag"),
+			"limit": prop("integer", fmt.Sprintf("Maximum number of memos (default: %d)", defaultListLimit)),
+		}),
+		Annotations: annotations{ReadOnlyHint: true},
+	},
+	{
+		Name:        "search_memos",
+		Description: "Search the lines of all memos with a regular expression, newest memos first.",
+		InputSchema: objectSchema(map[string]any{
+			"query":       prop("string", "Regular expression (RE2 syntax) to search for"),
+			"ignore_case": prop("boolean", "Match case-insensitively"),
+			"limit":       prop("integer", fmt.Sprintf("Maximum number of matching lines (default: %d)", defaultSearchLimit)),
+		}, "query"),
+		Annotations: annotations{ReadOnlyHint: true},
+	},
+}
+
+// callTool runs a tool. Failures of the tool itself are results flagged with isError, so that the agent sees them;
+// only unknown tools and malformed arguments are protocol errors.
+func (s *Server) callTool(params json.RawMessage) (any, error) {
+	var req struct {
+		Name      string          `json:"name"`
+		Arguments json.RawMessage `json:"arguments"`
+	}
+	if err := unmarshal(params, &req); err != nil {
+		return nil, err
+	}
+
+	var (
+		result toolResult
+		err    error
+	)
+	switch req.Name {
+	case "create_memo":
+		result, err = s.createMemo(req.Arguments)
+	case "append_memo":
+		result, err = s.appendMemo(req.Arguments)
+	case "read_memo":
+		result, err = s.readMemo(req.Arguments)
+	case "list_memos":
+		result, err = s.listMemos(req.Arguments)
+	case "search_memos":
+		result, err = s.searchMemos(req.Arguments)
+	default:
+		return nil, jsonrpc.InvalidParams(fmt.Errorf("unknown tool %q", req.Name))
+	}
+
+	var rpcErr *jsonrpc.Error
+	if errors.As(err, &rpcErr) {
+		return nil, err
+	}
+	if err != nil {
+		return toolResult{Content: []textContent{{Type: "text", Text: err.Error()}}, IsError: true}, nil
+	}
+	return result, nil
+}
+
+func text(s string) []textContent {
+	return []textContent{{Type: "text", Text: s}}
+}
+
+func (s *Server) createMemo(args json.RawMessage) (toolResult, error) {
+	var req struct {
+		Name    string `json:"name"`
+		Content string `json:"content"`
+		Ext     string `json:"ext"`
+		Encrypt bool   `json:"encrypt"`
+	}
+	if err := unmarshal(args, &req); err != nil {
+		return toolResult{}, err
+	}
+
+	opts := memo.CreateOptions{Content: []byte(req.Content)}
+	if req.Encrypt {
+		recipients, err := s.opts.Keyring.Recipients()
+		if err != nil {
+			return toolResult{}, err
+		}
+		opts.Recipients = recipients
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	path, err := s.opts.Creator.CreateWith(req.Name, req.Ext, opts)
+	if err != nil {
+		return toolResult{}, err
+	}
+	entry, err := memo.NewEntry(s.fsys, path)
+	if err != nil {
+		return toolResult{}, err
+	}
+	s.record("new %s", entry.RelPath)
+
+	return toolResult{Content: text("Created " + entry.RelPath), StructuredContent: entry.Schema()}, nil
+}
+
+func (s *Server) appendMemo(args json.RawMessage) (toolResult, error) {
+	var req struct {
+		Ref  string `json:"ref"`
+		Text string `json:"text"`
+	}
+	if err := unmarshal(args, &req); err != nil {
+		return toolResult{}, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	entry, err := memo.Resolve(s.fsys, req.Ref)
+	if err != nil {
+		return toolResult{}, err
+	}
+	appended := req.Text
+	if !strings.HasSuffix(appended, "\n") {
+		appended += "\n"
+	}
+	if err = memo.Append(s.fsys, s.opts.Keyring, entry, []byte(appended), s.opts.FilePerm); err != nil {
+		return toolResult{}, err
+	}
+	_ = s.opts.Hooks.Run(hook.PostEdit, entry.Schema())
+	s.record("append %s", entry.RelPath)
+
+	return toolResult{Content: text("Appended to " + entry.RelPath), StructuredContent: entry.Schema()}, nil
+}
+
+func (s *Server) readMemo(args json.RawMessage) (toolResult, error) {
+	var req struct {
+		Ref string `json:"ref"`
+	}
+	if err := unmarshal(args, &req); err != nil {
+		return toolResult{}, err
+	}
+
+	entry, err := memo.Resolve(s.fsys, req.Ref)
+	if err != nil {
+		return toolResult{}, err
+	}
+	content, err := s.opts.Keyring.ReadFile(s.fsys, entry.RelPath)
+	if err != nil {
+		return toolResult{}, err
+	}
+
+	return toolResult{
+		Content:           text(string(content)),
+		StructuredContent: schema.Content{Memo: entry.Schema(), Content: string(content)},
+	}, nil
+}
+
+func (s *Server) listMemos(args json.RawMessage) (toolResult, error) {
+	var req struct {
+		Since string `json:"since"`
+		Until string `json:"until"`
+		Tag   string `json:"tag"`
+		Limit int    `json:"limit"`
+	}
+	if err := unmarshal(args, &req); err != nil {
+		return toolResult{}, err
+	}
+	since, err := daily.ParseOptionalDate(req.Since, time.Now())
+	if err != nil {
+		return toolResult{}, err
+	}
+	until, err := daily.ParseOptionalDate(req.Until, time.Now())
+	if err != nil {
+		return toolResult{}, err
+	}
+	if req.Limit <= 0 {
+		req.Limit = defaultListLimit
+	}
+
+	entries, err := memo.List(s.fsys)
+	if err != nil {
+		return toolResult{}, err
+	}
+
+	var lines []string
+	memos := []schema.Memo{}
+	for _, entry := range entries {
+		if len(memos) == req.Limit {
+			break
+		}
+		if (!since.IsZero() && entry.DateDir < memo.DateDir(since)) || (!until.IsZero() && entry.DateDir > memo.DateDir(until)) {
+			continue
+		}
+
+		if req.Tag != "" {
+			content, readErr := s.readable(entry)
+			if readErr != nil {
+				return toolResult{}, readErr
+			}
+			if !markdown.HasTag(markdown.Tags(content), req.Tag) {
+				continue
+			}
+		}
+		lines = append(lines, entry.RelPath)
+		memos = append(memos, entry.Schema())
+	}
+
+	summary := strings.Join(lines, "\n")
+	if len(lines) == 0 {
+		summary = "No memos found."
+	}
+	return toolResult{Content: text(summary), StructuredContent: map[string]any{"memos": memos}}, nil
+}
+
+func (s *Server) searchMemos(args json.RawMessage) (toolResult, error) {
+	var req struct {
+		Query      string `json:"query"`
+		IgnoreCase bool   `json:"ignore_case"`
+		Limit      int    `json:"limit"`
+	}
+	if err := unmarshal(args, &req); err != nil {
+		return toolResult{}, err
+	}
+	if req.Limit <= 0 {
+		req.Limit = defaultSearchLimit
+	}
+
+	pattern := req.Query
+	if req.IgnoreCase {
+		pattern = "(?i)" + pattern
+	}
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return toolResult{}, fmt.Errorf("invalid query: %w", err)
+	}
+
+	entries, err := memo.List(s.fsys)
+	if err != nil {
+		return toolResult{}, err
+	}
+	found, err := memo.Search(entries, re, s.readable)
+	if err != nil {
+		return toolResult{}, err
+	}
+
+	var lines []string
+	matches := []schema.Match{}
+	for _, m := range found[:min(len(found), req.Limit)] {
+		lines = append(lines, fmt.Sprintf("%s:%d:%s", m.Entry.RelPath, m.Line, m.Text))
+		matches = append(matches, schema.Match{Memo: m.Entry.Schema(), Line: m.Line, Text: m.Text})
+	}
+
+	summary := strings.Join(lines, "\n")
+	if len(lines) == 0 {
+		summary = "No matches."
+	}
+	return toolResult{Content: text(summary), StructuredContent: map[string]any{"matches": matches}}, nil
+}
+
+// readable reads a memo for scans over every memo, skipping encrypted memos that cannot be decrypted.
+func (s *Server) readable(entry memo.Entry) ([]byte, error) {
+	content, err := s.opts.Keyring.ReadFile(s.fsys, entry.RelPath)
+	if errors.Is(err, crypt.ErrNoIdentity) {
+		return nil, nil
+	}
+	return content, err
+}
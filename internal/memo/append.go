@@ -0,0 +1,36 @@
+package memo
+
+import (
+	"bytes"
+	"fmt"
+	"io/fs"
+
+	"github.com/sushichan044/memo-cli/internal/crypt"
+	"github.com/sushichan044/memo-cli/internal/memofs"
+)
+
+// Append adds text to the end of the memo of entry, re-encrypting it if it is encrypted.
+// The text starts on a new line even if the memo does not end with one.
+func Append(fsys memofs.FS, keyring *crypt.Keyring, entry Entry, text []byte, perm fs.FileMode) error {
+	content, err := keyring.ReadFile(fsys, entry.RelPath)
+	if err != nil {
+		return err
+	}
+
+	// Never glue the appended text onto an unterminated last line.
+	if len(content) > 0 && !bytes.HasSuffix(content, []byte("\n")) {
+		content = append(content, '\n')
+	}
+	content = append(content, text...)
+
+	if entry.Encrypted {
+		err = keyring.WriteFile(fsys, entry.RelPath, content, perm)
+	} else {
+		err = fsys.WriteFile(entry.RelPath, content, perm)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to append to memo: %w", err)
+	}
+
+	return nil
+}
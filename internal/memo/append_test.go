@@ -0,0 +1,30 @@
+package memo_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/sushichan044/memo-cli/internal/config"
+	"github.com/sushichan044/memo-cli/internal/crypt"
+	"github.com/sushichan044/memo-cli/internal/memo"
+	"github.com/sushichan044/memo-cli/internal/memofs"
+)
+
+func TestAppend(t *testing.T) {
+	fsys := memofs.NewMem("/memo")
+	require.NoError(t, fsys.MkdirAll("20251031", 0o700))
+	require.NoError(t, fsys.WriteFile("20251031/14-30-45-notes.md", []byte("# Notes"), 0o600))
+
+	entries, err := memo.List(fsys)
+	require.NoError(t, err)
+	keyring := crypt.NewKeyring(config.Encryption{})
+
+	require.NoError(t, memo.Append(fsys, keyring, entries[0], []byte("first\n"), 0o600))
+	require.NoError(t, memo.Append(fsys, keyring, entries[0], []byte("second\n"), 0o600))
+
+	content, err := fsys.ReadFile("20251031/14-30-45-notes.md")
+	require.NoError(t, err)
+	assert.Equal(t, "# Notes\nfirst\nsecond\n", string(content))
+}
@@ -0,0 +1,26 @@
+package memo
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+
+	"github.com/sushichan044/memo-cli/internal/memofs"
+)
+
+// Delete removes the memo file of entry.
+// The date directory is removed as well once it no longer contains any file,
+// so that deleting the last memo of a day does not leave an empty directory behind.
+func Delete(fsys memofs.FS, entry Entry) error {
+	if err := fsys.Remove(entry.RelPath); err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return fmt.Errorf("%w: %s", ErrNotFound, entry.Path)
+		}
+		return fmt.Errorf("failed to delete memo: %w", err)
+	}
+
+	// Fails harmlessly when other memos remain in the directory.
+	_ = fsys.Remove(entry.DateDir)
+
+	return nil
+}
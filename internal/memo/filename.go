@@ -0,0 +1,76 @@
+package memo
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// maxFileNameBytes is the smallest per-component limit shared by ext4, APFS and NTFS.
+const maxFileNameBytes = 255
+
+var (
+	// ErrInvalidFileName is returned when a file name cannot be used on every supported platform.
+	ErrInvalidFileName = errors.New("invalid file name")
+	// ErrReservedFileName is returned when a file name is a reserved device name on Windows.
+	ErrReservedFileName = errors.New("reserved file name")
+	// ErrFileNameTooLong is returned when a file name exceeds maxFileNameBytes.
+	ErrFileNameTooLong = errors.New("file name too long")
+)
+
+// windowsReservedNames lists device names that Windows refuses as a file name,
+// regardless of case or extension (e.g. "con.md" is as invalid as "CON").
+//
+//nolint:gochecknoglobals // read-only lookup table
+var windowsReservedNames = map[string]struct{}{
+	"CON": {}, "PRN": {}, "AUX": {}, "NUL": {},
+	"COM0": {}, "COM1": {}, "COM2": {}, "COM3": {}, "COM4": {},
+	"COM5": {}, "COM6": {}, "COM7": {}, "COM8": {}, "COM9": {},
+	"COM¹": {}, "COM²": {}, "COM³": {},
+	"LPT0": {}, "LPT1": {}, "LPT2": {}, "LPT3": {}, "LPT4": {},
+	"LPT5": {}, "LPT6": {}, "LPT7": {}, "LPT8": {}, "LPT9": {},
+	"LPT¹": {}, "LPT²": {}, "LPT³": {},
+}
+
+// ValidateFileName reports whether name can be used as a single path component
+// on Linux, macOS and Windows alike.
+// It does not consult the host OS, so Windows-only rules are enforced everywhere.
+func ValidateFileName(name string) error {
+	if name == "" || name == "." || name == ".." {
+		return fmt.Errorf("%w: %q", ErrInvalidFileName, name)
+	}
+
+	if len(name) > maxFileNameBytes {
+		return fmt.Errorf(
+			"%w: %d bytes exceeds the %d byte limit (use a shorter memo name)",
+			ErrFileNameTooLong, len(name), maxFileNameBytes,
+		)
+	}
+
+	for _, r := range name {
+		if r < 0x20 || r == 0x7f {
+			return fmt.Errorf("%w: %q contains a control character", ErrInvalidFileName, name)
+		}
+		if strings.ContainsRune(`<>:"/\|?*`, r) {
+			return fmt.Errorf("%w: %q contains %q, which is not allowed on Windows", ErrInvalidFileName, name, r)
+		}
+	}
+
+	if strings.HasSuffix(name, ".") || strings.HasSuffix(name, " ") {
+		return fmt.Errorf("%w: %q ends with a dot or space, which Windows strips silently", ErrInvalidFileName, name)
+	}
+
+	stem, _, _ := strings.Cut(name, ".")
+	if _, reserved := windowsReservedNames[strings.ToUpper(strings.TrimRight(stem, " "))]; reserved {
+		return fmt.Errorf("%w: %q is a reserved device name on Windows", ErrReservedFileName, name)
+	}
+
+	return nil
+}
+
+// sanitizeFileName rewrites the parts of name that can be fixed without changing its meaning.
+// Trailing dots and spaces are dropped because Windows would strip them anyway,
+// which would make the same memo resolve to different names on different machines.
+func sanitizeFileName(name string) string {
+	return strings.TrimRight(name, ". ")
+}
@@ -0,0 +1,95 @@
+package memo_test
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/sushichan044/memo-cli/internal/config"
+	"github.com/sushichan044/memo-cli/internal/memo"
+)
+
+func TestValidateFileName(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		wantErr error
+	}{
+		{"plain memo", "14-30-45-notes.md", nil},
+		{"timestamp only", "14-30-45.md", nil},
+		{"unicode name", "14-30-45-議事録.md", nil},
+		{"reserved name contained in longer name", "14-30-45-con.md", nil},
+		{"exactly 255 bytes", strings.Repeat("a", 252) + ".md", nil},
+		{"empty", "", memo.ErrInvalidFileName},
+		{"dot", ".", memo.ErrInvalidFileName},
+		{"dot dot", "..", memo.ErrInvalidFileName},
+		{"reserved CON", "CON", memo.ErrReservedFileName},
+		{"reserved nul lowercase", "nul", memo.ErrReservedFileName},
+		{"reserved with extension", "con.md", memo.ErrReservedFileName},
+		{"reserved with double extension", "aux.md.bak", memo.ErrReservedFileName},
+		{"reserved COM port", "COM1.txt", memo.ErrReservedFileName},
+		{"reserved LPT superscript", "LPT¹.md", memo.ErrReservedFileName},
+		{"reserved with trailing space before dot", "PRN .md", memo.ErrReservedFileName},
+		{"trailing dot", "notes.", memo.ErrInvalidFileName},
+		{"trailing space", "notes ", memo.ErrInvalidFileName},
+		{"colon", "14:30:45.md", memo.ErrInvalidFileName},
+		{"question mark", "why?.md", memo.ErrInvalidFileName},
+		{"backslash", `a\b.md`, memo.ErrInvalidFileName},
+		{"control character", "a\tb.md", memo.ErrInvalidFileName},
+		{"256 bytes", strings.Repeat("a", 253) + ".md", memo.ErrFileNameTooLong},
+		{"multibyte over limit", strings.Repeat("あ", 85) + ".md", memo.ErrFileNameTooLong},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := memo.ValidateFileName(tt.input)
+			if tt.wantErr == nil {
+				assert.NoError(t, err)
+				return
+			}
+			assert.ErrorIs(t, err, tt.wantErr)
+		})
+	}
+}
+
+func TestCreate_FileNameSafety(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		wantErr error
+		suffix  string
+	}{
+		{"reserved name is escaped", "CON", nil, "-CON_.md"},
+		{"trailing dots are rewritten", "notes...", nil, "-notes.md"},
+		{"trailing spaces are rewritten", "notes   ", nil, "-notes.md"},
+		{"too long once prefix is added", strings.Repeat("a", 250), memo.ErrFileNameTooLong, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			creator := memo.New(&config.Config{BaseDir: t.TempDir()})
+
+			path, err := creator.Create(tt.input, "")
+			if tt.wantErr != nil {
+				require.ErrorIs(t, err, tt.wantErr)
+				assert.Empty(t, path)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.True(t, strings.HasSuffix(path, tt.suffix), "path %q should end with %q", path, tt.suffix)
+		})
+	}
+}
+
+func TestCreate_TooLongNameDoesNotCreateDirectories(t *testing.T) {
+	baseDir := filepath.Join(t.TempDir(), "memo")
+	creator := memo.New(&config.Config{BaseDir: baseDir})
+
+	_, err := creator.Create(strings.Repeat("a", 300), "")
+	require.ErrorIs(t, err, memo.ErrFileNameTooLong)
+	assert.NoDirExists(t, baseDir)
+}
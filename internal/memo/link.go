@@ -0,0 +1,86 @@
+package memo
+
+import (
+	"path"
+	"strings"
+	"time"
+
+	"github.com/spf13/pathologize"
+
+	"github.com/sushichan044/memo-cli/internal/crypt"
+)
+
+// LinkResolver maps the targets of [[wiki-links]] to memos.
+type LinkResolver struct {
+	entries []Entry
+}
+
+// NewLinkResolver creates a new LinkResolver over entries, which must be sorted newest first as returned by List.
+func NewLinkResolver(entries []Entry) *LinkResolver {
+	return &LinkResolver{entries: entries}
+}
+
+// Resolve returns the memo a link target refers to. Since file names carry a timestamp prefix,
+// a target is matched case-insensitively against, in order:
+//   - the path relative to the memo root, with or without extension (e.g. "20251031/14-30-45-notes");
+//   - a date (e.g. "2025-10-31" or "20251031"), which links to the daily note of that day;
+//   - the file name without extension (e.g. "14-30-45-notes");
+//   - the memo name, written as given to `memo new` (e.g. "release notes" links to "14-30-45-release-notes.md").
+//
+// When several memos match at the same step, the newest one wins.
+func (r *LinkResolver) Resolve(target string) (Entry, bool) {
+	target = strings.TrimSpace(target)
+	if target == "" {
+		return Entry{}, false
+	}
+
+	if strings.Contains(target, "/") {
+		for _, entry := range r.entries {
+			stemPath := entry.DateDir + "/" + entry.Stem()
+			if strings.EqualFold(entry.RelPath, target) || strings.EqualFold(stemPath, target) ||
+				strings.EqualFold(stemPath, trimExt(target)) {
+				return entry, true
+			}
+		}
+		return Entry{}, false
+	}
+
+	if date, ok := parseLinkDate(target); ok {
+		for _, entry := range r.entries {
+			if entry.Daily && entry.DateDir == date {
+				return entry, true
+			}
+		}
+	}
+
+	for _, entry := range r.entries {
+		if strings.EqualFold(entry.Stem(), target) || strings.EqualFold(entry.Stem(), trimExt(target)) {
+			return entry, true
+		}
+	}
+
+	name := normalizeFileName(pathologize.Clean(target))
+	for _, entry := range r.entries {
+		if entry.Name != "" && !entry.Daily && strings.EqualFold(entry.Name, name) {
+			return entry, true
+		}
+	}
+
+	return Entry{}, false
+}
+
+// trimExt removes the extension, and the encryption suffix, from a link target.
+func trimExt(target string) string {
+	target = strings.TrimSuffix(target, crypt.Suffix)
+	return strings.TrimSuffix(target, path.Ext(target))
+}
+
+// parseLinkDate returns the date directory named by a YYYY-MM-DD or YYYYMMDD target.
+func parseLinkDate(target string) (string, bool) {
+	for _, layout := range []string{time.DateOnly, dateDirLayout} {
+		if date, err := time.ParseInLocation(layout, target, time.Local); err == nil {
+			return DateDir(date), true
+		}
+	}
+	return "", false
+}
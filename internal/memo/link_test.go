@@ -0,0 +1,48 @@
+package memo_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/sushichan044/memo-cli/internal/memo"
+	"github.com/sushichan044/memo-cli/internal/memofs"
+)
+
+func TestLinkResolver(t *testing.T) {
+	fsys := memofs.NewMem(memRoot)
+	writeMemo(t, fsys, "20251030/09-00-00-release-notes.md", "")
+	writeMemo(t, fsys, "20251031/14-30-45-release-notes.md", "")
+	writeMemo(t, fsys, "20251031/15-00-00-secret.md.age", "")
+	writeMemo(t, fsys, "20251031/daily.md", "")
+
+	entries, err := memo.List(fsys)
+	require.NoError(t, err)
+	resolver := memo.NewLinkResolver(entries)
+
+	tests := []struct {
+		target string
+		want   string
+	}{
+		{"release notes", "20251031/14-30-45-release-notes.md"},
+		{"Release-Notes", "20251031/14-30-45-release-notes.md"},
+		{"09-00-00-release-notes", "20251030/09-00-00-release-notes.md"},
+		{"09-00-00-release-notes.md", "20251030/09-00-00-release-notes.md"},
+		{"20251030/09-00-00-release-notes", "20251030/09-00-00-release-notes.md"},
+		{"20251031/15-00-00-secret.md.age", "20251031/15-00-00-secret.md.age"},
+		{"secret", "20251031/15-00-00-secret.md.age"},
+		{"2025-10-31", "20251031/daily.md"},
+		{"20251031/daily", "20251031/daily.md"},
+		{"missing", ""},
+		{"2025-10-30", ""},
+		{"20251029/09-00-00-release-notes", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.target, func(t *testing.T) {
+			entry, ok := resolver.Resolve(tt.target)
+			assert.Equal(t, tt.want != "", ok)
+			assert.Equal(t, tt.want, entry.RelPath)
+		})
+	}
+}
@@ -0,0 +1,247 @@
+package memo
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/sushichan044/memo-cli/internal/crypt"
+	"github.com/sushichan044/memo-cli/internal/memofs"
+	"github.com/sushichan044/memo-cli/schema"
+)
+
+const (
+	dateDirLayout   = "20060102"
+	timestampLayout = "15-04-05"
+
+	// LatestRef resolves to the most recently created memo.
+	LatestRef = "@latest"
+
+	// DailyName is the file name stem of the daily note of a date directory (e.g. "20251031/daily.md").
+	DailyName = "daily"
+)
+
+// ErrNotFound is returned when a memo reference does not match any memo.
+var ErrNotFound = errors.New("memo not found")
+
+// Entry describes a memo file stored under the base directory.
+type Entry struct {
+	// Path is the absolute path to the memo file.
+	Path string
+	// RelPath is the slash-separated path relative to the base directory (e.g. "20251031/14-30-45-notes.md").
+	RelPath string
+	// DateDir is the YYYYMMDD directory the memo lives in.
+	DateDir string
+	// Name is the user-provided part of the file name, or empty for timestamp-only memos.
+	Name string
+	// Ext is the memo extension without the leading dot and without the encryption suffix.
+	Ext string
+	// Encrypted reports whether the memo is encrypted with age.
+	Encrypted bool
+	// CreatedAt is derived from the date directory and the HH-MM-SS prefix, in local time.
+	// Daily notes have no time of day and are created at midnight.
+	CreatedAt time.Time
+	// Daily reports whether the memo is the daily note of its date directory.
+	Daily bool
+}
+
+// List returns every memo in fsys, newest first.
+// Files that do not follow the YYYYMMDD/HH-MM-SS[-name].ext layout are ignored.
+// A missing memo root yields an empty list.
+func List(fsys memofs.FS) ([]Entry, error) {
+	dirs, err := fsys.ReadDir(".")
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to read memo directory: %w", err)
+	}
+
+	var entries []Entry
+	for _, dir := range dirs {
+		if !dir.IsDir() {
+			continue
+		}
+		if _, parseErr := time.ParseInLocation(dateDirLayout, dir.Name(), time.Local); parseErr != nil {
+			continue
+		}
+
+		files, readErr := fsys.ReadDir(dir.Name())
+		if readErr != nil {
+			return nil, fmt.Errorf("failed to read date directory: %w", readErr)
+		}
+
+		for _, file := range files {
+			if !file.Type().IsRegular() {
+				continue
+			}
+			if entry, ok := parseEntry(fsys.Root(), dir.Name(), file.Name()); ok {
+				entries = append(entries, entry)
+			}
+		}
+	}
+
+	slices.SortStableFunc(entries, func(a, b Entry) int {
+		if c := b.CreatedAt.Compare(a.CreatedAt); c != 0 {
+			return c
+		}
+		return strings.Compare(b.RelPath, a.RelPath)
+	})
+
+	return entries, nil
+}
+
+// Resolve finds the memo referred to by ref. A reference is one of:
+//   - "@latest", the most recently created memo;
+//   - a path to a memo file, absolute or relative to the current directory or to the memo root;
+//   - a memo name as given to `memo new`, or its full file name without extension.
+//     When several memos share the name, the newest one wins.
+func Resolve(fsys memofs.FS, ref string) (Entry, error) {
+	entries, err := List(fsys)
+	if err != nil {
+		return Entry{}, err
+	}
+
+	if ref == LatestRef {
+		if len(entries) == 0 {
+			return Entry{}, fmt.Errorf("%w: no memos yet", ErrNotFound)
+		}
+		return entries[0], nil
+	}
+
+	candidates := []string{filepath.Join(fsys.Root(), ref)}
+	if abs, absErr := filepath.Abs(ref); absErr == nil {
+		candidates = append(candidates, abs)
+	}
+	for _, entry := range entries {
+		if slices.Contains(candidates, entry.Path) {
+			return entry, nil
+		}
+	}
+
+	for _, entry := range entries {
+		if entry.Name == ref || entry.Stem() == ref {
+			return entry, nil
+		}
+	}
+
+	return Entry{}, fmt.Errorf("%w: %q", ErrNotFound, ref)
+}
+
+// NewEntry builds the Entry for the memo file at path, which must live in a date directory under the memo root.
+func NewEntry(fsys memofs.FS, path string) (Entry, error) {
+	baseDir := fsys.Root()
+	rel, err := filepath.Rel(baseDir, path)
+	if err != nil {
+		return Entry{}, fmt.Errorf("%w: %s", ErrNotFound, path)
+	}
+
+	dateDir, filename, ok := strings.Cut(filepath.ToSlash(rel), "/")
+	if !ok || strings.Contains(filename, "/") {
+		return Entry{}, fmt.Errorf("%w: %s is not a memo under %s", ErrNotFound, path, baseDir)
+	}
+
+	entry, ok := parseEntry(baseDir, dateDir, filename)
+	if !ok {
+		return Entry{}, fmt.Errorf("%w: %s is not a memo under %s", ErrNotFound, path, baseDir)
+	}
+
+	return entry, nil
+}
+
+// DateDir returns the name of the date directory holding the memos of t's day (e.g. "20251031").
+func DateDir(t time.Time) string {
+	return t.Format(dateDirLayout)
+}
+
+// Schema converts e into its stable JSON representation.
+func (e Entry) Schema() schema.Memo {
+	return schema.Memo{
+		Path:      e.Path,
+		RelPath:   e.RelPath,
+		DateDir:   e.DateDir,
+		Name:      e.Name,
+		Ext:       e.Ext,
+		Encrypted: e.Encrypted,
+		CreatedAt: e.CreatedAt,
+	}
+}
+
+// Stem returns the file name without extension and encryption suffix (e.g. "14-30-45-notes").
+func (e Entry) Stem() string {
+	if e.Daily {
+		return DailyName
+	}
+	if e.Name == "" {
+		return e.CreatedAt.Format(timestampLayout)
+	}
+	return e.CreatedAt.Format(timestampLayout) + "-" + e.Name
+}
+
+// Title returns the display name of the memo: its name, or the file name stem for unnamed memos.
+func (e Entry) Title() string {
+	if e.Name != "" {
+		return e.Name
+	}
+	return e.Stem()
+}
+
+// parseEntry builds an Entry from a file name inside a date directory.
+func parseEntry(root, dateDir, filename string) (Entry, bool) {
+	rest := filename
+	encrypted := crypt.IsEncrypted(rest)
+	rest = strings.TrimSuffix(rest, crypt.Suffix)
+
+	dot := strings.LastIndexByte(rest, '.')
+	if dot <= 0 {
+		return Entry{}, false
+	}
+	stem, ext := rest[:dot], rest[dot+1:]
+
+	if stem == DailyName {
+		date, err := time.ParseInLocation(dateDirLayout, dateDir, time.Local)
+		if err != nil {
+			return Entry{}, false
+		}
+		return Entry{
+			Path:      filepath.Join(root, dateDir, filename),
+			RelPath:   dateDir + "/" + filename,
+			DateDir:   dateDir,
+			Name:      DailyName,
+			Ext:       ext,
+			Encrypted: encrypted,
+			CreatedAt: date,
+			Daily:     true,
+		}, true
+	}
+
+	if len(stem) < len(timestampLayout) {
+		return Entry{}, false
+	}
+	createdAt, err := time.ParseInLocation(dateDirLayout+timestampLayout, dateDir+stem[:len(timestampLayout)], time.Local)
+	if err != nil {
+		return Entry{}, false
+	}
+
+	name := stem[len(timestampLayout):]
+	if name != "" {
+		if name[0] != '-' {
+			return Entry{}, false
+		}
+		name = name[1:]
+	}
+
+	return Entry{
+		Path:      filepath.Join(root, dateDir, filename),
+		RelPath:   dateDir + "/" + filename,
+		DateDir:   dateDir,
+		Name:      name,
+		Ext:       ext,
+		Encrypted: encrypted,
+		CreatedAt: createdAt,
+	}, true
+}
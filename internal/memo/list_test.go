@@ -0,0 +1,160 @@
+package memo_test
+
+import (
+	"io/fs"
+	"path"
+	"path/filepath"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/sushichan044/memo-cli/internal/memo"
+	"github.com/sushichan044/memo-cli/internal/memofs"
+)
+
+const memRoot = "/memo"
+
+// writeMemo creates a memo file at relPath in fsys with the given content and returns its display path.
+func writeMemo(t *testing.T, fsys memofs.FS, relPath, content string) string {
+	t.Helper()
+
+	require.NoError(t, fsys.MkdirAll(path.Dir(relPath), 0o700))
+	require.NoError(t, fsys.WriteFile(relPath, []byte(content), 0o600))
+
+	return filepath.Join(fsys.Root(), filepath.FromSlash(relPath))
+}
+
+func TestList(t *testing.T) {
+	fsys := memofs.NewMem(memRoot)
+	writeMemo(t, fsys, "20251030/09-00-00-old.md", "")
+	writeMemo(t, fsys, "20251031/14-30-45.md", "")
+	writeMemo(t, fsys, "20251031/15-00-00-secret.md.age", "")
+	writeMemo(t, fsys, "20251031/notes.md", "")
+	writeMemo(t, fsys, "drafts/15-00-00-draft.md", "")
+	writeMemo(t, fsys, "20251031/16-00-00", "")
+
+	entries, err := memo.List(fsys)
+	require.NoError(t, err)
+	require.Len(t, entries, 3)
+
+	assert.Equal(t, "20251031/15-00-00-secret.md.age", entries[0].RelPath)
+	assert.Equal(t, "secret", entries[0].Name)
+	assert.Equal(t, "md", entries[0].Ext)
+	assert.True(t, entries[0].Encrypted)
+
+	assert.Equal(t, "20251031/14-30-45.md", entries[1].RelPath)
+	assert.Empty(t, entries[1].Name)
+	assert.Equal(t, "14-30-45", entries[1].Stem())
+	assert.Equal(t, "14-30-45", entries[1].Title())
+	assert.Equal(t, time.Date(2025, 10, 31, 14, 30, 45, 0, time.Local), entries[1].CreatedAt)
+
+	assert.Equal(t, "old", entries[2].Name)
+	assert.Equal(t, "old", entries[2].Title())
+	assert.Equal(t, "20251030", entries[2].DateDir)
+}
+
+func TestList_DailyNote(t *testing.T) {
+	fsys := memofs.NewMem(memRoot)
+	writeMemo(t, fsys, "20251031/daily.md", "")
+	writeMemo(t, fsys, "20251031/09-00-00-standup.md", "")
+
+	entries, err := memo.List(fsys)
+	require.NoError(t, err)
+	require.Len(t, entries, 2)
+
+	daily := entries[1]
+	assert.True(t, daily.Daily)
+	assert.Equal(t, memo.DailyName, daily.Name)
+	assert.Equal(t, "daily", daily.Stem())
+	assert.Equal(t, time.Date(2025, 10, 31, 0, 0, 0, 0, time.Local), daily.CreatedAt)
+	assert.False(t, entries[0].Daily)
+}
+
+func TestList_MissingBaseDir(t *testing.T) {
+	entries, err := memo.List(memofs.NewOS(filepath.Join(t.TempDir(), "missing")))
+	require.NoError(t, err)
+	assert.Empty(t, entries)
+}
+
+func TestResolve(t *testing.T) {
+	fsys := memofs.NewMem(memRoot)
+	older := writeMemo(t, fsys, "20251030/09-00-00-notes.md", "")
+	newer := writeMemo(t, fsys, "20251031/10-00-00-notes.md", "")
+	latest := writeMemo(t, fsys, "20251031/11-00-00.txt", "")
+
+	tests := []struct {
+		name string
+		ref  string
+		want string
+	}{
+		{"latest", memo.LatestRef, latest},
+		{"name picks newest", "notes", newer},
+		{"stem", "09-00-00-notes", older},
+		{"relative to base dir", "20251030/09-00-00-notes.md", older},
+		{"absolute path", older, older},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			entry, err := memo.Resolve(fsys, tt.ref)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, entry.Path)
+		})
+	}
+}
+
+func TestResolve_NotFound(t *testing.T) {
+	fsys := memofs.NewMem(memRoot)
+
+	_, err := memo.Resolve(fsys, memo.LatestRef)
+	require.ErrorIs(t, err, memo.ErrNotFound)
+
+	writeMemo(t, fsys, "20251031/10-00-00-notes.md", "")
+	_, err = memo.Resolve(fsys, "missing")
+	require.ErrorIs(t, err, memo.ErrNotFound)
+}
+
+func TestSearch(t *testing.T) {
+	fsys := memofs.NewMem(memRoot)
+	writeMemo(t, fsys, "20251031/10-00-00-a.md", "first\nTODO: fix\nlast")
+	writeMemo(t, fsys, "20251031/11-00-00-b.md", "nothing here")
+
+	entries, err := memo.List(fsys)
+	require.NoError(t, err)
+
+	matches, err := memo.Search(entries, regexp.MustCompile("TODO"), func(e memo.Entry) ([]byte, error) {
+		return fsys.ReadFile(e.RelPath)
+	})
+	require.NoError(t, err)
+	require.Len(t, matches, 1)
+	assert.Equal(t, "a", matches[0].Entry.Name)
+	assert.Equal(t, 2, matches[0].Line)
+	assert.Equal(t, "TODO: fix", matches[0].Text)
+}
+
+func TestDelete(t *testing.T) {
+	fsys := memofs.NewMem(memRoot)
+	keep := "20251031/10-00-00-keep.md"
+	writeMemo(t, fsys, keep, "")
+	writeMemo(t, fsys, "20251031/11-00-00-drop.md", "")
+	writeMemo(t, fsys, "20251030/09-00-00-last.md", "")
+
+	drop, err := memo.Resolve(fsys, "drop")
+	require.NoError(t, err)
+	require.NoError(t, memo.Delete(fsys, drop))
+	_, err = fsys.Stat(drop.RelPath)
+	require.ErrorIs(t, err, fs.ErrNotExist)
+	_, err = fsys.Stat(keep)
+	require.NoError(t, err, "other memos must be kept")
+
+	last, err := memo.Resolve(fsys, "last")
+	require.NoError(t, err)
+	require.NoError(t, memo.Delete(fsys, last))
+	_, err = fsys.Stat("20251030")
+	require.ErrorIs(t, err, fs.ErrNotExist, "empty date directory should be removed")
+
+	require.ErrorIs(t, memo.Delete(fsys, last), memo.ErrNotFound)
+}
@@ -1,65 +1,182 @@
 package memo
 
 import (
+	"errors"
 	"fmt"
-	"os"
+	"io"
+	"io/fs"
+	"path"
 	"path/filepath"
 	"strings"
 	"time"
 
+	"filippo.io/age"
 	"github.com/spf13/pathologize"
 
 	"github.com/sushichan044/memo-cli/internal/config"
+	"github.com/sushichan044/memo-cli/internal/crypt"
 	"github.com/sushichan044/memo-cli/internal/gitignore"
+	"github.com/sushichan044/memo-cli/internal/hook"
+	"github.com/sushichan044/memo-cli/internal/memofs"
+)
+
+var (
+	// ErrExists is returned when a memo with the same file name already exists.
+	ErrExists = errors.New("memo already exists")
+	// ErrInvalidExtension is returned when a memo extension contains unsupported characters.
+	ErrInvalidExtension = errors.New("invalid extension")
 )
 
 // Creator handles memo creation logic.
 type Creator struct {
 	config *config.Config
+	fs     memofs.FS
+	hooks  *hook.Runner
 }
 
-// New creates a new Creator instance.
+// New creates a new Creator instance storing memos on disk under cfg.BaseDir.
 func New(cfg *config.Config) *Creator {
-	return &Creator{config: cfg}
+	return NewWithFS(cfg, memofs.NewOS(cfg.BaseDir))
+}
+
+// NewWithFS creates a new Creator instance storing memos in fsys.
+// cfg.BaseDir is not used to access files; fsys.Root() is reported instead.
+func NewWithFS(cfg *config.Config, fsys memofs.FS) *Creator {
+	return &Creator{config: cfg, fs: fsys}
+}
+
+// WithHooks makes c run the pre-create and post-create hooks of hooks around every memo it creates.
+func (c *Creator) WithHooks(hooks *hook.Runner) *Creator {
+	c.hooks = hooks
+	return c
 }
 
 // Create creates a new memo file with the given name and extension.
 // If name is empty, uses timestamp (HH-MM-SS) as filename.
 // Returns the absolute path to the created file.
 func (c *Creator) Create(name, ext string) (string, error) {
-	// Ensure base directory exists
-	if err := os.MkdirAll(c.config.BaseDir, 0o750); err != nil {
-		return "", fmt.Errorf("failed to create base directory: %w", err)
+	return c.CreateWith(name, ext, CreateOptions{})
+}
+
+// CreateEncrypted creates a new memo like Create, but encrypted to recipients with age.
+// The file name gets an additional ".age" suffix (e.g. HH-MM-SS-name.md.age).
+func (c *Creator) CreateEncrypted(name, ext string, recipients ...age.Recipient) (string, error) {
+	if len(recipients) == 0 {
+		return "", crypt.ErrNoRecipients
 	}
 
+	return c.CreateWith(name, ext, CreateOptions{Recipients: recipients})
+}
+
+// CreateOptions customizes CreateWith.
+type CreateOptions struct {
+	// Content is written to the new memo. Nil creates an empty memo.
+	Content []byte
+	// Recipients encrypts the memo with age when non-empty.
+	Recipients []age.Recipient
+}
+
+// CreateWith creates a new memo like Create, with the initial content and encryption given by opts.
+// Returns an error wrapping ErrExists if a memo with the same file name already exists.
+func (c *Creator) CreateWith(name, ext string, opts CreateOptions) (string, error) {
+	return c.create(name, ext, opts.Content, opts.Recipients)
+}
+
+func (c *Creator) create(name, ext string, content []byte, recipients []age.Recipient) (string, error) {
 	normalizedExt, err := normalizeExtension(ext)
 	if err != nil {
 		return "", err
 	}
 
+	suffix := ""
+	if len(recipients) > 0 {
+		suffix = crypt.Suffix
+	}
+
 	// Generate filename
-	filename := normalizeFileName(c.generateFilename(name))
+	filename := sanitizeFileName(normalizeFileName(c.generateFilename(name))) + "." + normalizedExt + suffix
+	if validateErr := ValidateFileName(filename); validateErr != nil {
+		return "", validateErr
+	}
+
+	// Ensure base directory exists
+	if mkdirErr := c.fs.MkdirAll(".", c.config.DirPerm()); mkdirErr != nil {
+		return "", fmt.Errorf("failed to create base directory: %w", mkdirErr)
+	}
 
 	// Create date directory (YYYYMMDD)
 	now := time.Now()
-	dateDir := now.Format("20060102")
-	fullDir := filepath.Join(c.config.BaseDir, dateDir)
+	dateDir := now.Format(dateDirLayout)
 
-	if mkdirErr := os.MkdirAll(fullDir, 0o750); mkdirErr != nil {
+	if mkdirErr := c.fs.MkdirAll(dateDir, c.config.DirPerm()); mkdirErr != nil {
 		return "", fmt.Errorf("failed to create date directory: %w", mkdirErr)
 	}
 
-	// Create file path
-	filePath := filepath.Join(fullDir, filename+"."+normalizedExt)
+	// Create the file, refusing to overwrite a memo created within the same second
+	memoName := path.Join(dateDir, filename)
+	_, err = CreateFile(c.fs, memoName, c.config.FilePerm(), c.hooks, func(w io.Writer) error {
+		return writeContent(w, content, recipients)
+	})
+	if errors.Is(err, fs.ErrExist) {
+		return "", fmt.Errorf("%w: %s", ErrExists, c.absPath(memoName))
+	}
+	if err != nil {
+		return "", err
+	}
+
+	return c.absPath(memoName), nil
+}
 
-	// Create empty file
-	file, err := os.Create(filePath)
+// CreateFile creates the memo name (e.g. "20251031/14-30-45-notes.md") in fsys, whose date directory must exist,
+// and fills it with write. The pre-create hook of hooks runs before the file is created and may abort it;
+// the post-create hook runs once it is written. A memo that cannot be written is removed again.
+// It returns an error wrapping fs.ErrExist if name already exists.
+func CreateFile(
+	fsys memofs.FS, name string, perm fs.FileMode, hooks *hook.Runner, write func(io.Writer) error,
+) (Entry, error) {
+	entry, err := NewEntry(fsys, filepath.Join(fsys.Root(), filepath.FromSlash(name)))
 	if err != nil {
-		return "", fmt.Errorf("failed to create memo file: %w", err)
+		return Entry{}, err
+	}
+	if hookErr := hooks.Run(hook.PreCreate, entry.Schema()); hookErr != nil {
+		return Entry{}, hookErr
+	}
+
+	file, err := fsys.Create(name, perm)
+	if err != nil {
+		return Entry{}, fmt.Errorf("failed to create memo file: %w", err)
 	}
 	defer file.Close()
 
-	return filePath, nil
+	if writeErr := write(file); writeErr != nil {
+		_ = fsys.Remove(name)
+		return Entry{}, writeErr
+	}
+	if closeErr := file.Close(); closeErr != nil {
+		return Entry{}, fmt.Errorf("failed to write memo file: %w", closeErr)
+	}
+
+	_ = hooks.Run(hook.PostCreate, entry.Schema())
+	return entry, nil
+}
+
+// absPath converts a name in the memo FS into the path reported to users.
+func (c *Creator) absPath(name string) string {
+	return filepath.Join(c.fs.Root(), filepath.FromSlash(name))
+}
+
+// writeContent writes content to a newly created memo, encrypting it when recipients are given.
+func writeContent(w io.Writer, content []byte, recipients []age.Recipient) error {
+	if len(recipients) > 0 {
+		// Even an empty memo gets an age header, so it can be decrypted and edited later.
+		return crypt.Encrypt(w, content, recipients...)
+	}
+
+	if _, err := w.Write(content); err != nil {
+		return fmt.Errorf("failed to write memo file: %w", err)
+	}
+
+	return nil
 }
 
 // generateFilename creates a normalized filename from user input.
@@ -69,7 +186,11 @@ func (c *Creator) Create(name, ext string) (string, error) {
 // - Replacing slashes with dashes.
 // - Replacing spaces with dashes.
 func (c *Creator) generateFilename(name string) string {
-	timestamp := time.Now().Format("15-04-05")
+	return fileStem(time.Now().Format(timestampLayout), name)
+}
+
+// fileStem joins a HH-MM-SS timestamp and a user-provided name into a file name without extension.
+func fileStem(timestamp, name string) string {
 	if name == "" {
 		// Use timestamp as default
 		return timestamp
@@ -78,6 +199,22 @@ func (c *Creator) generateFilename(name string) string {
 	return timestamp + "-" + pathologize.Clean(name)
 }
 
+// RelPath returns the path, relative to the memo root, of a memo named name created at t
+// (e.g. "20251031/14-30-45-notes.md"). The name is normalized as by Create.
+func RelPath(t time.Time, name, ext string) (string, error) {
+	normalizedExt, err := normalizeExtension(ext)
+	if err != nil {
+		return "", err
+	}
+
+	filename := sanitizeFileName(normalizeFileName(fileStem(t.Format(timestampLayout), name))) + "." + normalizedExt
+	if validateErr := ValidateFileName(filename); validateErr != nil {
+		return "", validateErr
+	}
+
+	return DateDir(t) + "/" + filename, nil
+}
+
 // CheckGitignore checks if the memo base directory is ignored by git.
 // Returns a warning message if not ignored, empty string otherwise.
 // Silently returns empty string if gitignore checking fails (e.g., not a git repository).
@@ -132,7 +269,7 @@ func normalizeExtension(ext string) (string, error) {
 		if (r >= 'a' && r <= 'z') || (r >= '0' && r <= '9') || r == '-' || r == '_' {
 			continue
 		}
-		return "", fmt.Errorf("invalid extension: %q", ext)
+		return "", fmt.Errorf("%w: %q", ErrInvalidExtension, ext)
 	}
 
 	return normalized, nil
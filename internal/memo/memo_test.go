@@ -1,16 +1,24 @@
 package memo_test
 
 import (
+	"io"
+	"io/fs"
 	"os"
 	"path/filepath"
+	"runtime"
 	"strings"
 	"testing"
+	"time"
 
+	"filippo.io/age"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
 	"github.com/sushichan044/memo-cli/internal/config"
+	"github.com/sushichan044/memo-cli/internal/crypt"
+	"github.com/sushichan044/memo-cli/internal/hook"
 	"github.com/sushichan044/memo-cli/internal/memo"
+	"github.com/sushichan044/memo-cli/internal/memofs"
 )
 
 func TestNormalizeFileName(t *testing.T) {
@@ -256,6 +264,41 @@ func TestCreate_DirectoryCreation(t *testing.T) {
 	}
 }
 
+func TestCreate_Hooks(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("hooks are tested with sh")
+	}
+
+	tmpDir := t.TempDir()
+	log := filepath.Join(tmpDir, "hooks.log")
+	cfg := &config.Config{
+		BaseDir: filepath.Join(tmpDir, "memo"),
+		Hooks: config.Hooks{
+			PreCreate:  config.Hook{Shell: `test "$MEMO_NAME" != draft && echo "pre $MEMO_REL_PATH" >> ` + log},
+			PostCreate: config.Hook{Shell: `echo "post $(cat "$MEMO_PATH")" >> ` + log},
+		},
+	}
+	var output strings.Builder
+	creator := memo.New(cfg).WithHooks(hook.New(cfg, cfg.BaseDir, &output))
+
+	path, err := creator.CreateWith("notes", "md", memo.CreateOptions{Content: []byte("hello")})
+	require.NoError(t, err)
+	rel, err := filepath.Rel(cfg.BaseDir, path)
+	require.NoError(t, err)
+
+	data, err := os.ReadFile(log)
+	require.NoError(t, err)
+	assert.Equal(t, "pre "+filepath.ToSlash(rel)+"\npost hello\n", string(data), "post-create sees the content")
+
+	// A failing pre-create hook aborts the creation.
+	path, err = creator.Create("draft", "md")
+	require.ErrorIs(t, err, hook.ErrFailed)
+	assert.Empty(t, path)
+	entries, err := memo.List(memofs.NewOS(cfg.BaseDir))
+	require.NoError(t, err)
+	assert.Len(t, entries, 1)
+}
+
 // verifyTimestampPrefix checks that the first three parts of dash-separated name are 2-digit timestamps.
 func verifyTimestampPrefix(t *testing.T, parts []string) {
 	t.Helper()
@@ -340,6 +383,27 @@ func TestGenerateFilename(t *testing.T) {
 	}
 }
 
+func TestRelPath(t *testing.T) {
+	at := time.Date(2025, 10, 31, 14, 30, 45, 0, time.Local)
+
+	tests := []struct {
+		name, ext string
+		want      string
+	}{
+		{"Project Ideas", "md", "20251031/14-30-45-Project-Ideas.md"},
+		{"", ".TXT", "20251031/14-30-45.txt"},
+		{"notes", "", "20251031/14-30-45-notes.md"},
+	}
+	for _, tt := range tests {
+		got, err := memo.RelPath(at, tt.name, tt.ext)
+		require.NoError(t, err)
+		assert.Equal(t, tt.want, got)
+	}
+
+	_, err := memo.RelPath(at, "notes", "m d")
+	require.ErrorIs(t, err, memo.ErrInvalidExtension)
+}
+
 func TestCheckGitignore(t *testing.T) {
 	// This is a basic test - gitignore checking is difficult to test
 	// without setting up a real git repository
@@ -358,3 +422,128 @@ func TestCheckGitignore(t *testing.T) {
 	// Just check that it doesn't panic
 	t.Logf("CheckGitignore() returned: %q", warning)
 }
+
+func TestCreateEncrypted(t *testing.T) {
+	identity, err := age.GenerateX25519Identity()
+	require.NoError(t, err)
+
+	creator := memo.New(&config.Config{BaseDir: t.TempDir()})
+
+	path, err := creator.CreateEncrypted("secret", "", identity.Recipient())
+	require.NoError(t, err)
+	assert.True(t, strings.HasSuffix(path, "-secret.md.age"), "path %q should end with -secret.md.age", path)
+
+	file, err := os.Open(path)
+	require.NoError(t, err)
+	defer file.Close()
+
+	r, err := age.Decrypt(file, identity)
+	require.NoError(t, err, "new encrypted memo should be decryptable")
+	content, err := io.ReadAll(r)
+	require.NoError(t, err)
+	assert.Empty(t, content)
+}
+
+func TestCreateEncrypted_NoRecipients(t *testing.T) {
+	creator := memo.New(&config.Config{BaseDir: t.TempDir()})
+
+	_, err := creator.CreateEncrypted("secret", "")
+	require.ErrorIs(t, err, crypt.ErrNoRecipients)
+}
+
+func TestCreate_SameNameWithinSecond(t *testing.T) {
+	creator := memo.New(&config.Config{BaseDir: t.TempDir()})
+
+	// Retry until both calls land in the same second, so the test is not flaky around second boundaries.
+	for range 3 {
+		first, err := creator.Create("dup", "")
+		require.NoError(t, err)
+
+		second, err := creator.Create("dup", "")
+		if err == nil {
+			require.NotEqual(t, first, second)
+			continue
+		}
+
+		require.ErrorIs(t, err, memo.ErrExists)
+		return
+	}
+	t.Skip("could not create two memos within the same second")
+}
+
+func TestCreateWith_Content(t *testing.T) {
+	creator := memo.New(&config.Config{BaseDir: t.TempDir()})
+
+	path, err := creator.CreateWith("notes", "", memo.CreateOptions{Content: []byte("# Notes\n")})
+	require.NoError(t, err)
+
+	content, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, "# Notes\n", string(content))
+}
+
+func TestCreate_InvalidExtensionIsTyped(t *testing.T) {
+	creator := memo.New(&config.Config{BaseDir: t.TempDir()})
+
+	_, err := creator.Create("test", "md/evil")
+	require.ErrorIs(t, err, memo.ErrInvalidExtension)
+}
+
+func TestCreate_MemFS(t *testing.T) {
+	fsys := memofs.NewMem("/memo")
+	creator := memo.NewWithFS(&config.Config{BaseDir: "/unused"}, fsys)
+
+	path, err := creator.CreateWith("in-memory", "", memo.CreateOptions{Content: []byte("hermetic")})
+	require.NoError(t, err)
+	assert.True(t, strings.HasPrefix(path, filepath.FromSlash("/memo/")), "path %q should be under the FS root", path)
+
+	entry, err := memo.NewEntry(fsys, path)
+	require.NoError(t, err)
+	assert.Equal(t, "in-memory", entry.Name)
+
+	content, err := fsys.ReadFile(entry.RelPath)
+	require.NoError(t, err)
+	assert.Equal(t, "hermetic", string(content))
+
+	info, err := fsys.Stat(entry.DateDir)
+	require.NoError(t, err)
+	assert.Equal(t, config.DefaultDirMode, info.Mode().Perm())
+}
+
+func TestCreateFile(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("hooks are tested with sh")
+	}
+
+	tmpDir := t.TempDir()
+	log := filepath.Join(tmpDir, "hooks.log")
+	cfg := &config.Config{
+		Hooks: config.Hooks{
+			PreCreate:  config.Hook{Shell: `echo "pre $MEMO_REL_PATH" >> ` + log},
+			PostCreate: config.Hook{Shell: `echo "post $MEMO_REL_PATH" >> ` + log},
+		},
+	}
+	hooks := hook.New(cfg, tmpDir, io.Discard)
+	fsys := memofs.NewMem("/memo")
+	require.NoError(t, fsys.MkdirAll("20251031", 0o700))
+	write := func(w io.Writer) error {
+		_, err := w.Write([]byte("hello"))
+		return err
+	}
+
+	// A name that is not a memo fails before any hook runs.
+	_, err := memo.CreateFile(fsys, "20251031/notes", 0o600, hooks, write)
+	require.ErrorIs(t, err, memo.ErrNotFound)
+	_, err = os.Stat(log)
+	require.ErrorIs(t, err, os.ErrNotExist)
+
+	entry, err := memo.CreateFile(fsys, "20251031/14-30-45-notes.md", 0o600, hooks, write)
+	require.NoError(t, err)
+	assert.Equal(t, "notes", entry.Name)
+	data, err := os.ReadFile(log)
+	require.NoError(t, err)
+	assert.Equal(t, "pre 20251031/14-30-45-notes.md\npost 20251031/14-30-45-notes.md\n", string(data))
+
+	_, err = memo.CreateFile(fsys, "20251031/14-30-45-notes.md", 0o600, nil, write)
+	require.ErrorIs(t, err, fs.ErrExist)
+}
@@ -0,0 +1,113 @@
+package memo
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"runtime"
+)
+
+// PermissionIssue describes a path under the memo root whose mode is looser than configured.
+type PermissionIssue struct {
+	// Path is the absolute path of the file or directory.
+	Path string
+	// Name is the slash-separated path relative to the memo root.
+	Name string
+	// Mode is the current permission.
+	Mode os.FileMode
+	// Want is the permission the path would have after tightening.
+	Want os.FileMode
+	// IsDir reports whether Path is a directory.
+	IsDir bool
+}
+
+// CheckRootPermissions checks if the memo base directory grants bits beyond the configured directory mode,
+// such as read access for other users. Returns a warning message if it does, empty string otherwise.
+// Silently returns empty string on Windows, where permission bits are not meaningful,
+// and when the base directory does not exist yet.
+func (c *Creator) CheckRootPermissions() string {
+	if runtime.GOOS == "windows" {
+		return ""
+	}
+
+	info, err := c.fs.Stat(".")
+	if err != nil {
+		return ""
+	}
+
+	if info.Mode().Perm()&^c.config.DirPerm() == 0 {
+		return ""
+	}
+
+	return fmt.Sprintf(
+		"⚠️  Warning: Memo directory is readable by other users (%04o)\n"+
+			"    Run `memo doctor --fix-perms` to restrict it to %04o.",
+		info.Mode().Perm(),
+		info.Mode().Perm()&c.config.DirPerm(),
+	)
+}
+
+// CheckPermissions walks the memo base directory and reports every directory and file
+// whose permission grants bits beyond the configured modes.
+// Returns nil on Windows and when the base directory does not exist.
+func (c *Creator) CheckPermissions() ([]PermissionIssue, error) {
+	if runtime.GOOS == "windows" {
+		return nil, nil
+	}
+
+	var issues []PermissionIssue
+	err := fs.WalkDir(c.fs, ".", func(name string, d fs.DirEntry, walkErr error) error {
+		if walkErr != nil {
+			if name == "." && errors.Is(walkErr, fs.ErrNotExist) {
+				return fs.SkipAll
+			}
+			return walkErr
+		}
+
+		if d.Type()&fs.ModeSymlink != 0 {
+			// Never follow or chmod through symlinks.
+			return nil
+		}
+
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+
+		want := c.config.FilePerm()
+		if d.IsDir() {
+			want = c.config.DirPerm()
+		}
+
+		mode := info.Mode().Perm()
+		if mode&^want != 0 {
+			issues = append(issues, PermissionIssue{
+				Path:  c.absPath(name),
+				Name:  name,
+				Mode:  mode,
+				Want:  mode & want,
+				IsDir: d.IsDir(),
+			})
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to check permissions: %w", err)
+	}
+
+	return issues, nil
+}
+
+// FixPermissions tightens each issue to its Want mode.
+// Modes are only ever narrowed, so a memo that is already stricter than configured stays that way.
+func (c *Creator) FixPermissions(issues []PermissionIssue) error {
+	for _, issue := range issues {
+		if err := c.fs.Chmod(issue.Name, issue.Want); err != nil {
+			return fmt.Errorf("failed to fix permissions of %s: %w", issue.Path, err)
+		}
+	}
+
+	return nil
+}
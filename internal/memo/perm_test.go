@@ -0,0 +1,136 @@
+package memo_test
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/sushichan044/memo-cli/internal/config"
+	"github.com/sushichan044/memo-cli/internal/memo"
+)
+
+func skipOnWindows(t *testing.T) {
+	t.Helper()
+
+	if runtime.GOOS == "windows" {
+		t.Skip("permission bits are not meaningful on Windows")
+	}
+}
+
+func requireMode(t *testing.T, path string, want os.FileMode) {
+	t.Helper()
+
+	info, err := os.Stat(path)
+	require.NoError(t, err)
+	assert.Equal(t, want, info.Mode().Perm(), "mode of %s", path)
+}
+
+func TestCreate_DefaultPermissions(t *testing.T) {
+	skipOnWindows(t)
+
+	baseDir := filepath.Join(t.TempDir(), "memo")
+	creator := memo.New(&config.Config{BaseDir: baseDir})
+
+	path, err := creator.Create("secret", "")
+	require.NoError(t, err)
+
+	requireMode(t, path, config.DefaultFileMode)
+	requireMode(t, filepath.Dir(path), config.DefaultDirMode)
+	requireMode(t, baseDir, config.DefaultDirMode)
+}
+
+func TestCreate_ConfiguredPermissions(t *testing.T) {
+	skipOnWindows(t)
+
+	baseDir := filepath.Join(t.TempDir(), "memo")
+	creator := memo.New(&config.Config{BaseDir: baseDir, FileMode: 0o640, DirMode: 0o750})
+
+	path, err := creator.Create("shared", "")
+	require.NoError(t, err)
+
+	requireMode(t, path, 0o640)
+	requireMode(t, filepath.Dir(path), 0o750)
+}
+
+func TestCheckAndFixPermissions(t *testing.T) {
+	skipOnWindows(t)
+
+	baseDir := filepath.Join(t.TempDir(), "memo")
+	creator := memo.New(&config.Config{BaseDir: baseDir})
+
+	path, err := creator.Create("loose", "")
+	require.NoError(t, err)
+
+	strict, err := creator.Create("strict", "")
+	require.NoError(t, err)
+
+	require.NoError(t, os.Chmod(path, 0o644))
+	require.NoError(t, os.Chmod(strict, 0o400))
+	require.NoError(t, os.Chmod(filepath.Dir(path), 0o755))
+
+	issues, err := creator.CheckPermissions()
+	require.NoError(t, err)
+	require.Len(t, issues, 2)
+
+	byPath := map[string]memo.PermissionIssue{}
+	for _, issue := range issues {
+		byPath[issue.Path] = issue
+	}
+	assert.Equal(t, os.FileMode(0o600), byPath[path].Want)
+	assert.Equal(t, os.FileMode(0o700), byPath[filepath.Dir(path)].Want)
+	assert.True(t, byPath[filepath.Dir(path)].IsDir)
+
+	require.NoError(t, creator.FixPermissions(issues))
+
+	requireMode(t, path, 0o600)
+	requireMode(t, filepath.Dir(path), 0o700)
+	// Already stricter than configured, so it must not be loosened.
+	requireMode(t, strict, 0o400)
+
+	issues, err = creator.CheckPermissions()
+	require.NoError(t, err)
+	assert.Empty(t, issues)
+}
+
+func TestCheckPermissions_MissingBaseDir(t *testing.T) {
+	creator := memo.New(&config.Config{BaseDir: filepath.Join(t.TempDir(), "missing")})
+
+	issues, err := creator.CheckPermissions()
+	require.NoError(t, err)
+	assert.Empty(t, issues)
+}
+
+func TestCheckRootPermissions(t *testing.T) {
+	skipOnWindows(t)
+
+	baseDir := t.TempDir()
+	creator := memo.New(&config.Config{BaseDir: baseDir})
+
+	require.NoError(t, os.Chmod(baseDir, 0o700))
+	assert.Empty(t, creator.CheckRootPermissions())
+
+	require.NoError(t, os.Chmod(baseDir, 0o755))
+	assert.Contains(t, creator.CheckRootPermissions(), "readable by other users")
+}
+
+func TestCheckRootPermissions_ConfiguredDirMode(t *testing.T) {
+	skipOnWindows(t)
+
+	baseDir := t.TempDir()
+	creator := memo.New(&config.Config{BaseDir: baseDir, DirMode: 0o750})
+
+	require.NoError(t, os.Chmod(baseDir, 0o750))
+	assert.Empty(t, creator.CheckRootPermissions())
+
+	require.NoError(t, os.Chmod(baseDir, 0o700))
+	assert.Empty(t, creator.CheckRootPermissions())
+
+	require.NoError(t, os.Chmod(baseDir, 0o755))
+	warning := creator.CheckRootPermissions()
+	assert.Contains(t, warning, "(0755)")
+	assert.Contains(t, warning, "restrict it to 0750")
+}
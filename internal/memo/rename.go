@@ -0,0 +1,51 @@
+package memo
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"path"
+
+	"github.com/sushichan044/memo-cli/internal/crypt"
+	"github.com/sushichan044/memo-cli/internal/memofs"
+)
+
+// Rename gives the memo of entry a new name and returns the renamed entry.
+// The memo keeps its date directory, timestamp, extension and encryption, so it stays
+// in place when listed by date. An empty name leaves only the timestamp.
+// Returns an error wrapping ErrExists if a memo with the new file name already exists.
+func Rename(fsys memofs.FS, entry Entry, name string) (Entry, error) {
+	filename := sanitizeFileName(normalizeFileName(fileStem(entry.CreatedAt.Format(timestampLayout), name))) +
+		"." + entry.Ext
+	if entry.Encrypted {
+		filename += crypt.Suffix
+	}
+	if err := ValidateFileName(filename); err != nil {
+		return Entry{}, err
+	}
+
+	newName := path.Join(entry.DateDir, filename)
+	if newName == entry.RelPath {
+		return entry, nil
+	}
+
+	if _, err := fsys.Stat(newName); err == nil {
+		return Entry{}, fmt.Errorf("%w: %s", ErrExists, newName)
+	} else if !errors.Is(err, fs.ErrNotExist) {
+		return Entry{}, fmt.Errorf("failed to rename memo: %w", err)
+	}
+
+	if err := fsys.Rename(entry.RelPath, newName); err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return Entry{}, fmt.Errorf("%w: %s", ErrNotFound, entry.Path)
+		}
+		return Entry{}, fmt.Errorf("failed to rename memo: %w", err)
+	}
+
+	renamed, ok := parseEntry(fsys.Root(), entry.DateDir, filename)
+	if !ok {
+		return Entry{}, fmt.Errorf("%w: %s", ErrNotFound, newName)
+	}
+
+	return renamed, nil
+}
@@ -0,0 +1,53 @@
+package memo_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/sushichan044/memo-cli/internal/memo"
+	"github.com/sushichan044/memo-cli/internal/memofs"
+)
+
+func TestRename(t *testing.T) {
+	fsys := memofs.NewMem(memRoot)
+	writeMemo(t, fsys, "20251031/14-30-45-draft.md.age", "ciphertext")
+
+	entry, err := memo.Resolve(fsys, "draft")
+	require.NoError(t, err)
+
+	renamed, err := memo.Rename(fsys, entry, "release notes")
+	require.NoError(t, err)
+	assert.Equal(t, "20251031/14-30-45-release-notes.md.age", renamed.RelPath)
+	assert.Equal(t, "release-notes", renamed.Name)
+	assert.True(t, renamed.Encrypted)
+	assert.Equal(t, entry.CreatedAt, renamed.CreatedAt)
+
+	data, err := fsys.ReadFile(renamed.RelPath)
+	require.NoError(t, err)
+	assert.Equal(t, "ciphertext", string(data))
+
+	_, err = fsys.Stat(entry.RelPath)
+	require.Error(t, err)
+}
+
+func TestRename_Errors(t *testing.T) {
+	fsys := memofs.NewMem(memRoot)
+	writeMemo(t, fsys, "20251031/14-30-45-a.md", "")
+	writeMemo(t, fsys, "20251031/14-30-45-b.md", "")
+
+	entry, err := memo.Resolve(fsys, "a")
+	require.NoError(t, err)
+
+	_, err = memo.Rename(fsys, entry, "b")
+	require.ErrorIs(t, err, memo.ErrExists)
+
+	_, err = memo.Rename(fsys, entry, strings.Repeat("x", 300))
+	require.ErrorIs(t, err, memo.ErrFileNameTooLong)
+
+	same, err := memo.Rename(fsys, entry, "a")
+	require.NoError(t, err)
+	assert.Equal(t, entry, same)
+}
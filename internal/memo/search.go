@@ -0,0 +1,39 @@
+package memo
+
+import (
+	"bufio"
+	"bytes"
+	"regexp"
+)
+
+// Match is a line of a memo matching a search pattern.
+type Match struct {
+	Entry Entry
+	// Line is the 1-based line number.
+	Line int
+	// Text is the matching line without its line terminator.
+	Text string
+}
+
+// Search scans entries for lines matching re, in the order of entries.
+// read returns the plaintext content of a memo, which lets callers decrypt encrypted memos;
+// returning nil content with a nil error skips the memo.
+func Search(entries []Entry, re *regexp.Regexp, read func(Entry) ([]byte, error)) ([]Match, error) {
+	var matches []Match
+	for _, entry := range entries {
+		content, err := read(entry)
+		if err != nil {
+			return nil, err
+		}
+
+		scanner := bufio.NewScanner(bytes.NewReader(content))
+		scanner.Buffer(nil, len(content)+1)
+		for line := 1; scanner.Scan(); line++ {
+			if re.Match(scanner.Bytes()) {
+				matches = append(matches, Match{Entry: entry, Line: line, Text: scanner.Text()})
+			}
+		}
+	}
+
+	return matches, nil
+}
@@ -0,0 +1,38 @@
+package memo
+
+import (
+	"fmt"
+	"io/fs"
+
+	"github.com/sushichan044/memo-cli/internal/crypt"
+	"github.com/sushichan044/memo-cli/internal/markdown"
+	"github.com/sushichan044/memo-cli/internal/memofs"
+)
+
+// AddTag tags the memo of entry with tag, re-encrypting it if it is encrypted.
+// It reports false, leaving the memo untouched, when the memo already has the tag.
+func AddTag(fsys memofs.FS, keyring *crypt.Keyring, entry Entry, tag string, perm fs.FileMode) (bool, error) {
+	content, err := keyring.ReadFile(fsys, entry.RelPath)
+	if err != nil {
+		return false, err
+	}
+	if markdown.HasTag(markdown.Tags(content), tag) {
+		return false, nil
+	}
+
+	content, err = markdown.AddTag(content, tag)
+	if err != nil {
+		return false, err
+	}
+
+	if entry.Encrypted {
+		err = keyring.WriteFile(fsys, entry.RelPath, content, perm)
+	} else {
+		err = fsys.WriteFile(entry.RelPath, content, perm)
+	}
+	if err != nil {
+		return false, fmt.Errorf("failed to tag memo: %w", err)
+	}
+
+	return true, nil
+}
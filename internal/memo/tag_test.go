@@ -0,0 +1,35 @@
+package memo_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/sushichan044/memo-cli/internal/config"
+	"github.com/sushichan044/memo-cli/internal/crypt"
+	"github.com/sushichan044/memo-cli/internal/memo"
+	"github.com/sushichan044/memo-cli/internal/memofs"
+)
+
+func TestAddTag(t *testing.T) {
+	fsys := memofs.NewMem("/memo")
+	require.NoError(t, fsys.MkdirAll("20251031", 0o700))
+	require.NoError(t, fsys.WriteFile("20251031/14-30-45-notes.md", []byte("# Notes\n"), 0o600))
+
+	entries, err := memo.List(fsys)
+	require.NoError(t, err)
+	keyring := crypt.NewKeyring(config.Encryption{})
+
+	added, err := memo.AddTag(fsys, keyring, entries[0], "#work", 0o600)
+	require.NoError(t, err)
+	assert.True(t, added)
+
+	added, err = memo.AddTag(fsys, keyring, entries[0], "Work", 0o600)
+	require.NoError(t, err)
+	assert.False(t, added, "already tagged")
+
+	content, err := fsys.ReadFile("20251031/14-30-45-notes.md")
+	require.NoError(t, err)
+	assert.Equal(t, "# Notes\n#work\n", string(content))
+}
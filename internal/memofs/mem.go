@@ -0,0 +1,415 @@
+package memofs
+
+import (
+	"bytes"
+	"io"
+	"io/fs"
+	"path"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Mem is an FS that keeps everything in memory.
+// The root directory always exists. Mem is safe for concurrent use.
+type Mem struct {
+	mu    sync.RWMutex
+	root  string
+	nodes map[string]*memNode
+	now   func() time.Time
+}
+
+type memNode struct {
+	data    []byte
+	mode    fs.FileMode
+	modTime time.Time
+}
+
+// NewMem creates an empty in-memory FS. root is only used for display, as returned by Root.
+func NewMem(root string) *Mem {
+	m := &Mem{root: root, nodes: map[string]*memNode{}, now: time.Now}
+	m.nodes["."] = &memNode{mode: fs.ModeDir | 0o700, modTime: m.now()}
+	return m
+}
+
+func (m *Mem) Root() string {
+	return m.root
+}
+
+func (m *Mem) Open(name string) (fs.File, error) {
+	if err := checkName("open", name); err != nil {
+		return nil, err
+	}
+
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	node, ok := m.nodes[name]
+	if !ok {
+		return nil, &fs.PathError{Op: "open", Path: name, Err: fs.ErrNotExist}
+	}
+
+	info := m.info(name, node)
+	if node.mode.IsDir() {
+		entries, err := m.readDir(name)
+		if err != nil {
+			return nil, err
+		}
+		return &memDir{info: info, entries: entries}, nil
+	}
+
+	return &memFile{info: info, Reader: bytes.NewReader(slices.Clone(node.data))}, nil
+}
+
+func (m *Mem) ReadDir(name string) ([]fs.DirEntry, error) {
+	if err := checkName("readdir", name); err != nil {
+		return nil, err
+	}
+
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	return m.readDir(name)
+}
+
+func (m *Mem) ReadFile(name string) ([]byte, error) {
+	if err := checkName("readfile", name); err != nil {
+		return nil, err
+	}
+
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	node, ok := m.nodes[name]
+	if !ok {
+		return nil, &fs.PathError{Op: "readfile", Path: name, Err: fs.ErrNotExist}
+	}
+	if node.mode.IsDir() {
+		return nil, &fs.PathError{Op: "readfile", Path: name, Err: errIsDir}
+	}
+
+	return slices.Clone(node.data), nil
+}
+
+func (m *Mem) Stat(name string) (fs.FileInfo, error) {
+	if err := checkName("stat", name); err != nil {
+		return nil, err
+	}
+
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	node, ok := m.nodes[name]
+	if !ok {
+		return nil, &fs.PathError{Op: "stat", Path: name, Err: fs.ErrNotExist}
+	}
+
+	return m.info(name, node), nil
+}
+
+func (m *Mem) MkdirAll(name string, perm fs.FileMode) error {
+	if err := checkName("mkdir", name); err != nil {
+		return err
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	return m.mkdirAll(name, perm)
+}
+
+func (m *Mem) Create(name string, perm fs.FileMode) (io.WriteCloser, error) {
+	if err := checkName("create", name); err != nil {
+		return nil, err
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if _, exists := m.nodes[name]; exists {
+		return nil, &fs.PathError{Op: "create", Path: name, Err: fs.ErrExist}
+	}
+	if err := m.checkParent("create", name); err != nil {
+		return nil, err
+	}
+
+	node := &memNode{mode: perm.Perm(), modTime: m.now()}
+	m.nodes[name] = node
+
+	return &memWriter{mem: m, node: node}, nil
+}
+
+func (m *Mem) WriteFile(name string, data []byte, perm fs.FileMode) error {
+	if err := checkName("write", name); err != nil {
+		return err
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if node, exists := m.nodes[name]; exists {
+		if node.mode.IsDir() {
+			return &fs.PathError{Op: "write", Path: name, Err: errIsDir}
+		}
+		node.data = slices.Clone(data)
+		node.modTime = m.now()
+		return nil
+	}
+
+	if err := m.checkParent("write", name); err != nil {
+		return err
+	}
+
+	m.nodes[name] = &memNode{data: slices.Clone(data), mode: perm.Perm(), modTime: m.now()}
+
+	return nil
+}
+
+func (m *Mem) Chmod(name string, perm fs.FileMode) error {
+	if err := checkName("chmod", name); err != nil {
+		return err
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	node, ok := m.nodes[name]
+	if !ok {
+		return &fs.PathError{Op: "chmod", Path: name, Err: fs.ErrNotExist}
+	}
+	node.mode = node.mode.Type() | perm.Perm()
+
+	return nil
+}
+
+// Chtimes sets the modification time of name. Mem does not track access times.
+func (m *Mem) Chtimes(name string, _, mtime time.Time) error {
+	if err := checkName("chtimes", name); err != nil {
+		return err
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	node, ok := m.nodes[name]
+	if !ok {
+		return &fs.PathError{Op: "chtimes", Path: name, Err: fs.ErrNotExist}
+	}
+	node.modTime = mtime
+
+	return nil
+}
+
+func (m *Mem) Remove(name string) error {
+	if err := checkName("remove", name); err != nil {
+		return err
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	node, ok := m.nodes[name]
+	if !ok || name == "." {
+		return &fs.PathError{Op: "remove", Path: name, Err: fs.ErrNotExist}
+	}
+	if node.mode.IsDir() && len(m.children(name)) > 0 {
+		return &fs.PathError{Op: "remove", Path: name, Err: errNotEmpty}
+	}
+	delete(m.nodes, name)
+
+	return nil
+}
+
+func (m *Mem) Rename(oldname, newname string) error {
+	if err := checkName("rename", oldname); err != nil {
+		return err
+	}
+	if err := checkName("rename", newname); err != nil {
+		return err
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	node, ok := m.nodes[oldname]
+	if !ok || oldname == "." {
+		return &fs.PathError{Op: "rename", Path: oldname, Err: fs.ErrNotExist}
+	}
+	if err := m.checkParent("rename", newname); err != nil {
+		return err
+	}
+	if target, exists := m.nodes[newname]; exists && target.mode.IsDir() {
+		return &fs.PathError{Op: "rename", Path: newname, Err: fs.ErrExist}
+	}
+
+	if node.mode.IsDir() {
+		if strings.HasPrefix(newname, oldname+"/") {
+			return &fs.PathError{Op: "rename", Path: newname, Err: fs.ErrInvalid}
+		}
+		for _, child := range m.descendants(oldname) {
+			m.nodes[newname+strings.TrimPrefix(child, oldname)] = m.nodes[child]
+			delete(m.nodes, child)
+		}
+	}
+
+	delete(m.nodes, oldname)
+	m.nodes[newname] = node
+
+	return nil
+}
+
+// SetClock replaces the clock used for modification times, for deterministic tests.
+func (m *Mem) SetClock(now func() time.Time) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.now = now
+}
+
+func (m *Mem) mkdirAll(name string, perm fs.FileMode) error {
+	if node, ok := m.nodes[name]; ok {
+		if !node.mode.IsDir() {
+			return &fs.PathError{Op: "mkdir", Path: name, Err: fs.ErrExist}
+		}
+		return nil
+	}
+
+	if err := m.mkdirAll(path.Dir(name), perm); err != nil {
+		return err
+	}
+
+	m.nodes[name] = &memNode{mode: fs.ModeDir | perm.Perm(), modTime: m.now()}
+
+	return nil
+}
+
+func (m *Mem) checkParent(op, name string) error {
+	parent, ok := m.nodes[path.Dir(name)]
+	if !ok {
+		return &fs.PathError{Op: op, Path: name, Err: fs.ErrNotExist}
+	}
+	if !parent.mode.IsDir() {
+		return &fs.PathError{Op: op, Path: name, Err: errNotDir}
+	}
+	return nil
+}
+
+func (m *Mem) readDir(name string) ([]fs.DirEntry, error) {
+	node, ok := m.nodes[name]
+	if !ok {
+		return nil, &fs.PathError{Op: "readdir", Path: name, Err: fs.ErrNotExist}
+	}
+	if !node.mode.IsDir() {
+		return nil, &fs.PathError{Op: "readdir", Path: name, Err: errNotDir}
+	}
+
+	children := m.children(name)
+	entries := make([]fs.DirEntry, 0, len(children))
+	for _, child := range children {
+		entries = append(entries, fs.FileInfoToDirEntry(m.info(child, m.nodes[child])))
+	}
+
+	return entries, nil
+}
+
+// children returns the sorted names of the direct children of dir.
+func (m *Mem) children(dir string) []string {
+	var names []string
+	for name := range m.nodes {
+		if name != "." && path.Dir(name) == dir {
+			names = append(names, name)
+		}
+	}
+	slices.Sort(names)
+	return names
+}
+
+// descendants returns the names of every node below dir.
+func (m *Mem) descendants(dir string) []string {
+	var names []string
+	for name := range m.nodes {
+		if strings.HasPrefix(name, dir+"/") {
+			names = append(names, name)
+		}
+	}
+	return names
+}
+
+func (m *Mem) info(name string, node *memNode) *memInfo {
+	return &memInfo{name: path.Base(name), size: int64(len(node.data)), mode: node.mode, modTime: node.modTime}
+}
+
+type memInfo struct {
+	name    string
+	size    int64
+	mode    fs.FileMode
+	modTime time.Time
+}
+
+func (i *memInfo) Name() string       { return i.name }
+func (i *memInfo) Size() int64        { return i.size }
+func (i *memInfo) Mode() fs.FileMode  { return i.mode }
+func (i *memInfo) ModTime() time.Time { return i.modTime }
+func (i *memInfo) IsDir() bool        { return i.mode.IsDir() }
+func (i *memInfo) Sys() any           { return nil }
+
+// memFile is an open regular file. It reads from a snapshot taken at Open.
+type memFile struct {
+	*bytes.Reader
+
+	info *memInfo
+}
+
+func (f *memFile) Stat() (fs.FileInfo, error) { return f.info, nil }
+func (f *memFile) Close() error               { return nil }
+
+// memDir is an open directory. It lists a snapshot taken at Open.
+type memDir struct {
+	info    *memInfo
+	entries []fs.DirEntry
+	offset  int
+}
+
+func (d *memDir) Stat() (fs.FileInfo, error) { return d.info, nil }
+func (d *memDir) Close() error               { return nil }
+
+func (d *memDir) Read([]byte) (int, error) {
+	return 0, &fs.PathError{Op: "read", Path: d.info.name, Err: errIsDir}
+}
+
+func (d *memDir) ReadDir(n int) ([]fs.DirEntry, error) {
+	remaining := d.entries[d.offset:]
+	if n <= 0 {
+		d.offset = len(d.entries)
+		return remaining, nil
+	}
+	if len(remaining) == 0 {
+		return nil, io.EOF
+	}
+
+	n = min(n, len(remaining))
+	d.offset += n
+
+	return remaining[:n], nil
+}
+
+// memWriter appends to a file created by Mem.Create.
+type memWriter struct {
+	mem  *Mem
+	node *memNode
+}
+
+func (w *memWriter) Write(p []byte) (int, error) {
+	w.mem.mu.Lock()
+	defer w.mem.mu.Unlock()
+
+	w.node.data = append(w.node.data, p...)
+	w.node.modTime = w.mem.now()
+
+	return len(p), nil
+}
+
+func (w *memWriter) Close() error {
+	return nil
+}
@@ -0,0 +1,57 @@
+// Package memofs abstracts the file system holding the memo tree.
+//
+// FS extends the read-only io/fs interfaces with the few write operations memo needs.
+// Names are slash-separated and relative to the memo root, as defined by fs.ValidPath.
+// OS stores memos on disk; Mem keeps them in memory for hermetic tests and
+// as a starting point for other backends.
+package memofs
+
+import (
+	"errors"
+	"io"
+	"io/fs"
+	"time"
+)
+
+var (
+	errIsDir    = errors.New("is a directory")
+	errNotDir   = errors.New("not a directory")
+	errNotEmpty = errors.New("directory not empty")
+)
+
+// FS is a writable file system rooted at the memo base directory.
+type FS interface {
+	fs.ReadDirFS
+	fs.ReadFileFS
+	fs.StatFS
+
+	// Root returns the absolute path that names are relative to.
+	// It is used to display memo paths and to hand files to external programs.
+	Root() string
+
+	// MkdirAll creates directory name and any missing parents, including the root itself,
+	// with mode perm regardless of the process umask. Existing directories are left untouched.
+	MkdirAll(name string, perm fs.FileMode) error
+	// Create creates file name with mode perm and returns a writer for its content.
+	// It fails with an error wrapping fs.ErrExist if the file already exists.
+	Create(name string, perm fs.FileMode) (io.WriteCloser, error)
+	// WriteFile replaces the content of name, creating it with mode perm if needed.
+	// Readers never observe a partially written file.
+	WriteFile(name string, data []byte, perm fs.FileMode) error
+	// Chmod changes the permission of name.
+	Chmod(name string, perm fs.FileMode) error
+	// Chtimes changes the access and modification times of name.
+	Chtimes(name string, atime, mtime time.Time) error
+	// Remove removes file name or empty directory name.
+	Remove(name string) error
+	// Rename moves oldname to newname, replacing newname if it is a file.
+	Rename(oldname, newname string) error
+}
+
+// checkName validates name as an fs.ValidPath and reports op failures like the os package does.
+func checkName(op, name string) error {
+	if !fs.ValidPath(name) {
+		return &fs.PathError{Op: op, Path: name, Err: fs.ErrInvalid}
+	}
+	return nil
+}
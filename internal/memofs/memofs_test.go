@@ -0,0 +1,149 @@
+package memofs_test
+
+import (
+	"errors"
+	"io/fs"
+	"path/filepath"
+	"runtime"
+	"testing"
+	"testing/fstest"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/sushichan044/memo-cli/internal/memofs"
+)
+
+// backends runs fn against every FS implementation, so both behave identically.
+func backends(t *testing.T, fn func(t *testing.T, fsys memofs.FS)) {
+	t.Helper()
+
+	t.Run("os", func(t *testing.T) {
+		fn(t, memofs.NewOS(filepath.Join(t.TempDir(), "memo")))
+	})
+	t.Run("mem", func(t *testing.T) {
+		fn(t, memofs.NewMem("/memo"))
+	})
+}
+
+func TestFS_Conformance(t *testing.T) {
+	backends(t, func(t *testing.T, fsys memofs.FS) {
+		require.NoError(t, fsys.MkdirAll("20251031", 0o700))
+		require.NoError(t, fsys.WriteFile("20251031/14-30-45.md", []byte("hello"), 0o600))
+
+		w, err := fsys.Create("20251031/15-00-00-notes.md", 0o600)
+		require.NoError(t, err)
+		_, err = w.Write([]byte("notes"))
+		require.NoError(t, err)
+		require.NoError(t, w.Close())
+
+		require.NoError(t, fstest.TestFS(fsys, "20251031/14-30-45.md", "20251031/15-00-00-notes.md"))
+	})
+}
+
+func TestFS_CreateExclusive(t *testing.T) {
+	backends(t, func(t *testing.T, fsys memofs.FS) {
+		require.NoError(t, fsys.MkdirAll(".", 0o700))
+
+		w, err := fsys.Create("a.md", 0o600)
+		require.NoError(t, err)
+		require.NoError(t, w.Close())
+
+		_, err = fsys.Create("a.md", 0o600)
+		require.ErrorIs(t, err, fs.ErrExist)
+
+		_, err = fsys.Create("missing/a.md", 0o600)
+		require.ErrorIs(t, err, fs.ErrNotExist)
+	})
+}
+
+func TestFS_WriteFileReplaces(t *testing.T) {
+	backends(t, func(t *testing.T, fsys memofs.FS) {
+		require.NoError(t, fsys.MkdirAll(".", 0o700))
+		require.NoError(t, fsys.WriteFile("a.md", []byte("first"), 0o600))
+		require.NoError(t, fsys.WriteFile("a.md", []byte("second"), 0o600))
+
+		data, err := fsys.ReadFile("a.md")
+		require.NoError(t, err)
+		assert.Equal(t, "second", string(data))
+	})
+}
+
+func TestFS_Permissions(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("permission bits are not meaningful on Windows")
+	}
+
+	backends(t, func(t *testing.T, fsys memofs.FS) {
+		require.NoError(t, fsys.MkdirAll("20251031", 0o700))
+		require.NoError(t, fsys.WriteFile("20251031/a.md", nil, 0o640))
+
+		info, err := fsys.Stat("20251031")
+		require.NoError(t, err)
+		assert.True(t, info.IsDir())
+		assert.Equal(t, fs.FileMode(0o700), info.Mode().Perm())
+
+		require.NoError(t, fsys.Chmod("20251031/a.md", 0o600))
+		info, err = fsys.Stat("20251031/a.md")
+		require.NoError(t, err)
+		assert.Equal(t, fs.FileMode(0o600), info.Mode().Perm())
+	})
+}
+
+func TestFS_Chtimes(t *testing.T) {
+	backends(t, func(t *testing.T, fsys memofs.FS) {
+		require.NoError(t, fsys.MkdirAll(".", 0o700))
+		require.NoError(t, fsys.WriteFile("a.md", nil, 0o600))
+
+		mtime := time.Date(2025, 10, 31, 14, 30, 45, 0, time.UTC)
+		require.NoError(t, fsys.Chtimes("a.md", mtime, mtime))
+
+		info, err := fsys.Stat("a.md")
+		require.NoError(t, err)
+		assert.True(t, mtime.Equal(info.ModTime()))
+
+		require.ErrorIs(t, fsys.Chtimes("missing.md", mtime, mtime), fs.ErrNotExist)
+	})
+}
+
+func TestFS_RemoveAndRename(t *testing.T) {
+	backends(t, func(t *testing.T, fsys memofs.FS) {
+		require.NoError(t, fsys.MkdirAll("a", 0o700))
+		require.NoError(t, fsys.MkdirAll("b", 0o700))
+		require.NoError(t, fsys.WriteFile("a/x.md", []byte("x"), 0o600))
+
+		require.Error(t, fsys.Remove("a"), "non-empty directory must not be removed")
+
+		require.NoError(t, fsys.Rename("a/x.md", "b/y.md"))
+		_, err := fsys.Stat("a/x.md")
+		require.ErrorIs(t, err, fs.ErrNotExist)
+
+		data, err := fsys.ReadFile("b/y.md")
+		require.NoError(t, err)
+		assert.Equal(t, "x", string(data))
+
+		require.NoError(t, fsys.Remove("a"))
+		_, err = fsys.Stat("a")
+		require.ErrorIs(t, err, fs.ErrNotExist)
+	})
+}
+
+func TestFS_InvalidNames(t *testing.T) {
+	backends(t, func(t *testing.T, fsys memofs.FS) {
+		for _, name := range []string{"../escape.md", "/abs.md", "a/../b.md", ""} {
+			_, err := fsys.ReadFile(name)
+			require.Error(t, err, name)
+			assert.True(t, errors.Is(err, fs.ErrInvalid), "%q: %v", name, err)
+		}
+	})
+}
+
+func TestOS_MkdirAllCreatesRoot(t *testing.T) {
+	root := filepath.Join(t.TempDir(), "nested", "memo")
+	fsys := memofs.NewOS(root)
+
+	require.NoError(t, fsys.MkdirAll(".", 0o700))
+	assert.DirExists(t, root)
+	assert.Equal(t, root, fsys.Root())
+}
@@ -0,0 +1,193 @@
+package memofs
+
+import (
+	"errors"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// OS is an FS backed by a directory on disk.
+type OS struct {
+	root string
+}
+
+// NewOS creates an FS rooted at dir, which does not need to exist yet.
+func NewOS(dir string) *OS {
+	return &OS{root: filepath.Clean(dir)}
+}
+
+func (o *OS) Root() string {
+	return o.root
+}
+
+// path converts a validated FS name into a host path.
+func (o *OS) path(op, name string) (string, error) {
+	if err := checkName(op, name); err != nil {
+		return "", err
+	}
+	return filepath.Join(o.root, filepath.FromSlash(name)), nil
+}
+
+func (o *OS) Open(name string) (fs.File, error) {
+	p, err := o.path("open", name)
+	if err != nil {
+		return nil, err
+	}
+	return os.Open(p)
+}
+
+func (o *OS) ReadDir(name string) ([]fs.DirEntry, error) {
+	p, err := o.path("readdir", name)
+	if err != nil {
+		return nil, err
+	}
+	return os.ReadDir(p)
+}
+
+func (o *OS) ReadFile(name string) ([]byte, error) {
+	p, err := o.path("readfile", name)
+	if err != nil {
+		return nil, err
+	}
+	return os.ReadFile(p)
+}
+
+func (o *OS) Stat(name string) (fs.FileInfo, error) {
+	p, err := o.path("stat", name)
+	if err != nil {
+		return nil, err
+	}
+	return os.Stat(p)
+}
+
+func (o *OS) MkdirAll(name string, perm fs.FileMode) error {
+	p, err := o.path("mkdir", name)
+	if err != nil {
+		return err
+	}
+	return mkdirAllPerm(p, perm)
+}
+
+func (o *OS) Create(name string, perm fs.FileMode) (io.WriteCloser, error) {
+	p, err := o.path("create", name)
+	if err != nil {
+		return nil, err
+	}
+
+	file, err := os.OpenFile(p, os.O_RDWR|os.O_CREATE|os.O_EXCL, perm)
+	if err != nil {
+		return nil, err
+	}
+
+	// Apply perm explicitly so that the result does not depend on the process umask.
+	if chmodErr := file.Chmod(perm); chmodErr != nil {
+		file.Close()
+		return nil, chmodErr
+	}
+
+	return file, nil
+}
+
+func (o *OS) WriteFile(name string, data []byte, perm fs.FileMode) error {
+	p, err := o.path("write", name)
+	if err != nil {
+		return err
+	}
+
+	// Write to a sibling temporary file and rename it over the target,
+	// so an interrupted write never leaves a truncated file behind.
+	tmp, err := os.CreateTemp(filepath.Dir(p), "."+filepath.Base(p)+".*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	defer tmp.Close()
+
+	if chmodErr := tmp.Chmod(perm); chmodErr != nil {
+		return chmodErr
+	}
+
+	if _, writeErr := tmp.Write(data); writeErr != nil {
+		return writeErr
+	}
+
+	if closeErr := tmp.Close(); closeErr != nil {
+		return closeErr
+	}
+
+	return os.Rename(tmp.Name(), p)
+}
+
+func (o *OS) Chmod(name string, perm fs.FileMode) error {
+	p, err := o.path("chmod", name)
+	if err != nil {
+		return err
+	}
+	return os.Chmod(p, perm)
+}
+
+func (o *OS) Chtimes(name string, atime, mtime time.Time) error {
+	p, err := o.path("chtimes", name)
+	if err != nil {
+		return err
+	}
+	return os.Chtimes(p, atime, mtime)
+}
+
+func (o *OS) Remove(name string) error {
+	p, err := o.path("remove", name)
+	if err != nil {
+		return err
+	}
+	return os.Remove(p)
+}
+
+func (o *OS) Rename(oldname, newname string) error {
+	oldPath, err := o.path("rename", oldname)
+	if err != nil {
+		return err
+	}
+
+	newPath, err := o.path("rename", newname)
+	if err != nil {
+		return err
+	}
+
+	return os.Rename(oldPath, newPath)
+}
+
+// mkdirAllPerm is like os.MkdirAll, but applies perm to every directory it creates
+// with an explicit chmod so that the result does not depend on the process umask.
+// Directories that already exist are left untouched.
+func mkdirAllPerm(path string, perm fs.FileMode) error {
+	info, err := os.Stat(path)
+	if err == nil {
+		if !info.IsDir() {
+			return &fs.PathError{Op: "mkdir", Path: path, Err: fs.ErrExist}
+		}
+		return nil
+	}
+
+	if !errors.Is(err, fs.ErrNotExist) {
+		return err
+	}
+
+	if parent := filepath.Dir(path); parent != path {
+		if parentErr := mkdirAllPerm(parent, perm); parentErr != nil {
+			return parentErr
+		}
+	}
+
+	if mkdirErr := os.Mkdir(path, perm); mkdirErr != nil {
+		if errors.Is(mkdirErr, fs.ErrExist) {
+			// Lost a race with a concurrent creator; the directory is usable as is.
+			return nil
+		}
+		return mkdirErr
+	}
+
+	return os.Chmod(path, perm)
+}
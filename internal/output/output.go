@@ -0,0 +1,75 @@
+// Package output writes command results in the format selected with --format.
+package output
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+)
+
+// Format is an output format accepted by --format.
+type Format string
+
+const (
+	// FormatText prints human-friendly messages to stderr and bare paths to stdout.
+	FormatText Format = "text"
+	// FormatJSON prints documents from the schema package to stdout.
+	FormatJSON Format = "json"
+)
+
+// Printer writes command results to stdout and human-oriented messages to stderr.
+type Printer struct {
+	format Format
+	stdout io.Writer
+	stderr io.Writer
+}
+
+// New creates a new Printer instance.
+func New(format Format, stdout, stderr io.Writer) *Printer {
+	return &Printer{format: format, stdout: stdout, stderr: stderr}
+}
+
+// Format returns the selected output format.
+func (p *Printer) Format() Format {
+	return p.format
+}
+
+// JSON reports whether results must be printed as JSON.
+func (p *Printer) JSON() bool {
+	return p.format == FormatJSON
+}
+
+// Emit writes v as a single line of JSON to stdout.
+// Calling it once per item produces NDJSON.
+func (p *Printer) Emit(v any) error {
+	return json.NewEncoder(p.stdout).Encode(v)
+}
+
+// Println writes a plain result line to stdout.
+func (p *Printer) Println(a ...any) {
+	fmt.Fprintln(p.stdout, a...)
+}
+
+// Printf writes a plain formatted result to stdout.
+func (p *Printer) Printf(format string, a ...any) {
+	fmt.Fprintf(p.stdout, format, a...)
+}
+
+// Infof writes a human-oriented message to stderr.
+// It is suppressed in JSON mode, where the emitted document carries the same information.
+func (p *Printer) Infof(format string, a ...any) {
+	if p.JSON() {
+		return
+	}
+	fmt.Fprintf(p.stderr, format, a...)
+}
+
+// Warn writes a warning to stderr, followed by a blank line.
+// Warnings are printed in every format since stderr is not parsed by scripts.
+func (p *Printer) Warn(message string) {
+	if message == "" {
+		return
+	}
+	fmt.Fprintln(p.stderr, message)
+	fmt.Fprintln(p.stderr) // blank line
+}
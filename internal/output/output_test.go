@@ -0,0 +1,55 @@
+package output_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/sushichan044/memo-cli/internal/output"
+)
+
+func TestPrinter_EmitNDJSON(t *testing.T) {
+	var stdout, stderr bytes.Buffer
+	p := output.New(output.FormatJSON, &stdout, &stderr)
+
+	require.NoError(t, p.Emit(map[string]int{"line": 1}))
+	require.NoError(t, p.Emit(map[string]int{"line": 2}))
+
+	assert.Equal(t, "{\"line\":1}\n{\"line\":2}\n", stdout.String())
+}
+
+func TestPrinter_Infof(t *testing.T) {
+	tests := []struct {
+		name   string
+		format output.Format
+		want   string
+	}{
+		{"text prints to stderr", output.FormatText, "created\n"},
+		{"json suppresses", output.FormatJSON, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var stdout, stderr bytes.Buffer
+			p := output.New(tt.format, &stdout, &stderr)
+
+			p.Infof("created\n")
+
+			assert.Equal(t, tt.want, stderr.String())
+			assert.Empty(t, stdout.String())
+		})
+	}
+}
+
+func TestPrinter_Warn(t *testing.T) {
+	var stdout, stderr bytes.Buffer
+	p := output.New(output.FormatJSON, &stdout, &stderr)
+
+	p.Warn("")
+	assert.Empty(t, stderr.String())
+
+	p.Warn("careful")
+	assert.Equal(t, "careful\n\n", stderr.String())
+}
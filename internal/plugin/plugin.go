@@ -0,0 +1,140 @@
+// Package plugin finds external subcommands: like git, `memo foo` runs an executable named memo-foo
+// found on PATH when foo is not a built-in command.
+package plugin
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"slices"
+	"strings"
+)
+
+// Prefix is prepended to a command name to form the name of its plugin executable.
+const Prefix = "memo-"
+
+// Plugin is an executable providing a memo subcommand.
+type Plugin struct {
+	// Name is the subcommand, e.g. "foo" for memo-foo.
+	Name string
+	// Path is the absolute path to the executable.
+	Path string
+}
+
+// Env is the configuration of memo passed to plugins, so that they work on the same memos in the same way.
+type Env struct {
+	// RootDir is the memo root, passed as MEMO_ROOT_DIR.
+	RootDir string
+	// Format is the output format selected with --format, passed as MEMO_FORMAT.
+	Format string
+	// ConfigFile is the config file memo read, passed as MEMO_CONFIG_FILE.
+	ConfigFile string
+	// Executable is the memo executable, passed as MEMO_EXECUTABLE so that plugins can call back into memo.
+	Executable string
+}
+
+// Command returns the command running p with args, with env added to its environment.
+// The plugin inherits the standard streams of the current process.
+func (p Plugin) Command(args []string, env Env) *exec.Cmd {
+	cmd := exec.Command(p.Path, args...)
+	cmd.Env = append(os.Environ(),
+		"MEMO_ROOT_DIR="+env.RootDir,
+		"MEMO_FORMAT="+env.Format,
+		"MEMO_CONFIG_FILE="+env.ConfigFile,
+		"MEMO_EXECUTABLE="+env.Executable,
+	)
+	cmd.Stdin = os.Stdin
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	return cmd
+}
+
+// Find returns the plugin providing the subcommand name, looked up on PATH.
+// Names that could not be typed as a subcommand, such as paths, are never found.
+func Find(name string) (Plugin, bool) {
+	if !validName(name) {
+		return Plugin{}, false
+	}
+	path, err := exec.LookPath(Prefix + name)
+	if err != nil {
+		return Plugin{}, false
+	}
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		return Plugin{}, false
+	}
+	return Plugin{Name: name, Path: abs}, true
+}
+
+// List returns the plugins on PATH, sorted by name. When several directories provide the same plugin,
+// the first one on PATH wins, as it does for Find.
+func List() []Plugin {
+	var plugins []Plugin
+	seen := make(map[string]bool)
+	for _, dir := range filepath.SplitList(os.Getenv("PATH")) {
+		if dir == "" {
+			dir = "."
+		}
+		files, err := os.ReadDir(dir)
+		if err != nil {
+			continue
+		}
+		for _, file := range files {
+			name, ok := commandName(file.Name())
+			if !ok || seen[name] || file.IsDir() {
+				continue
+			}
+			path := filepath.Join(dir, file.Name())
+			if !executable(path) {
+				continue
+			}
+			if abs, err := filepath.Abs(path); err == nil {
+				path = abs
+			}
+			seen[name] = true
+			plugins = append(plugins, Plugin{Name: name, Path: path})
+		}
+	}
+
+	slices.SortFunc(plugins, func(a, b Plugin) int { return strings.Compare(a.Name, b.Name) })
+	return plugins
+}
+
+// commandName returns the subcommand provided by the executable named filename.
+func commandName(filename string) (string, bool) {
+	name, ok := strings.CutPrefix(filename, Prefix)
+	if !ok {
+		return "", false
+	}
+	if runtime.GOOS == "windows" {
+		ext := filepath.Ext(name)
+		if !slices.ContainsFunc(filepath.SplitList(os.Getenv("PATHEXT")), func(e string) bool { return strings.EqualFold(e, ext) }) {
+			return "", false
+		}
+		name = strings.TrimSuffix(name, ext)
+	}
+	return name, validName(name)
+}
+
+// validName reports whether name can be a subcommand: a word of letters, digits, dashes and underscores.
+func validName(name string) bool {
+	if name == "" || strings.HasPrefix(name, "-") {
+		return false
+	}
+	for _, r := range name {
+		if !(r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' || r == '-' || r == '_') {
+			return false
+		}
+	}
+	return true
+}
+
+// executable reports whether path is a regular file that can be run.
+func executable(path string) bool {
+	info, err := os.Stat(path)
+	if err != nil || !info.Mode().IsRegular() {
+		return false
+	}
+	return runtime.GOOS == "windows" || info.Mode().Perm()&0o111 != 0
+}
@@ -0,0 +1,78 @@
+package plugin_test
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/sushichan044/memo-cli/internal/plugin"
+)
+
+// setup puts plugins in two directories on PATH and returns them.
+func setup(t *testing.T) (string, string) {
+	t.Helper()
+	if runtime.GOOS == "windows" {
+		t.Skip("plugins are tested with sh scripts")
+	}
+
+	first, second := t.TempDir(), t.TempDir()
+	script := "#!/bin/sh\necho \"$0 $* $MEMO_ROOT_DIR $MEMO_FORMAT $MEMO_CONFIG_FILE $MEMO_EXECUTABLE\"\n"
+	for path, mode := range map[string]os.FileMode{
+		filepath.Join(first, "memo-sync"):      0o755,
+		filepath.Join(first, "memo-notes"):     0o644, // not executable
+		filepath.Join(first, "memo-"):          0o755, // no command name
+		filepath.Join(second, "memo-sync"):     0o755, // shadowed by the first directory
+		filepath.Join(second, "memo-stand_up"): 0o755,
+		filepath.Join(second, "other-tool"):    0o755,
+	} {
+		require.NoError(t, os.WriteFile(path, []byte(script), mode))
+	}
+	require.NoError(t, os.Mkdir(filepath.Join(second, "memo-dir"), 0o755))
+
+	t.Setenv("PATH", first+string(filepath.ListSeparator)+second)
+	return first, second
+}
+
+func TestList(t *testing.T) {
+	first, second := setup(t)
+
+	assert.Equal(t, []plugin.Plugin{
+		{Name: "stand_up", Path: filepath.Join(second, "memo-stand_up")},
+		{Name: "sync", Path: filepath.Join(first, "memo-sync")},
+	}, plugin.List())
+}
+
+func TestFind(t *testing.T) {
+	first, _ := setup(t)
+
+	p, ok := plugin.Find("sync")
+	require.True(t, ok)
+	assert.Equal(t, plugin.Plugin{Name: "sync", Path: filepath.Join(first, "memo-sync")}, p)
+
+	for _, name := range []string{"notes", "missing", "", "-sync", "../sync", "sync/x"} {
+		_, ok = plugin.Find(name)
+		assert.False(t, ok, name)
+	}
+}
+
+func TestPlugin_Command(t *testing.T) {
+	setup(t)
+	p, ok := plugin.Find("sync")
+	require.True(t, ok)
+
+	cmd := p.Command([]string{"--dry-run"}, plugin.Env{
+		RootDir:    "/memo",
+		Format:     "json",
+		ConfigFile: "/etc/memo.toml",
+		Executable: "/bin/memo",
+	})
+	var stdout bytes.Buffer
+	cmd.Stdout = &stdout
+	require.NoError(t, cmd.Run())
+	assert.Equal(t, p.Path+" --dry-run /memo json /etc/memo.toml /bin/memo\n", stdout.String())
+}
@@ -0,0 +1,438 @@
+// Package server serves the memo root over HTTP: a web UI to browse, search, read, edit and create memos,
+// and the JSON REST API underneath it.
+//
+// Every request must carry the token given in Options. API clients send it as "Authorization: Bearer <token>";
+// browsers open the UI once with "?token=<token>", which stores it in a cookie. Forms echo the token in a
+// hidden field, so that other pages served from localhost cannot submit them.
+package server
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"net/http"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/sushichan044/memo-cli/internal/crypt"
+	"github.com/sushichan044/memo-cli/internal/hook"
+	"github.com/sushichan044/memo-cli/internal/markdown"
+	"github.com/sushichan044/memo-cli/internal/memo"
+	"github.com/sushichan044/memo-cli/internal/memofs"
+	"github.com/sushichan044/memo-cli/internal/site"
+	"github.com/sushichan044/memo-cli/schema"
+)
+
+const (
+	// tokenCookie holds the token of browsers that opened the UI with "?token=".
+	tokenCookie = "memo_token"
+	// tokenBytes is the entropy of generated tokens.
+	tokenBytes = 24
+	// maxBodyBytes caps request bodies, which hold at most one memo.
+	maxBodyBytes = 16 << 20
+)
+
+var (
+	// ErrConflict is returned when a memo changed since the client read it.
+	ErrConflict = errors.New("memo changed since it was loaded")
+	// ErrReadOnly is returned when saving an encrypted memo that cannot be decrypted.
+	ErrReadOnly = errors.New("memo cannot be decrypted: configure encryption.identity_file to edit it")
+)
+
+// Options configures a Server.
+type Options struct {
+	// Token authenticates every request. It must not be empty.
+	Token string
+	// Title is shown on every page of the UI.
+	Title string
+	// Creator creates new memos.
+	Creator *memo.Creator
+	// Keyring reads memos and re-encrypts saved encrypted memos.
+	Keyring *crypt.Keyring
+	// FilePerm is applied to saved memos.
+	FilePerm fs.FileMode
+	// Record is called after a memo was created or saved, with a history message such as
+	// "edit 20251031/14-30-45-notes.md". It may be nil.
+	Record func(message string)
+	// Hooks run the post-edit hook after a memo is saved. It may be nil; memos are created by Creator,
+	// which runs its own hooks.
+	Hooks *hook.Runner
+}
+
+// Server serves the memos of a memo root. It implements http.Handler.
+type Server struct {
+	fsys     memofs.FS
+	opts     Options
+	mux      *http.ServeMux
+	renderer *site.Renderer
+	// mu serializes changes, so that concurrent saves of a memo are detected as conflicts.
+	mu sync.Mutex
+}
+
+// NewToken returns a random token for Options.Token.
+func NewToken() (string, error) {
+	b := make([]byte, tokenBytes)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("failed to generate token: %w", err)
+	}
+	return hex.EncodeToString(b), nil
+}
+
+// New returns a Server of the memos in fsys.
+func New(fsys memofs.FS, opts Options) *Server {
+	s := &Server{fsys: fsys, opts: opts, mux: http.NewServeMux(), renderer: site.NewRenderer()}
+
+	s.mux.HandleFunc("GET /api/memos", s.apiList)
+	s.mux.HandleFunc("POST /api/memos", s.apiCreate)
+	s.mux.HandleFunc("GET /api/memos/{path...}", s.apiRead)
+	s.mux.HandleFunc("PUT /api/memos/{path...}", s.apiUpdate)
+
+	s.mux.HandleFunc("GET /{$}", s.uiIndex)
+	s.mux.HandleFunc("GET /memos/{path...}", s.uiMemo)
+	s.mux.HandleFunc("GET /edit/{path...}", s.uiEdit)
+	s.mux.HandleFunc("POST /edit/{path...}", s.uiSave)
+	s.mux.HandleFunc("GET /new", s.uiNew)
+	s.mux.HandleFunc("POST /new", s.uiCreate)
+	s.mux.HandleFunc("GET /style.css", s.uiStyle)
+
+	return s
+}
+
+// ServeHTTP implements http.Handler.
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+	w.Header().Set("Referrer-Policy", "no-referrer")
+
+	if strings.HasPrefix(r.URL.Path, "/api/") {
+		if !s.validToken(bearerToken(r)) {
+			writeError(w, http.StatusUnauthorized, errors.New("missing or invalid token"))
+			return
+		}
+		s.mux.ServeHTTP(w, r)
+		return
+	}
+
+	// Opening the UI with ?token= stores the token and drops it from the address bar and history.
+	if token := r.URL.Query().Get("token"); token != "" && r.Method == http.MethodGet {
+		if !s.validToken(token) {
+			http.Error(w, "invalid token", http.StatusUnauthorized)
+			return
+		}
+		http.SetCookie(w, &http.Cookie{
+			Name: tokenCookie, Value: token, Path: "/", HttpOnly: true, SameSite: http.SameSiteStrictMode,
+		})
+		query := r.URL.Query()
+		query.Del("token")
+		r.URL.RawQuery = query.Encode()
+		http.Redirect(w, r, r.URL.RequestURI(), http.StatusSeeOther)
+		return
+	}
+
+	cookie, err := r.Cookie(tokenCookie)
+	if err != nil || !s.validToken(cookie.Value) {
+		http.Error(w, "open the URL printed by `memo serve`, which includes the token", http.StatusUnauthorized)
+		return
+	}
+	if r.Method == http.MethodPost && !s.validToken(r.PostFormValue("token")) {
+		http.Error(w, "missing or invalid form token", http.StatusForbidden)
+		return
+	}
+
+	s.mux.ServeHTTP(w, r)
+}
+
+func (s *Server) validToken(token string) bool {
+	return s.opts.Token != "" && subtle.ConstantTimeCompare([]byte(token), []byte(s.opts.Token)) == 1
+}
+
+func bearerToken(r *http.Request) string {
+	token, _ := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+	return token
+}
+
+// Query selects memos of the list.
+type Query struct {
+	// Text keeps memos containing it, case-insensitively.
+	Text string
+	// Tag keeps memos tagged with it.
+	Tag string
+	// Date keeps memos created on its day (YYYY-MM-DD).
+	Date string
+}
+
+// listed is a memo of a list with its tags.
+type listed struct {
+	entry memo.Entry
+	tags  []string
+}
+
+// list returns the memos selected by q, newest first. Encrypted memos that cannot be decrypted are
+// listed without tags, and only when q does not look into content.
+func (s *Server) list(q Query) ([]listed, error) {
+	entries, err := memo.List(s.fsys)
+	if err != nil {
+		return nil, err
+	}
+
+	var re *regexp.Regexp
+	if q.Text != "" {
+		re = regexp.MustCompile("(?i)" + regexp.QuoteMeta(q.Text))
+	}
+
+	var result []listed
+	for _, entry := range entries {
+		if q.Date != "" && entry.CreatedAt.Format(time.DateOnly) != q.Date {
+			continue
+		}
+
+		content, readErr := s.read(entry)
+		if readErr != nil && !errors.Is(readErr, crypt.ErrNoIdentity) {
+			return nil, readErr
+		}
+		if content == nil && (re != nil || q.Tag != "") {
+			continue
+		}
+
+		if re != nil && !re.Match(content) && !re.MatchString(entry.Title()) {
+			continue
+		}
+		tags := markdown.Tags(content)
+		if q.Tag != "" && !markdown.HasTag(tags, q.Tag) {
+			continue
+		}
+
+		result = append(result, listed{entry: entry, tags: tags})
+	}
+
+	return result, nil
+}
+
+// lookup finds the memo at relPath, such as "20251031/14-30-45-notes.md".
+// Only memos are served, never other files of the memo root.
+func (s *Server) lookup(relPath string) (memo.Entry, error) {
+	entries, err := memo.List(s.fsys)
+	if err != nil {
+		return memo.Entry{}, err
+	}
+	for _, entry := range entries {
+		if entry.RelPath == relPath {
+			return entry, nil
+		}
+	}
+	return memo.Entry{}, fmt.Errorf("%w: %q", memo.ErrNotFound, relPath)
+}
+
+func (s *Server) read(entry memo.Entry) ([]byte, error) {
+	return s.opts.Keyring.ReadFile(s.fsys, entry.RelPath)
+}
+
+// create creates a memo with content and returns it.
+func (s *Server) create(name, ext string, content []byte) (memo.Entry, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	path, err := s.opts.Creator.CreateWith(name, ext, memo.CreateOptions{Content: content})
+	if err != nil {
+		return memo.Entry{}, err
+	}
+	entry, err := memo.NewEntry(s.fsys, path)
+	if err != nil {
+		return memo.Entry{}, err
+	}
+	s.record("new %s", entry.RelPath)
+
+	return entry, nil
+}
+
+// save replaces the content of entry. A non-empty base is the checksum of the content the client
+// started from; save fails with ErrConflict if the memo changed since.
+func (s *Server) save(entry memo.Entry, content []byte, base string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	current, err := s.read(entry)
+	if errors.Is(err, crypt.ErrNoIdentity) {
+		return ErrReadOnly
+	}
+	if err != nil {
+		return err
+	}
+	if base != "" && base != checksum(current) {
+		return ErrConflict
+	}
+	if string(current) == string(content) {
+		return nil
+	}
+
+	if entry.Encrypted {
+		err = s.opts.Keyring.WriteFile(s.fsys, entry.RelPath, content, s.opts.FilePerm)
+	} else {
+		err = s.fsys.WriteFile(entry.RelPath, content, s.opts.FilePerm)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to save memo: %w", err)
+	}
+	_ = s.opts.Hooks.Run(hook.PostEdit, entry.Schema())
+	s.record("edit %s", entry.RelPath)
+
+	return nil
+}
+
+func (s *Server) record(format string, args ...any) {
+	if s.opts.Record != nil {
+		s.opts.Record(fmt.Sprintf(format, args...))
+	}
+}
+
+// checksum identifies a version of a memo for conflict detection.
+func checksum(content []byte) string {
+	sum := sha256.Sum256(content)
+	return hex.EncodeToString(sum[:])
+}
+
+// Memo is a memo returned by the API.
+type Memo struct {
+	schema.Content
+
+	// Tags are the tags of the memo.
+	Tags []string `json:"tags"`
+	// SHA256 is the checksum of the content, to send back as base_sha256 when saving it.
+	SHA256 string `json:"sha256"`
+}
+
+// CreateRequest is the body of POST /api/memos.
+type CreateRequest struct {
+	Name    string `json:"name"`
+	Ext     string `json:"ext"`
+	Content string `json:"content"`
+}
+
+// UpdateRequest is the body of PUT /api/memos/{path}.
+type UpdateRequest struct {
+	Content string `json:"content"`
+	// BaseSHA256 optionally guards against overwriting changes made since the memo was read.
+	BaseSHA256 string `json:"base_sha256,omitempty"`
+}
+
+func (s *Server) apiList(w http.ResponseWriter, r *http.Request) {
+	found, err := s.list(Query{Text: r.URL.Query().Get("q"), Tag: r.URL.Query().Get("tag"), Date: r.URL.Query().Get("date")})
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+
+	memos := make([]schema.Memo, 0, len(found))
+	for _, l := range found {
+		memos = append(memos, l.entry.Schema())
+	}
+	writeJSON(w, http.StatusOK, memos)
+}
+
+func (s *Server) apiRead(w http.ResponseWriter, r *http.Request) {
+	entry, ok := s.apiLookup(w, r)
+	if !ok {
+		return
+	}
+	s.apiMemo(w, http.StatusOK, entry)
+}
+
+func (s *Server) apiCreate(w http.ResponseWriter, r *http.Request) {
+	var req CreateRequest
+	if !decode(w, r, &req) {
+		return
+	}
+
+	entry, err := s.create(req.Name, req.Ext, []byte(req.Content))
+	if err != nil {
+		writeError(w, statusOf(err), err)
+		return
+	}
+	s.apiMemo(w, http.StatusCreated, entry)
+}
+
+func (s *Server) apiUpdate(w http.ResponseWriter, r *http.Request) {
+	entry, ok := s.apiLookup(w, r)
+	if !ok {
+		return
+	}
+	var req UpdateRequest
+	if !decode(w, r, &req) {
+		return
+	}
+
+	if err := s.save(entry, []byte(req.Content), req.BaseSHA256); err != nil {
+		writeError(w, statusOf(err), err)
+		return
+	}
+	s.apiMemo(w, http.StatusOK, entry)
+}
+
+func (s *Server) apiLookup(w http.ResponseWriter, r *http.Request) (memo.Entry, bool) {
+	entry, err := s.lookup(r.PathValue("path"))
+	if err != nil {
+		writeError(w, statusOf(err), err)
+		return memo.Entry{}, false
+	}
+	return entry, true
+}
+
+func (s *Server) apiMemo(w http.ResponseWriter, status int, entry memo.Entry) {
+	content, err := s.read(entry)
+	if err != nil {
+		writeError(w, statusOf(err), err)
+		return
+	}
+
+	tags := markdown.Tags(content)
+	if tags == nil {
+		tags = []string{}
+	}
+	writeJSON(w, status, Memo{
+		Content: schema.Content{Memo: entry.Schema(), Content: string(content)},
+		Tags:    tags,
+		SHA256:  checksum(content),
+	})
+}
+
+func decode(w http.ResponseWriter, r *http.Request, v any) bool {
+	if err := json.NewDecoder(io.LimitReader(r.Body, maxBodyBytes)).Decode(v); err != nil {
+		writeError(w, http.StatusBadRequest, fmt.Errorf("invalid request body: %w", err))
+		return false
+	}
+	return true
+}
+
+// statusOf maps an error to the HTTP status reporting it.
+func statusOf(err error) int {
+	switch {
+	case errors.Is(err, memo.ErrNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, memo.ErrExists), errors.Is(err, ErrConflict):
+		return http.StatusConflict
+	case errors.Is(err, crypt.ErrNoIdentity), errors.Is(err, ErrReadOnly):
+		return http.StatusForbidden
+	case errors.Is(err, memo.ErrInvalidExtension), errors.Is(err, memo.ErrInvalidFileName),
+		errors.Is(err, memo.ErrReservedFileName), errors.Is(err, memo.ErrFileNameTooLong):
+		return http.StatusBadRequest
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+func writeError(w http.ResponseWriter, status int, err error) {
+	writeJSON(w, status, schema.Error{Error: err.Error()})
+}
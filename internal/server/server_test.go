@@ -0,0 +1,234 @@
+package server_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/sushichan044/memo-cli/internal/config"
+	"github.com/sushichan044/memo-cli/internal/crypt"
+	"github.com/sushichan044/memo-cli/internal/memo"
+	"github.com/sushichan044/memo-cli/internal/memofs"
+	"github.com/sushichan044/memo-cli/internal/server"
+)
+
+const token = "secret"
+
+func newServer(t *testing.T, files map[string]string) (*server.Server, *memofs.Mem, *[]string) {
+	t.Helper()
+
+	fsys := memofs.NewMem("/memo")
+	for name, content := range files {
+		require.NoError(t, fsys.MkdirAll(name[:8], 0o700))
+		require.NoError(t, fsys.WriteFile(name, []byte(content), 0o600))
+	}
+
+	cfg := &config.Config{BaseDir: "/memo"}
+	var recorded []string
+	srv := server.New(fsys, server.Options{
+		Token:    token,
+		Title:    "Memos",
+		Creator:  memo.NewWithFS(cfg, fsys),
+		Keyring:  crypt.NewKeyring(cfg.Encryption),
+		FilePerm: 0o600,
+		Record:   func(message string) { recorded = append(recorded, message) },
+	})
+	return srv, fsys, &recorded
+}
+
+func api(t *testing.T, srv http.Handler, method, target, body string) *httptest.ResponseRecorder {
+	t.Helper()
+
+	req := httptest.NewRequest(method, target, strings.NewReader(body))
+	req.Header.Set("Authorization", "Bearer "+token)
+	rec := httptest.NewRecorder()
+	srv.ServeHTTP(rec, req)
+	return rec
+}
+
+func TestNewToken(t *testing.T) {
+	a, err := server.NewToken()
+	require.NoError(t, err)
+	b, err := server.NewToken()
+	require.NoError(t, err)
+
+	assert.Len(t, a, 48)
+	assert.NotEqual(t, a, b)
+}
+
+func TestServer_APIRequiresToken(t *testing.T) {
+	srv, _, _ := newServer(t, nil)
+
+	rec := httptest.NewRecorder()
+	srv.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/memos", nil))
+	assert.Equal(t, http.StatusUnauthorized, rec.Code)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/memos", nil)
+	req.Header.Set("Authorization", "Bearer wrong")
+	rec = httptest.NewRecorder()
+	srv.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusUnauthorized, rec.Code)
+}
+
+func TestServer_APIList(t *testing.T) {
+	srv, _, _ := newServer(t, map[string]string{
+		"20251030/09-00-00-design.md": "# Design #ux\n",
+		"20251031/14-30-45-notes.md":  "# Notes\nRelease plan\n",
+		"20251031/daily.md":           "# 2025-10-31\n",
+	})
+
+	for target, want := range map[string][]string{
+		"/api/memos":                 {"20251031/14-30-45-notes.md", "20251031/daily.md", "20251030/09-00-00-design.md"},
+		"/api/memos?q=release":       {"20251031/14-30-45-notes.md"},
+		"/api/memos?tag=ux":          {"20251030/09-00-00-design.md"},
+		"/api/memos?date=2025-10-31": {"20251031/14-30-45-notes.md", "20251031/daily.md"},
+		"/api/memos?q=nothing":       {},
+	} {
+		rec := api(t, srv, http.MethodGet, target, "")
+		require.Equal(t, http.StatusOK, rec.Code, target)
+
+		var memos []struct {
+			RelPath string `json:"rel_path"`
+		}
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &memos))
+		got := []string{}
+		for _, m := range memos {
+			got = append(got, m.RelPath)
+		}
+		assert.Equal(t, want, got, target)
+	}
+}
+
+func TestServer_APICreateReadUpdate(t *testing.T) {
+	srv, fsys, recorded := newServer(t, nil)
+
+	rec := api(t, srv, http.MethodPost, "/api/memos", `{"name":"standup notes","content":"# Standup #team\n"}`)
+	require.Equal(t, http.StatusCreated, rec.Code, rec.Body.String())
+
+	var created server.Memo
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &created))
+	assert.Equal(t, "standup-notes", created.Name)
+	assert.Equal(t, "# Standup #team\n", created.Content.Content)
+	assert.Equal(t, []string{"team"}, created.Tags)
+
+	rec = api(t, srv, http.MethodGet, "/api/memos/"+created.RelPath, "")
+	require.Equal(t, http.StatusOK, rec.Code)
+
+	body := `{"content":"# Standup\nDone\n","base_sha256":"` + created.SHA256 + `"}`
+	rec = api(t, srv, http.MethodPut, "/api/memos/"+created.RelPath, body)
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+
+	content, err := fsys.ReadFile(created.RelPath)
+	require.NoError(t, err)
+	assert.Equal(t, "# Standup\nDone\n", string(content))
+
+	// The memo changed since created.SHA256 was read.
+	rec = api(t, srv, http.MethodPut, "/api/memos/"+created.RelPath, body)
+	assert.Equal(t, http.StatusConflict, rec.Code)
+
+	assert.Equal(t, []string{"new " + created.RelPath, "edit " + created.RelPath}, *recorded)
+}
+
+func TestServer_APIErrors(t *testing.T) {
+	srv, _, _ := newServer(t, map[string]string{"20251031/14-30-45-notes.md": "# Notes\n"})
+
+	assert.Equal(t, http.StatusNotFound, api(t, srv, http.MethodGet, "/api/memos/20251031/missing.md", "").Code)
+	assert.Equal(t, http.StatusBadRequest, api(t, srv, http.MethodPost, "/api/memos", `{"ext":"m?d"}`).Code)
+	assert.Equal(t, http.StatusBadRequest, api(t, srv, http.MethodPut, "/api/memos/20251031/14-30-45-notes.md", "{").Code)
+}
+
+func TestServer_UITokenCookie(t *testing.T) {
+	srv, _, _ := newServer(t, map[string]string{"20251031/14-30-45-notes.md": "# Notes\n"})
+
+	rec := httptest.NewRecorder()
+	srv.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+	assert.Equal(t, http.StatusUnauthorized, rec.Code)
+
+	rec = httptest.NewRecorder()
+	srv.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/?token="+token+"&q=notes", nil))
+	require.Equal(t, http.StatusSeeOther, rec.Code)
+	assert.Equal(t, "/?q=notes", rec.Header().Get("Location"))
+	cookies := rec.Result().Cookies()
+	require.Len(t, cookies, 1)
+	assert.True(t, cookies[0].HttpOnly)
+
+	req := httptest.NewRequest(http.MethodGet, "/?q=notes", nil)
+	req.AddCookie(cookies[0])
+	rec = httptest.NewRecorder()
+	srv.ServeHTTP(rec, req)
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.Contains(t, rec.Body.String(), `href="/memos/20251031/14-30-45-notes.md">notes</a>`)
+}
+
+func ui(t *testing.T, srv http.Handler, method, target string, form url.Values) *httptest.ResponseRecorder {
+	t.Helper()
+
+	var body *strings.Reader
+	if form != nil {
+		body = strings.NewReader(form.Encode())
+	} else {
+		body = strings.NewReader("")
+	}
+	req := httptest.NewRequest(method, target, body)
+	if form != nil {
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	}
+	req.AddCookie(&http.Cookie{Name: "memo_token", Value: token})
+	rec := httptest.NewRecorder()
+	srv.ServeHTTP(rec, req)
+	return rec
+}
+
+func TestServer_UIViewAndEdit(t *testing.T) {
+	srv, fsys, _ := newServer(t, map[string]string{
+		"20251030/09-00-00-design.md": "# Design\n",
+		"20251031/14-30-45-notes.md":  "---\ntags: [proj]\n---\n# Notes\nSee [[design]].\n",
+	})
+
+	rec := ui(t, srv, http.MethodGet, "/memos/20251031/14-30-45-notes.md", nil)
+	require.Equal(t, http.StatusOK, rec.Code)
+	body := rec.Body.String()
+	assert.Contains(t, body, "<h1 id=\"notes\">Notes</h1>")
+	assert.Contains(t, body, `<a href="/memos/20251030/09-00-00-design.md">design</a>`)
+	assert.NotContains(t, body, "tags: [proj]")
+
+	rec = ui(t, srv, http.MethodGet, "/edit/20251031/14-30-45-notes.md", nil)
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.Contains(t, rec.Body.String(), "See [[design]].")
+
+	// Forms must echo the token: the cookie alone is sent by other localhost pages too.
+	form := url.Values{"content": {"# Notes\r\nEdited\r\n"}}
+	rec = ui(t, srv, http.MethodPost, "/edit/20251031/14-30-45-notes.md", form)
+	assert.Equal(t, http.StatusForbidden, rec.Code)
+
+	form.Set("token", token)
+	rec = ui(t, srv, http.MethodPost, "/edit/20251031/14-30-45-notes.md", form)
+	require.Equal(t, http.StatusSeeOther, rec.Code)
+	content, err := fsys.ReadFile("20251031/14-30-45-notes.md")
+	require.NoError(t, err)
+	assert.Equal(t, "# Notes\nEdited\n", string(content))
+}
+
+func TestServer_UICreate(t *testing.T) {
+	srv, fsys, _ := newServer(t, nil)
+
+	rec := ui(t, srv, http.MethodPost, "/new", url.Values{"token": {token}, "name": {"idea"}, "ext": {"md"}, "content": {"# Idea\n"}})
+	require.Equal(t, http.StatusSeeOther, rec.Code)
+	location := rec.Header().Get("Location")
+	assert.True(t, strings.HasSuffix(location, "-idea.md"), location)
+
+	entries, err := memo.List(fsys)
+	require.NoError(t, err)
+	require.Len(t, entries, 1)
+	assert.Equal(t, "idea", entries[0].Name)
+
+	rec = ui(t, srv, http.MethodPost, "/new", url.Values{"token": {token}, "name": {"x"}, "ext": {"m?d"}, "content": {"keep me"}})
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	assert.Contains(t, rec.Body.String(), "keep me")
+}
@@ -0,0 +1,22 @@
+package server
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/sushichan044/memo-cli/internal/memo"
+)
+
+// Names rejected by memo.ValidateFileName cannot always be produced through the API, as created memos get a
+// timestamp prefix, so the mapping of every validation error is checked directly.
+func TestStatusOf_FileNameErrors(t *testing.T) {
+	for _, name := range []string{"con", "nul.md", "a?b", ""} {
+		err := memo.ValidateFileName(name)
+		assert.Equal(t, http.StatusBadRequest, statusOf(fmt.Errorf("creating memo: %w", err)), "%q: %v", name, err)
+	}
+	assert.Equal(t, http.StatusBadRequest, statusOf(memo.ValidateFileName(strings.Repeat("a", 300))))
+}
@@ -0,0 +1,243 @@
+package server
+
+import (
+	"bytes"
+	"embed"
+	"errors"
+	"html/template"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/sushichan044/memo-cli/internal/crypt"
+	"github.com/sushichan044/memo-cli/internal/markdown"
+	"github.com/sushichan044/memo-cli/internal/memo"
+	"github.com/sushichan044/memo-cli/internal/site"
+)
+
+//go:embed assets
+var assets embed.FS //nolint:gochecknoglobals // embedded at build time
+
+//nolint:gochecknoglobals // parsed once from the embedded assets
+var pages = template.Must(template.ParseFS(assets, "assets/ui.html"))
+
+// memoLink is how templates list a memo.
+type memoLink struct {
+	URL     string
+	EditURL string
+	Title   string
+	Time    string
+	Tags    []string
+	// Locked marks encrypted memos.
+	Locked bool
+}
+
+type day struct {
+	Date  string
+	Memos []memoLink
+}
+
+type pageData struct {
+	Site  string
+	Title string
+	// Token is echoed by forms.
+	Token string
+	Query Query
+	Error string
+
+	Days []day
+
+	Memo    memoLink
+	RelPath string
+	Body    template.HTML
+	// Content and SHA256 fill the edit form.
+	Content string
+	SHA256  string
+	// Readable is false for encrypted memos that cannot be decrypted.
+	Readable bool
+
+	Name string
+	Ext  string
+}
+
+func (s *Server) uiIndex(w http.ResponseWriter, r *http.Request) {
+	q := Query{Text: r.URL.Query().Get("q"), Tag: r.URL.Query().Get("tag"), Date: r.URL.Query().Get("date")}
+	found, err := s.list(q)
+	if err != nil {
+		s.uiError(w, err)
+		return
+	}
+
+	var days []day
+	for _, l := range found {
+		date := l.entry.CreatedAt.Format(time.DateOnly)
+		if len(days) == 0 || days[len(days)-1].Date != date {
+			days = append(days, day{Date: date})
+		}
+		days[len(days)-1].Memos = append(days[len(days)-1].Memos, link(l.entry, l.tags))
+	}
+
+	s.render(w, http.StatusOK, "index", pageData{Title: "Memos", Query: q, Days: days})
+}
+
+func (s *Server) uiMemo(w http.ResponseWriter, r *http.Request) {
+	entry, err := s.lookup(r.PathValue("path"))
+	if err != nil {
+		s.uiError(w, err)
+		return
+	}
+
+	data := pageData{Title: entry.Title(), RelPath: entry.RelPath}
+	content, err := s.read(entry)
+	switch {
+	case errors.Is(err, crypt.ErrNoIdentity):
+		data.Memo = link(entry, nil)
+	case err != nil:
+		s.uiError(w, err)
+		return
+	default:
+		data.Readable = true
+		data.Memo = link(entry, markdown.Tags(content))
+		data.Body, err = s.renderer.Render(entry, content, s.resolver())
+		if err != nil {
+			s.uiError(w, err)
+			return
+		}
+	}
+
+	s.render(w, http.StatusOK, "memo", data)
+}
+
+func (s *Server) uiEdit(w http.ResponseWriter, r *http.Request) {
+	entry, err := s.lookup(r.PathValue("path"))
+	if err != nil {
+		s.uiError(w, err)
+		return
+	}
+	content, err := s.read(entry)
+	if err != nil {
+		s.uiError(w, err)
+		return
+	}
+
+	s.render(w, http.StatusOK, "edit", pageData{
+		Title: entry.Title(), Memo: link(entry, nil), RelPath: entry.RelPath,
+		Content: string(content), SHA256: checksum(content), Readable: true,
+	})
+}
+
+func (s *Server) uiSave(w http.ResponseWriter, r *http.Request) {
+	entry, err := s.lookup(r.PathValue("path"))
+	if err != nil {
+		s.uiError(w, err)
+		return
+	}
+
+	// Browsers submit textareas with CRLF line endings.
+	content := strings.ReplaceAll(r.PostFormValue("content"), "\r\n", "\n")
+	if saveErr := s.save(entry, []byte(content), r.PostFormValue("sha256")); saveErr != nil {
+		// Keep the edits on screen, so that nothing typed is lost.
+		s.render(w, statusOf(saveErr), "edit", pageData{
+			Title: entry.Title(), Memo: link(entry, nil), RelPath: entry.RelPath, Error: saveErr.Error(),
+			Content: content, SHA256: r.PostFormValue("sha256"), Readable: true,
+		})
+		return
+	}
+
+	http.Redirect(w, r, memoURL(entry), http.StatusSeeOther)
+}
+
+func (s *Server) uiNew(w http.ResponseWriter, _ *http.Request) {
+	s.render(w, http.StatusOK, "new", pageData{Title: "New memo", Ext: "md"})
+}
+
+func (s *Server) uiCreate(w http.ResponseWriter, r *http.Request) {
+	name, ext := r.PostFormValue("name"), r.PostFormValue("ext")
+	content := strings.ReplaceAll(r.PostFormValue("content"), "\r\n", "\n")
+
+	entry, err := s.create(name, ext, []byte(content))
+	if err != nil {
+		s.render(w, statusOf(err), "new", pageData{
+			Title: "New memo", Error: err.Error(), Name: name, Ext: ext, Content: content,
+		})
+		return
+	}
+
+	http.Redirect(w, r, memoURL(entry), http.StatusSeeOther)
+}
+
+func (s *Server) uiStyle(w http.ResponseWriter, _ *http.Request) {
+	css, err := site.Stylesheet()
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	ui, err := assets.ReadFile("assets/ui.css")
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/css; charset=utf-8")
+	_, _ = w.Write(append(css, ui...))
+}
+
+// resolver turns wiki-links into links to the pages of their memos.
+func (s *Server) resolver() func(markdown.Link) (string, bool) {
+	entries, err := memo.List(s.fsys)
+	if err != nil {
+		return nil
+	}
+	resolver := memo.NewLinkResolver(entries)
+
+	return func(link markdown.Link) (string, bool) {
+		target, ok := resolver.Resolve(link.Target)
+		if !ok {
+			return "", false
+		}
+		href := memoURL(target)
+		if link.Heading != "" {
+			href += "#" + site.HeadingID(link.Heading)
+		}
+		return href, true
+	}
+}
+
+func (s *Server) render(w http.ResponseWriter, status int, tmpl string, data pageData) {
+	data.Site = s.opts.Title
+	data.Token = s.opts.Token
+
+	var buf bytes.Buffer
+	if err := pages.ExecuteTemplate(&buf, tmpl, data); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.WriteHeader(status)
+	_, _ = w.Write(buf.Bytes())
+}
+
+func (s *Server) uiError(w http.ResponseWriter, err error) {
+	s.render(w, statusOf(err), "error", pageData{Title: "Error", Error: err.Error()})
+}
+
+// memoURL returns the path of the page of entry.
+func memoURL(entry memo.Entry) string {
+	return "/memos/" + entry.DateDir + "/" + url.PathEscape(strings.TrimPrefix(entry.RelPath, entry.DateDir+"/"))
+}
+
+func link(entry memo.Entry, tags []string) memoLink {
+	l := memoLink{
+		URL:     memoURL(entry),
+		EditURL: "/edit" + strings.TrimPrefix(memoURL(entry), "/memos"),
+		Title:   entry.Title(),
+		Tags:    tags,
+		Locked:  entry.Encrypted,
+	}
+	if !entry.Daily {
+		l.Time = entry.CreatedAt.Format(time.TimeOnly)
+	}
+	return l
+}
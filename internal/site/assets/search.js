@@ -0,0 +1,35 @@
+// Client-side search over window.MEMO_SEARCH_INDEX, loaded from search-index.js so it also works from file:// URLs.
+(function () {
+  var input = document.getElementById("search");
+  var results = document.getElementById("results");
+  var index = window.MEMO_SEARCH_INDEX || [];
+
+  function snippet(text, term) {
+    var at = text.toLowerCase().indexOf(term);
+    if (at < 0) return text.slice(0, 120);
+    var start = Math.max(0, at - 40);
+    return (start > 0 ? "…" : "") + text.slice(start, at + 80);
+  }
+
+  input.addEventListener("input", function () {
+    var terms = input.value.toLowerCase().split(/\s+/).filter(Boolean);
+    results.textContent = "";
+    if (terms.length === 0) return;
+
+    index.filter(function (memo) {
+      var haystack = (memo.title + " " + memo.tags.map(function (t) { return "#" + t; }).join(" ") + " " + memo.text).toLowerCase();
+      return terms.every(function (term) { return haystack.indexOf(term) >= 0; });
+    }).slice(0, 50).forEach(function (memo) {
+      var li = document.createElement("li");
+      var a = document.createElement("a");
+      a.href = memo.url;
+      a.textContent = memo.title;
+      var time = document.createElement("time");
+      time.textContent = " " + memo.date;
+      var p = document.createElement("p");
+      p.textContent = snippet(memo.text, terms[0]);
+      li.append(a, time, p);
+      results.append(li);
+    });
+  });
+})();
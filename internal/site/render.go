@@ -0,0 +1,74 @@
+package site
+
+import (
+	"bytes"
+	"fmt"
+	"html/template"
+
+	chromahtml "github.com/alecthomas/chroma/v2/formatters/html"
+	"github.com/alecthomas/chroma/v2/styles"
+	"github.com/yuin/goldmark"
+	highlighting "github.com/yuin/goldmark-highlighting/v2"
+	"github.com/yuin/goldmark/extension"
+	"github.com/yuin/goldmark/parser"
+
+	"github.com/sushichan044/memo-cli/internal/markdown"
+	"github.com/sushichan044/memo-cli/internal/memo"
+)
+
+// Renderer renders memos as HTML, the way site pages show them.
+type Renderer struct {
+	md goldmark.Markdown
+}
+
+// NewRenderer returns a Renderer of GitHub Flavored Markdown with highlighted code blocks.
+func NewRenderer() *Renderer {
+	return &Renderer{md: goldmark.New(
+		goldmark.WithExtensions(
+			extension.GFM,
+			highlighting.NewHighlighting(
+				highlighting.WithStyle(codeStyle),
+				highlighting.WithFormatOptions(chromahtml.WithClasses(true)),
+			),
+		),
+		goldmark.WithParserOptions(parser.WithAutoHeadingID()),
+	)}
+}
+
+// Render renders the content of entry. Markdown memos are rendered without their front matter, and
+// their wiki-links for which resolve returns a URL become links; other memos are shown preformatted.
+// A nil resolve leaves every wiki-link as written.
+func (r *Renderer) Render(entry memo.Entry, content []byte, resolve func(markdown.Link) (string, bool)) (template.HTML, error) {
+	if !isMarkdown(entry) {
+		return template.HTML("<pre>" + template.HTMLEscapeString(string(content)) + "</pre>"), nil //nolint:gosec // escaped
+	}
+
+	if resolve != nil {
+		content = rewriteLinks(content, resolve)
+	}
+
+	var buf bytes.Buffer
+	if err := r.md.Convert(markdown.Body(content), &buf); err != nil {
+		return "", fmt.Errorf("failed to render %s: %w", entry.RelPath, err)
+	}
+	return template.HTML(buf.String()), nil //nolint:gosec // goldmark omits raw HTML by default
+}
+
+// HeadingID returns the ID of the rendered heading, the fragment [[memo#Heading]] links point to.
+func HeadingID(heading string) string {
+	return headingID(heading)
+}
+
+// Stylesheet returns the stylesheet of site pages, including the colors of highlighted code.
+func Stylesheet() ([]byte, error) {
+	css, err := assets.ReadFile("assets/style.css")
+	if err != nil {
+		return nil, err
+	}
+
+	buf := bytes.NewBuffer(css)
+	if cssErr := chromahtml.New(chromahtml.WithClasses(true)).WriteCSS(buf, styles.Get(codeStyle)); cssErr != nil {
+		return nil, cssErr
+	}
+	return buf.Bytes(), nil
+}
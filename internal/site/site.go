@@ -0,0 +1,422 @@
+// Package site renders memos as a static HTML site that can be shared as a folder or archive.
+//
+// The site has an index of memos by date directory with a client-side search, a page per tag
+// and a page per memo. Markdown memos are rendered with highlighted code blocks, and
+// [[wiki-links]] between exported memos become links between their pages.
+package site
+
+import (
+	"bytes"
+	"embed"
+	"encoding/json"
+	"fmt"
+	"html/template"
+	"io/fs"
+	"maps"
+	"net/url"
+	"path"
+	"slices"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/sushichan044/memo-cli/internal/markdown"
+	"github.com/sushichan044/memo-cli/internal/memo"
+	"github.com/sushichan044/memo-cli/internal/memofs"
+)
+
+// codeStyle is the chroma style of highlighted code blocks.
+const codeStyle = "github"
+
+//go:embed assets
+var assets embed.FS //nolint:gochecknoglobals // embedded at build time
+
+//nolint:gochecknoglobals // parsed once from the embedded assets
+var pages = template.Must(template.ParseFS(assets, "assets/page.html"))
+
+// Options selects the memos of a site and how it is written.
+type Options struct {
+	// Title is the site name shown on every page.
+	Title string
+	// Since and Until restrict the site to memos created on or between their days. Zero values are unbounded.
+	Since time.Time
+	Until time.Time
+	// Tag restricts the site to memos tagged with it.
+	Tag string
+	// IncludeEncrypted publishes encrypted memos, decrypted. They are left out by default
+	// since a site is meant to be shared.
+	IncludeEncrypted bool
+	// FilePerm and DirPerm are applied to written files and created directories.
+	FilePerm fs.FileMode
+	DirPerm  fs.FileMode
+	// Now is shown as the generation time.
+	Now time.Time
+}
+
+// Result summarizes a written site.
+type Result struct {
+	// Memos is the number of memo pages.
+	Memos int
+	// Tags is the number of tag pages.
+	Tags int
+}
+
+// page is a memo exported to the site.
+type page struct {
+	entry memo.Entry
+	// file is the path of the page in the site, and url the same path escaped for links.
+	file    string
+	url     string
+	tags    []string
+	content []byte
+
+	body      template.HTML
+	backlinks []*page
+}
+
+// memoLink is how templates list a memo.
+type memoLink struct {
+	URL   string
+	Title string
+	Date  string
+	Time  string
+	Tags  []string
+}
+
+type day struct {
+	Date  string
+	Memos []memoLink
+}
+
+type tagLink struct {
+	Name  string
+	URL   string
+	Count int
+}
+
+type pageData struct {
+	Site      string
+	Title     string
+	Root      string
+	Generated string
+
+	Days      []day
+	Tags      []tagLink
+	Memos     []memoLink
+	Memo      memoLink
+	Body      template.HTML
+	Backlinks []memoLink
+}
+
+// searchEntry is a memo in the client-side search index.
+type searchEntry struct {
+	URL   string   `json:"url"`
+	Title string   `json:"title"`
+	Date  string   `json:"date"`
+	Tags  []string `json:"tags"`
+	Text  string   `json:"text"`
+}
+
+// Write renders the memos of entries selected by opts into out.
+// entries must be sorted newest first as returned by memo.List; links are resolved against all of them,
+// but only links to exported memos become links. read returns the plaintext content of a memo;
+// returning nil content with a nil error leaves the memo out.
+func Write(out memofs.FS, entries []memo.Entry, read func(memo.Entry) ([]byte, error), opts Options) (Result, error) {
+	selected, err := selectPages(entries, read, opts)
+	if err != nil {
+		return Result{}, err
+	}
+
+	byPath := map[string]*page{}
+	for _, p := range selected {
+		byPath[p.entry.RelPath] = p
+	}
+
+	renderer := NewRenderer()
+	resolver := memo.NewLinkResolver(entries)
+
+	for _, p := range selected {
+		body, renderErr := renderer.Render(p.entry, p.content, func(link markdown.Link) (string, bool) {
+			target, ok := resolver.Resolve(link.Target)
+			if !ok || byPath[target.RelPath] == nil {
+				return "", false
+			}
+			to := byPath[target.RelPath]
+			if to != p && !slices.Contains(to.backlinks, p) {
+				to.backlinks = append(to.backlinks, p)
+			}
+			href := "../" + to.url
+			if link.Heading != "" {
+				href += "#" + headingID(link.Heading)
+			}
+			return href, true
+		})
+		if renderErr != nil {
+			return Result{}, renderErr
+		}
+		p.body = body
+	}
+
+	w := &writer{out: out, opts: opts}
+	for _, p := range selected {
+		w.memoPage(p)
+	}
+	tags := w.tagPages(selected)
+	w.index(selected, tags)
+	w.assets(selected)
+
+	if w.err != nil {
+		return Result{}, w.err
+	}
+
+	return Result{Memos: len(selected), Tags: len(tags)}, nil
+}
+
+func selectPages(entries []memo.Entry, read func(memo.Entry) ([]byte, error), opts Options) ([]*page, error) {
+	var selected []*page
+	for _, entry := range entries {
+		if entry.Encrypted && !opts.IncludeEncrypted {
+			continue
+		}
+		if !opts.Since.IsZero() && entry.DateDir < memo.DateDir(opts.Since) {
+			continue
+		}
+		if !opts.Until.IsZero() && entry.DateDir > memo.DateDir(opts.Until) {
+			continue
+		}
+
+		content, err := read(entry)
+		if err != nil {
+			return nil, err
+		}
+		if content == nil {
+			continue
+		}
+
+		tags := markdown.Tags(content)
+		if opts.Tag != "" && !markdown.HasTag(tags, opts.Tag) {
+			continue
+		}
+
+		selected = append(selected, &page{
+			entry:   entry,
+			file:    entry.DateDir + "/" + pageName(entry),
+			url:     entry.DateDir + "/" + url.PathEscape(pageName(entry)),
+			tags:    tags,
+			content: content,
+		})
+	}
+
+	return selected, nil
+}
+
+// rewriteLinks replaces the wiki-links of content that resolve to a page with Markdown links.
+// Other links are left as written, so readers can tell they lead nowhere.
+func rewriteLinks(content []byte, resolve func(markdown.Link) (string, bool)) []byte {
+	found := markdown.Links(content)
+	if len(found) == 0 {
+		return content
+	}
+
+	lines := strings.SplitAfter(string(content), "\n")
+	// Replace from the end so that earlier columns stay valid.
+	for _, link := range slices.Backward(found) {
+		href, ok := resolve(link)
+		if !ok {
+			continue
+		}
+		text := link.Alias
+		if text == "" {
+			text = link.Target
+		}
+		line := lines[link.Line-1]
+		start := link.Column - 1
+		lines[link.Line-1] = line[:start] + "[" + text + "](<" + href + ">)" + line[start+len(link.Raw):]
+	}
+
+	return []byte(strings.Join(lines, ""))
+}
+
+// headingID approximates the IDs goldmark generates for headings, so that [[memo#Heading]] lands on it.
+func headingID(heading string) string {
+	var b strings.Builder
+	for _, r := range strings.ToLower(strings.TrimSpace(heading)) {
+		switch {
+		case unicode.IsLetter(r) || unicode.IsDigit(r) || r == '-' || r == '_':
+			b.WriteRune(r)
+		case unicode.IsSpace(r):
+			b.WriteRune('-')
+		}
+	}
+	return b.String()
+}
+
+// pageName is the file name of the page of entry, unique within its date directory (e.g. "14-30-45-notes.md.html").
+func pageName(entry memo.Entry) string {
+	return entry.Stem() + "." + entry.Ext + ".html"
+}
+
+func isMarkdown(entry memo.Entry) bool {
+	return entry.Ext == "md" || entry.Ext == "markdown"
+}
+
+// writer writes the files of a site, keeping the first error.
+type writer struct {
+	out  memofs.FS
+	opts Options
+	err  error
+}
+
+func (w *writer) write(name string, data []byte) {
+	if w.err != nil {
+		return
+	}
+	if err := w.out.MkdirAll(path.Dir(name), w.opts.DirPerm); err != nil {
+		w.err = fmt.Errorf("failed to write %s: %w", name, err)
+		return
+	}
+	if err := w.out.WriteFile(name, data, w.opts.FilePerm); err != nil {
+		w.err = fmt.Errorf("failed to write %s: %w", name, err)
+	}
+}
+
+func (w *writer) render(name, tmpl string, data pageData) {
+	data.Site = w.opts.Title
+	data.Generated = w.opts.Now.Format("2006-01-02 15:04")
+	// Pages are at most a few directories deep, e.g. "tags/project/x.html".
+	data.Root = strings.Repeat("../", strings.Count(name, "/"))
+
+	var buf bytes.Buffer
+	if err := pages.ExecuteTemplate(&buf, tmpl, data); err != nil {
+		if w.err == nil {
+			w.err = fmt.Errorf("failed to render %s: %w", name, err)
+		}
+		return
+	}
+	w.write(name, buf.Bytes())
+}
+
+func (w *writer) memoPage(p *page) {
+	backlinks := make([]memoLink, 0, len(p.backlinks))
+	for _, from := range p.backlinks {
+		backlinks = append(backlinks, link(from, "../"))
+	}
+	// Tags without a page are shown unlinked.
+	tags := make([]tagLink, 0, len(p.tags))
+	for _, tag := range p.tags {
+		_, href, _ := tagPage(tag)
+		tags = append(tags, tagLink{Name: tag, URL: href})
+	}
+	w.render(p.file, "memo", pageData{
+		Title:     p.entry.Title(),
+		Memo:      link(p, "../"),
+		Body:      p.body,
+		Backlinks: backlinks,
+		Tags:      tags,
+	})
+}
+
+func (w *writer) tagPages(selected []*page) []tagLink {
+	byTag := map[string][]*page{}
+	for _, p := range selected {
+		for _, tag := range p.tags {
+			byTag[tag] = append(byTag[tag], p)
+		}
+	}
+
+	var tags []tagLink
+	for _, tag := range slices.Sorted(maps.Keys(byTag)) {
+		name, href, ok := tagPage(tag)
+		if !ok {
+			continue
+		}
+		root := strings.Repeat("../", strings.Count(name, "/"))
+		memos := make([]memoLink, 0, len(byTag[tag]))
+		for _, p := range byTag[tag] {
+			memos = append(memos, link(p, root))
+		}
+		w.render(name, "tag", pageData{Title: tag, Memos: memos})
+		tags = append(tags, tagLink{Name: tag, URL: href, Count: len(byTag[tag])})
+	}
+
+	return tags
+}
+
+// tagPage returns the path of the page of tag in the site and the same path escaped for links, e.g.
+// "tags/project/x.html". Tags that are not valid tag names or have empty segments such as "a//b" or "proj/",
+// which front matter allows, get no page.
+func tagPage(tag string) (string, string, bool) {
+	if !markdown.ValidTag(tag) || !fs.ValidPath("tags/"+tag) {
+		return "", "", false
+	}
+	segments := strings.Split(tag, "/")
+	for i, segment := range segments {
+		segments[i] = url.PathEscape(segment)
+	}
+	return "tags/" + tag + ".html", "tags/" + strings.Join(segments, "/") + ".html", true
+}
+
+func (w *writer) index(selected []*page, tags []tagLink) {
+	var days []day
+	for _, p := range selected {
+		date := p.entry.CreatedAt.Format(time.DateOnly)
+		if len(days) == 0 || days[len(days)-1].Date != date {
+			days = append(days, day{Date: date})
+		}
+		days[len(days)-1].Memos = append(days[len(days)-1].Memos, link(p, ""))
+	}
+
+	w.render("index.html", "index", pageData{Title: "Index", Days: days, Tags: tags})
+}
+
+func (w *writer) assets(selected []*page) {
+	css, err := Stylesheet()
+	if err != nil {
+		w.err = err
+		return
+	}
+	w.write("style.css", css)
+
+	script, err := assets.ReadFile("assets/search.js")
+	if err != nil {
+		w.err = err
+		return
+	}
+	w.write("search.js", script)
+
+	index := make([]searchEntry, 0, len(selected))
+	for _, p := range selected {
+		tags := p.tags
+		if tags == nil {
+			tags = []string{}
+		}
+		index = append(index, searchEntry{
+			URL:   p.url,
+			Title: p.entry.Title(),
+			Date:  p.entry.CreatedAt.Format(time.DateOnly),
+			Tags:  tags,
+			Text:  string(markdown.Body(p.content)),
+		})
+	}
+	data, err := json.Marshal(index)
+	if err != nil {
+		w.err = err
+		return
+	}
+	// A script rather than JSON, so that search also works when the site is opened from file:// URLs.
+	w.write("search-index.js", append(append([]byte("window.MEMO_SEARCH_INDEX = "), data...), ";\n"...))
+}
+
+// link lists p on a page whose root is root.
+func link(p *page, root string) memoLink {
+	l := memoLink{
+		URL:   root + p.url,
+		Title: p.entry.Title(),
+		Date:  p.entry.CreatedAt.Format(time.DateOnly),
+		Tags:  p.tags,
+	}
+	if !p.entry.Daily {
+		l.Time = p.entry.CreatedAt.Format(time.TimeOnly)
+	}
+	return l
+}
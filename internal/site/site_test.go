@@ -0,0 +1,122 @@
+package site_test
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/sushichan044/memo-cli/internal/memo"
+	"github.com/sushichan044/memo-cli/internal/memofs"
+	"github.com/sushichan044/memo-cli/internal/site"
+)
+
+func write(t *testing.T, opts site.Options) *memofs.Mem {
+	t.Helper()
+
+	return writeFiles(t, map[string]string{
+		"20251030/09-00-00-design.md":     "---\ntags: [proj]\n---\n## Open Questions\n\n```go\nfunc main() {}\n```\n",
+		"20251031/10-00-00-plan.md":       "See [[design#Open Questions|the design]], [[secret]] and [[nowhere]].\n`[[design]]` #proj/alpha\n",
+		"20251031/11-00-00-secret.md.age": "not really encrypted",
+		"20251031/12-00-00-log.txt":       "<b>raw</b>\n",
+	}, opts)
+}
+
+func writeFiles(t *testing.T, files map[string]string, opts site.Options) *memofs.Mem {
+	t.Helper()
+
+	fsys := memofs.NewMem("/memo")
+	for name, content := range files {
+		require.NoError(t, fsys.MkdirAll(name[:8], 0o700))
+		require.NoError(t, fsys.WriteFile(name, []byte(content), 0o600))
+	}
+
+	entries, err := memo.List(fsys)
+	require.NoError(t, err)
+
+	out := memofs.NewMem("/site")
+	opts.Title = "Sprint"
+	opts.FilePerm, opts.DirPerm = 0o600, 0o700
+	opts.Now = time.Date(2025, 11, 1, 9, 0, 0, 0, time.Local)
+
+	_, err = site.Write(out, entries, func(e memo.Entry) ([]byte, error) {
+		return fsys.ReadFile(e.RelPath)
+	}, opts)
+	require.NoError(t, err)
+
+	return out
+}
+
+func read(t *testing.T, out *memofs.Mem, name string) string {
+	t.Helper()
+
+	data, err := out.ReadFile(name)
+	require.NoError(t, err)
+	return string(data)
+}
+
+func TestWrite(t *testing.T) {
+	out := write(t, site.Options{})
+
+	plan := read(t, out, "20251031/10-00-00-plan.md.html")
+	assert.Contains(t, plan, `<a href="../20251030/09-00-00-design.md.html#open-questions">the design</a>`)
+	assert.Contains(t, plan, "[[secret]]", "encrypted memos are not exported, so links to them stay as written")
+	assert.Contains(t, plan, "[[nowhere]]")
+	assert.Contains(t, plan, "<code>[[design]]</code>")
+	assert.Contains(t, plan, `href="../tags/proj/alpha.html"`)
+
+	design := read(t, out, "20251030/09-00-00-design.md.html")
+	assert.Contains(t, design, `<h2 id="open-questions">Open Questions</h2>`)
+	assert.Contains(t, design, `<span class="kd">func</span>`, "code is highlighted")
+	assert.NotContains(t, design, "tags: [proj]", "front matter is not rendered")
+	assert.Contains(t, design, "Linked from")
+
+	assert.Contains(t, read(t, out, "20251031/12-00-00-log.txt.html"), "<pre>&lt;b&gt;raw&lt;/b&gt;\n</pre>")
+
+	index := read(t, out, "index.html")
+	assert.Less(t, strings.Index(index, "2025-10-31"), strings.Index(index, "2025-10-30"), "newest day first")
+	assert.Contains(t, index, `href="tags/proj.html"`)
+	assert.NotContains(t, index, "secret")
+
+	assert.Contains(t, read(t, out, "tags/proj/alpha.html"), `href="../../20251031/10-00-00-plan.md.html"`)
+	assert.Contains(t, read(t, out, "search-index.js"), `"url":"20251030/09-00-00-design.md.html"`)
+	assert.Contains(t, read(t, out, "style.css"), ".chroma")
+}
+
+func TestWrite_Filters(t *testing.T) {
+	out := write(t, site.Options{Tag: "proj/alpha", IncludeEncrypted: true})
+
+	index := read(t, out, "index.html")
+	assert.Contains(t, index, "10-00-00-plan.md.html")
+	assert.NotContains(t, index, "09-00-00-design.md.html")
+
+	// The design memo is not exported, so the link to it stays as written.
+	assert.Contains(t, read(t, out, "20251031/10-00-00-plan.md.html"), "[[design#Open Questions|the design]]")
+
+	out = write(t, site.Options{Until: time.Date(2025, 10, 30, 0, 0, 0, 0, time.Local)})
+	_, err := out.ReadFile("20251031/10-00-00-plan.md.html")
+	require.Error(t, err)
+	read(t, out, "20251030/09-00-00-design.md.html")
+}
+
+func TestWrite_FrontMatterTags(t *testing.T) {
+	out := writeFiles(t, map[string]string{
+		"20251031/10-00-00-odd.md": "---\ntags: [c#, what?, 100%, ../../../x, a//b, proj/, café/menu]\n---\nOdd tags.\n",
+	}, site.Options{})
+
+	assert.Contains(t, read(t, out, "index.html"),
+		`<nav class="tags"><a class="tag" href="tags/caf%C3%A9/menu.html">#café/menu</a> <small>1</small> </nav>`,
+		"only valid tags get a page")
+	read(t, out, "tags/café/menu.html")
+
+	memoPage := read(t, out, "20251031/10-00-00-odd.md.html")
+	assert.Contains(t, memoPage, `<a class="tag" href="../tags/caf%C3%A9/menu.html">#café/menu</a>`)
+	assert.Contains(t, memoPage, `<span class="tag">#c#</span>`)
+	assert.Contains(t, memoPage, `<span class="tag">#what?</span>`)
+	assert.Contains(t, memoPage, `<span class="tag">#100%</span>`)
+	assert.Contains(t, memoPage, `<span class="tag">#../../../x</span>`)
+	assert.Contains(t, memoPage, `<span class="tag">#a//b</span>`)
+	assert.Contains(t, memoPage, `<span class="tag">#proj/</span>`)
+}
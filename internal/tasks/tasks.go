@@ -0,0 +1,148 @@
+// Package tasks aggregates Markdown task list items across memos.
+package tasks
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/sushichan044/memo-cli/internal/markdown"
+	"github.com/sushichan044/memo-cli/internal/memo"
+)
+
+// idLength is the number of hex digits of a task ID; plenty for the tasks of one person.
+const idLength = 7
+
+var (
+	// ErrNotFound is returned when no task has the given ID.
+	ErrNotFound = errors.New("task not found")
+	// ErrAmbiguous is returned when an ID prefix matches several tasks.
+	ErrAmbiguous = errors.New("ambiguous task ID")
+)
+
+// Task is a task list item of a memo.
+type Task struct {
+	markdown.Task
+
+	// ID identifies the task by its memo and text, so it survives edits elsewhere in the memo
+	// and toggling its checkbox.
+	ID string
+	// Entry is the memo the task was found in.
+	Entry memo.Entry
+	// MemoTags are the tags of the whole memo.
+	MemoTags []string
+}
+
+// HasTag reports whether the task or its memo is tagged with tag.
+func (t Task) HasTag(tag string) bool {
+	return markdown.HasTag(t.Tags, tag) || markdown.HasTag(t.MemoTags, tag)
+}
+
+// Collect returns the tasks of entries, in the order of entries and then by line.
+// read returns the plaintext content of a memo; returning nil content with a nil error skips the memo.
+func Collect(entries []memo.Entry, read func(memo.Entry) ([]byte, error)) ([]Task, error) {
+	var tasks []Task
+	for _, entry := range entries {
+		content, err := read(entry)
+		if err != nil {
+			return nil, err
+		}
+		if content == nil {
+			continue
+		}
+
+		memoTags := markdown.Tags(content)
+		seen := map[string]int{}
+		for _, item := range markdown.Tasks(content) {
+			id := taskID(entry, item.Text, seen[item.Text])
+			seen[item.Text]++
+			tasks = append(tasks, Task{Task: item, ID: id, Entry: entry, MemoTags: memoTags})
+		}
+	}
+
+	return tasks, nil
+}
+
+// Filter selects tasks. The zero Filter selects open tasks.
+type Filter struct {
+	// Open and Done select tasks by checkbox state. When neither is set, only open tasks are selected.
+	Open bool
+	Done bool
+	// Tag selects tasks tagged, or in a memo tagged, with the tag.
+	Tag string
+	// Since selects tasks of memos created on or after the day of Since.
+	Since time.Time
+}
+
+// Match reports whether t is selected by f.
+func (f Filter) Match(t Task) bool {
+	open, done := f.Open, f.Done
+	if !open && !done {
+		open = true
+	}
+	if (t.Done && !done) || (!t.Done && !open) {
+		return false
+	}
+
+	if f.Tag != "" && !t.HasTag(f.Tag) {
+		return false
+	}
+
+	if !f.Since.IsZero() && t.Entry.DateDir < memo.DateDir(f.Since) {
+		return false
+	}
+
+	return true
+}
+
+// Find returns the task whose ID starts with id.
+func Find(tasks []Task, id string) (Task, error) {
+	var found []Task
+	for _, t := range tasks {
+		if id != "" && strings.HasPrefix(t.ID, strings.ToLower(id)) {
+			found = append(found, t)
+		}
+	}
+
+	switch len(found) {
+	case 0:
+		return Task{}, fmt.Errorf("%w: %q", ErrNotFound, id)
+	case 1:
+		return found[0], nil
+	default:
+		return Task{}, fmt.Errorf("%w: %q matches %d tasks", ErrAmbiguous, id, len(found))
+	}
+}
+
+// Toggle flips the checkbox of t in content, the current content of its memo, and returns the new content.
+// It fails if the line no longer holds the task, so a memo edited since the tasks were collected is never corrupted.
+func Toggle(content []byte, t Task) ([]byte, error) {
+	lines := bytes.SplitAfter(content, []byte("\n"))
+	if t.Line < 1 || t.Line > len(lines) {
+		return nil, fmt.Errorf("%w: %s changed since it was read", ErrNotFound, t.Entry.RelPath)
+	}
+
+	raw := lines[t.Line-1]
+	body := bytes.TrimRight(raw, "\r\n")
+	if string(body) != t.Raw {
+		return nil, fmt.Errorf("%w: %s changed since it was read", ErrNotFound, t.Entry.RelPath)
+	}
+
+	toggled, ok := markdown.ToggleTask(string(body))
+	if !ok {
+		return nil, fmt.Errorf("%w: %s:%d is not a task", ErrNotFound, t.Entry.RelPath, t.Line)
+	}
+	lines[t.Line-1] = append([]byte(toggled), raw[len(body):]...)
+
+	return bytes.Join(lines, nil), nil
+}
+
+// taskID derives a short stable ID from the memo, the task text and its occurrence among identical tasks.
+func taskID(entry memo.Entry, text string, occurrence int) string {
+	sum := sha256.Sum256(fmt.Appendf(nil, "%s\x00%s\x00%d", entry.RelPath, text, occurrence))
+	return hex.EncodeToString(sum[:])[:idLength]
+}
@@ -0,0 +1,98 @@
+package tasks_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/sushichan044/memo-cli/internal/memo"
+	"github.com/sushichan044/memo-cli/internal/memofs"
+	"github.com/sushichan044/memo-cli/internal/tasks"
+)
+
+func collect(t *testing.T, files map[string]string) ([]tasks.Task, *memofs.Mem) {
+	t.Helper()
+
+	fsys := memofs.NewMem("/memo")
+	for name, content := range files {
+		require.NoError(t, fsys.MkdirAll(name[:8], 0o700))
+		require.NoError(t, fsys.WriteFile(name, []byte(content), 0o600))
+	}
+
+	entries, err := memo.List(fsys)
+	require.NoError(t, err)
+
+	collected, err := tasks.Collect(entries, func(e memo.Entry) ([]byte, error) {
+		return fsys.ReadFile(e.RelPath)
+	})
+	require.NoError(t, err)
+
+	return collected, fsys
+}
+
+func TestCollectAndFilter(t *testing.T) {
+	collected, _ := collect(t, map[string]string{
+		"20251030/09-00-00-ops.md":  "---\ntags: [ops]\n---\n- [ ] rotate keys @due(2025-11-01)\n- [x] patch hosts\n",
+		"20251031/10-00-00-todo.md": "- [ ] write report #work\n- [ ] write report #work\n",
+	})
+	require.Len(t, collected, 4)
+
+	// Newest memo first, then by line.
+	assert.Equal(t, "write report #work", collected[0].Text)
+	assert.Equal(t, 1, collected[0].Line)
+	assert.NotEqual(t, collected[0].ID, collected[1].ID, "identical tasks get distinct IDs")
+	assert.Len(t, collected[0].ID, 7)
+
+	ids := func(f tasks.Filter) []string {
+		var texts []string
+		for _, task := range collected {
+			if f.Match(task) {
+				texts = append(texts, task.Text)
+			}
+		}
+		return texts
+	}
+
+	assert.Equal(t, []string{"write report #work", "write report #work", "rotate keys @due(2025-11-01)"}, ids(tasks.Filter{}))
+	assert.Equal(t, []string{"patch hosts"}, ids(tasks.Filter{Done: true}))
+	assert.Len(t, ids(tasks.Filter{Open: true, Done: true}), 4)
+	assert.Equal(t, []string{"rotate keys @due(2025-11-01)"}, ids(tasks.Filter{Tag: "#ops"}))
+	assert.Equal(t, []string{"write report #work", "write report #work"},
+		ids(tasks.Filter{Since: time.Date(2025, 10, 31, 12, 0, 0, 0, time.Local)}))
+
+	assert.Equal(t, time.Date(2025, 11, 1, 0, 0, 0, 0, time.Local), collected[2].Due)
+}
+
+func TestCollect_StableIDs(t *testing.T) {
+	before, _ := collect(t, map[string]string{"20251031/10-00-00.md": "- [ ] a\n- [ ] b\n"})
+	after, _ := collect(t, map[string]string{"20251031/10-00-00.md": "intro\n\n- [x] b\n- [ ] a\n"})
+
+	assert.Equal(t, before[0].ID, after[1].ID)
+	assert.Equal(t, before[1].ID, after[0].ID)
+}
+
+func TestFindAndToggle(t *testing.T) {
+	collected, fsys := collect(t, map[string]string{"20251031/10-00-00.md": "# Todo\r\n- [ ] a\r\n- [x] b\r\n"})
+
+	task, err := tasks.Find(collected, collected[0].ID[:4])
+	require.NoError(t, err)
+	assert.Equal(t, "a", task.Text)
+
+	_, err = tasks.Find(collected, "zzzzzzz")
+	require.ErrorIs(t, err, tasks.ErrNotFound)
+	_, err = tasks.Find(collected, "")
+	require.ErrorIs(t, err, tasks.ErrNotFound)
+
+	content, err := fsys.ReadFile(task.Entry.RelPath)
+	require.NoError(t, err)
+
+	toggled, err := tasks.Toggle(content, task)
+	require.NoError(t, err)
+	assert.Equal(t, "# Todo\r\n- [x] a\r\n- [x] b\r\n", string(toggled))
+
+	// The task is gone from that line once the memo changed.
+	_, err = tasks.Toggle(toggled, task)
+	require.ErrorIs(t, err, tasks.ErrNotFound)
+}
@@ -0,0 +1,414 @@
+// Package transfer moves memos between memo roots in portable formats.
+//
+// Memos are exported as JSON Lines, zip or tar, keeping their YYYYMMDD/HH-MM-SS-name.ext paths and
+// modification times. Content is exported byte for byte, so front matter is preserved and encrypted
+// memos stay encrypted. Import detects the format and skips memos whose content is already present.
+package transfer
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"path"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/sushichan044/memo-cli/internal/crypt"
+	"github.com/sushichan044/memo-cli/internal/markdown"
+	"github.com/sushichan044/memo-cli/internal/memo"
+	"github.com/sushichan044/memo-cli/internal/memofs"
+	"github.com/sushichan044/memo-cli/schema"
+)
+
+// Format is an export format.
+type Format string
+
+const (
+	// FormatJSONL writes one schema.ExportedMemo per line.
+	FormatJSONL Format = "jsonl"
+	// FormatZip writes a zip archive of the date directories.
+	FormatZip Format = "zip"
+	// FormatTar writes an uncompressed tar archive of the date directories.
+	FormatTar Format = "tar"
+)
+
+const (
+	encodingText   = "utf-8"
+	encodingBase64 = "base64"
+
+	// tarMagicOffset is where the "ustar" magic of a tar header starts.
+	tarMagicOffset = 257
+)
+
+// ErrUnknownFormat is returned when the source of an import is not an export of a known format.
+var ErrUnknownFormat = errors.New("unknown import format")
+
+// Item is a memo file in transit.
+type Item struct {
+	// Name is the slash-separated path relative to the memo root (e.g. "20251031/14-30-45-notes.md").
+	Name    string
+	Content []byte
+	ModTime time.Time
+}
+
+// Export writes entries to w in format and returns the number of memos written.
+func Export(fsys memofs.FS, entries []memo.Entry, w io.Writer, format Format) (int, error) {
+	var items []Item
+	for _, entry := range entries {
+		content, err := fsys.ReadFile(entry.RelPath)
+		if err != nil {
+			return 0, fmt.Errorf("failed to read %s: %w", entry.RelPath, err)
+		}
+		info, err := fsys.Stat(entry.RelPath)
+		if err != nil {
+			return 0, fmt.Errorf("failed to read %s: %w", entry.RelPath, err)
+		}
+		items = append(items, Item{Name: entry.RelPath, Content: content, ModTime: info.ModTime()})
+	}
+
+	var err error
+	switch format {
+	case FormatJSONL:
+		err = writeJSONL(w, entries, items)
+	case FormatZip:
+		err = writeZip(w, items)
+	case FormatTar:
+		err = writeTar(w, items)
+	default:
+		return 0, fmt.Errorf("%w: %q", ErrUnknownFormat, format)
+	}
+	if err != nil {
+		return 0, fmt.Errorf("failed to export memos: %w", err)
+	}
+
+	return len(items), nil
+}
+
+func writeJSONL(w io.Writer, entries []memo.Entry, items []Item) error {
+	enc := json.NewEncoder(w)
+	for i, item := range items {
+		doc := schema.ExportedMemo{
+			RelPath:   item.Name,
+			CreatedAt: entries[i].CreatedAt,
+			ModTime:   item.ModTime,
+			Encrypted: entries[i].Encrypted,
+			Tags:      []string{},
+			SHA256:    hash(item.Content),
+			Encoding:  encodingText,
+			Content:   string(item.Content),
+		}
+		if !entries[i].Encrypted {
+			if tags := markdown.Tags(item.Content); tags != nil {
+				doc.Tags = tags
+			}
+		}
+		if !utf8.Valid(item.Content) {
+			doc.Encoding = encodingBase64
+			doc.Content = base64.StdEncoding.EncodeToString(item.Content)
+		}
+		if err := enc.Encode(doc); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func writeZip(w io.Writer, items []Item) error {
+	zw := zip.NewWriter(w)
+	for _, item := range items {
+		header := &zip.FileHeader{Name: item.Name, Method: zip.Deflate, Modified: item.ModTime}
+		header.SetMode(0o600) //nolint:mnd // permissions are reapplied from the config on import
+		f, err := zw.CreateHeader(header)
+		if err != nil {
+			return err
+		}
+		if _, err = f.Write(item.Content); err != nil {
+			return err
+		}
+	}
+	return zw.Close()
+}
+
+func writeTar(w io.Writer, items []Item) error {
+	tw := tar.NewWriter(w)
+	for _, item := range items {
+		header := &tar.Header{
+			Typeflag: tar.TypeReg,
+			Name:     item.Name,
+			Size:     int64(len(item.Content)),
+			Mode:     0o600, //nolint:mnd // permissions are reapplied from the config on import
+			ModTime:  item.ModTime,
+			Format:   tar.FormatPAX,
+		}
+		if err := tw.WriteHeader(header); err != nil {
+			return err
+		}
+		if _, err := tw.Write(item.Content); err != nil {
+			return err
+		}
+	}
+	return tw.Close()
+}
+
+// Read loads the memos of an export, detecting its format: JSON Lines, zip, or tar, optionally gzipped.
+// Directories and other entries that are not regular files are ignored.
+func Read(r io.Reader) ([]Item, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+
+	switch {
+	case bytes.HasPrefix(data, []byte("PK\x03\x04")), bytes.HasPrefix(data, []byte("PK\x05\x06")):
+		return readZip(data)
+	case bytes.HasPrefix(data, []byte{0x1f, 0x8b}):
+		gz, gzErr := gzip.NewReader(bytes.NewReader(data))
+		if gzErr != nil {
+			return nil, fmt.Errorf("%w: %w", ErrUnknownFormat, gzErr)
+		}
+		defer gz.Close()
+		return readTar(gz)
+	case len(data) > tarMagicOffset+5 && string(data[tarMagicOffset:tarMagicOffset+5]) == "ustar":
+		return readTar(bytes.NewReader(data))
+	case bytes.HasPrefix(bytes.TrimSpace(data), []byte("{")):
+		return readJSONL(data)
+	case len(bytes.TrimSpace(data)) == 0:
+		return nil, nil
+	default:
+		return nil, fmt.Errorf("%w: expected JSON Lines, zip or tar", ErrUnknownFormat)
+	}
+}
+
+func readJSONL(data []byte) ([]Item, error) {
+	var items []Item
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	scanner.Buffer(nil, len(data)+1)
+	for line := 1; scanner.Scan(); line++ {
+		if len(bytes.TrimSpace(scanner.Bytes())) == 0 {
+			continue
+		}
+
+		var doc schema.ExportedMemo
+		if err := json.Unmarshal(scanner.Bytes(), &doc); err != nil {
+			return nil, fmt.Errorf("%w: line %d: %w", ErrUnknownFormat, line, err)
+		}
+
+		content := []byte(doc.Content)
+		if doc.Encoding == encodingBase64 {
+			decoded, err := base64.StdEncoding.DecodeString(doc.Content)
+			if err != nil {
+				return nil, fmt.Errorf("%w: line %d: %w", ErrUnknownFormat, line, err)
+			}
+			content = decoded
+		}
+		items = append(items, Item{Name: doc.RelPath, Content: content, ModTime: doc.ModTime})
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	return items, nil
+}
+
+func readZip(data []byte) ([]Item, error) {
+	zr, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrUnknownFormat, err)
+	}
+
+	var items []Item
+	for _, f := range zr.File {
+		if !f.Mode().IsRegular() {
+			continue
+		}
+		rc, openErr := f.Open()
+		if openErr != nil {
+			return nil, fmt.Errorf("failed to read %s: %w", f.Name, openErr)
+		}
+		content, readErr := io.ReadAll(rc)
+		_ = rc.Close()
+		if readErr != nil {
+			return nil, fmt.Errorf("failed to read %s: %w", f.Name, readErr)
+		}
+		items = append(items, Item{Name: f.Name, Content: content, ModTime: f.Modified})
+	}
+
+	return items, nil
+}
+
+func readTar(r io.Reader) ([]Item, error) {
+	var items []Item
+	tr := tar.NewReader(r)
+	for {
+		header, err := tr.Next()
+		if errors.Is(err, io.EOF) {
+			return items, nil
+		}
+		if err != nil {
+			return nil, fmt.Errorf("%w: %w", ErrUnknownFormat, err)
+		}
+		if header.Typeflag != tar.TypeReg {
+			continue
+		}
+		content, err := io.ReadAll(tr)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read %s: %w", header.Name, err)
+		}
+		items = append(items, Item{Name: header.Name, Content: content, ModTime: header.ModTime})
+	}
+}
+
+// ImportOptions customizes Import.
+type ImportOptions struct {
+	// FilePerm and DirPerm are applied to imported memos and created date directories.
+	FilePerm fs.FileMode
+	DirPerm  fs.FileMode
+	// DryRun reports what would be imported without writing anything.
+	DryRun bool
+}
+
+// Skipped is an item Import did not write.
+type Skipped struct {
+	Name   string
+	Reason string
+}
+
+// Renamed is an item Import wrote under another name because its own was taken.
+type Renamed struct {
+	From string
+	To   string
+}
+
+// ImportResult lists what Import did, or would do, with each item.
+type ImportResult struct {
+	// Imported are the names of the items written as is.
+	Imported []string
+	Renamed  []Renamed
+	Skipped  []Skipped
+}
+
+// Import writes items into fsys, keeping their path and modification time.
+// Items whose content is already present anywhere in fsys, or earlier in items, are skipped, as are items
+// whose path does not follow the memo layout or whose file name memo new would refuse. An item whose path is taken by a memo with
+// other content is renamed by adding a counter to its name (e.g. "14-30-45-notes-2.md").
+func Import(fsys memofs.FS, items []Item, opts ImportOptions) (ImportResult, error) {
+	entries, err := memo.List(fsys)
+	if err != nil {
+		return ImportResult{}, err
+	}
+
+	seen := map[string]string{}
+	for _, entry := range entries {
+		content, readErr := fsys.ReadFile(entry.RelPath)
+		if readErr != nil {
+			return ImportResult{}, fmt.Errorf("failed to read %s: %w", entry.RelPath, readErr)
+		}
+		seen[hash(content)] = entry.RelPath
+	}
+	taken := func(name string) bool {
+		_, statErr := fsys.Stat(name)
+		return statErr == nil
+	}
+	written := map[string]bool{}
+
+	var result ImportResult
+	for _, item := range items {
+		entry, reason := parseName(fsys, item.Name)
+		if reason != "" {
+			result.Skipped = append(result.Skipped, Skipped{Name: item.Name, Reason: reason})
+			continue
+		}
+
+		sum := hash(item.Content)
+		if existing, dup := seen[sum]; dup {
+			result.Skipped = append(result.Skipped, Skipped{Name: item.Name, Reason: "duplicate of " + existing})
+			continue
+		}
+
+		name := entry.RelPath
+		for n := 2; taken(name) || written[name]; n++ {
+			name = numberedName(entry, n)
+		}
+		seen[sum] = name
+		written[name] = true
+
+		if name == entry.RelPath {
+			result.Imported = append(result.Imported, name)
+		} else {
+			result.Renamed = append(result.Renamed, Renamed{From: entry.RelPath, To: name})
+		}
+
+		if opts.DryRun {
+			continue
+		}
+		if writeErr := write(fsys, name, item, opts); writeErr != nil {
+			return result, writeErr
+		}
+	}
+
+	return result, nil
+}
+
+func write(fsys memofs.FS, name string, item Item, opts ImportOptions) error {
+	if err := fsys.MkdirAll(path.Dir(name), opts.DirPerm); err != nil {
+		return fmt.Errorf("failed to import %s: %w", name, err)
+	}
+	if err := fsys.WriteFile(name, item.Content, opts.FilePerm); err != nil {
+		return fmt.Errorf("failed to import %s: %w", name, err)
+	}
+	if !item.ModTime.IsZero() {
+		if err := fsys.Chtimes(name, item.ModTime, item.ModTime); err != nil {
+			return fmt.Errorf("failed to import %s: %w", name, err)
+		}
+	}
+	return nil
+}
+
+// parseName checks that name is a memo path directly inside a date directory, with a file name that
+// memo new would accept on every platform. Otherwise it returns why name is skipped.
+func parseName(fsys memofs.FS, name string) (memo.Entry, string) {
+	const notMemo = "not a YYYYMMDD/HH-MM-SS-name.ext memo"
+	if !fs.ValidPath(name) || strings.Count(name, "/") != 1 {
+		return memo.Entry{}, notMemo
+	}
+	if err := memo.ValidateFileName(path.Base(name)); err != nil {
+		return memo.Entry{}, err.Error()
+	}
+	entry, err := memo.NewEntry(fsys, filepath.Join(fsys.Root(), filepath.FromSlash(name)))
+	if err != nil {
+		return memo.Entry{}, notMemo
+	}
+	return entry, ""
+}
+
+// numberedName returns the n-th alternative name for entry. A daily note becomes a regular memo
+// at midnight, since a date directory has only one daily note.
+func numberedName(entry memo.Entry, n int) string {
+	stem := entry.Stem()
+	if entry.Daily {
+		stem = entry.CreatedAt.Format("15-04-05") + "-" + memo.DailyName
+	}
+	name := entry.DateDir + "/" + stem + "-" + strconv.Itoa(n) + "." + entry.Ext
+	if entry.Encrypted {
+		name += crypt.Suffix
+	}
+	return name
+}
+
+func hash(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,137 @@
+package transfer_test
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/sushichan044/memo-cli/internal/memo"
+	"github.com/sushichan044/memo-cli/internal/memofs"
+	"github.com/sushichan044/memo-cli/internal/transfer"
+)
+
+var mtime = time.Date(2025, 10, 31, 18, 0, 0, 0, time.UTC) //nolint:gochecknoglobals // test fixture
+
+func memos(t *testing.T, files map[string]string) *memofs.Mem {
+	t.Helper()
+
+	fsys := memofs.NewMem("/memo")
+	for name, content := range files {
+		require.NoError(t, fsys.MkdirAll(name[:8], 0o700))
+		require.NoError(t, fsys.WriteFile(name, []byte(content), 0o600))
+		require.NoError(t, fsys.Chtimes(name, mtime, mtime))
+	}
+	return fsys
+}
+
+func TestExportImport_RoundTrip(t *testing.T) {
+	src := memos(t, map[string]string{
+		"20251030/09-00-00-design.md":     "---\ntags: [proj]\n---\n# Design #ux\n",
+		"20251031/daily.md":               "# 2025-10-31\n",
+		"20251031/15-00-00-secret.md.age": "\xff\x00binary",
+	})
+	entries, err := memo.List(src)
+	require.NoError(t, err)
+
+	for _, format := range []transfer.Format{transfer.FormatJSONL, transfer.FormatZip, transfer.FormatTar} {
+		t.Run(string(format), func(t *testing.T) {
+			var buf bytes.Buffer
+			count, exportErr := transfer.Export(src, entries, &buf, format)
+			require.NoError(t, exportErr)
+			assert.Equal(t, 3, count)
+
+			items, readErr := transfer.Read(&buf)
+			require.NoError(t, readErr)
+
+			dst := memofs.NewMem("/other")
+			result, importErr := transfer.Import(dst, items, transfer.ImportOptions{FilePerm: 0o600, DirPerm: 0o700})
+			require.NoError(t, importErr)
+			assert.Len(t, result.Imported, 3)
+
+			for _, entry := range entries {
+				want, _ := src.ReadFile(entry.RelPath)
+				got, readFileErr := dst.ReadFile(entry.RelPath)
+				require.NoError(t, readFileErr)
+				assert.Equal(t, want, got)
+
+				info, statErr := dst.Stat(entry.RelPath)
+				require.NoError(t, statErr)
+				assert.True(t, mtime.Equal(info.ModTime()), "mtime of %s is %s", entry.RelPath, info.ModTime())
+			}
+		})
+	}
+}
+
+func TestExport_JSONLTags(t *testing.T) {
+	src := memos(t, map[string]string{"20251030/09-00-00-design.md": "---\ntags: [proj]\n---\n# Design #ux\n"})
+	entries, err := memo.List(src)
+	require.NoError(t, err)
+
+	var buf bytes.Buffer
+	_, err = transfer.Export(src, entries, &buf, transfer.FormatJSONL)
+	require.NoError(t, err)
+
+	assert.Contains(t, buf.String(), `"tags":["proj","ux"]`)
+	assert.Contains(t, buf.String(), `"content":"---\ntags: [proj]\n---\n# Design #ux\n"`)
+}
+
+func TestImport_DedupeAndRename(t *testing.T) {
+	dst := memos(t, map[string]string{
+		"20251030/09-00-00-design.md": "original",
+		"20251031/daily.md":           "today",
+	})
+
+	long := strings.Repeat("x", 256)
+	items := []transfer.Item{
+		{Name: "20251029/08-00-00-copy.md", Content: []byte("original")},
+		{Name: "20251030/09-00-00-design.md", Content: []byte("changed")},
+		{Name: "20251030/09-00-00-design.md", Content: []byte("changed again")},
+		{Name: "20251031/daily.md", Content: []byte("other machine")},
+		{Name: "20251101/10-00-00-new.md", Content: []byte("new")},
+		{Name: "20251101/10-00-00-new-copy.md", Content: []byte("new")},
+		{Name: "notes/readme.md", Content: []byte("x")},
+		{Name: "../20251101/10-00-00-evil.md", Content: []byte("x")},
+		{Name: "20251101/con.md", Content: []byte("device")},
+		{Name: "20251101/10-00-00-" + long + ".md", Content: []byte("long")},
+	}
+
+	dryRun, err := transfer.Import(dst, items, transfer.ImportOptions{DryRun: true})
+	require.NoError(t, err)
+	_, err = dst.Stat("20251101/10-00-00-new.md")
+	require.Error(t, err, "dry run writes nothing")
+
+	result, err := transfer.Import(dst, items, transfer.ImportOptions{FilePerm: 0o600, DirPerm: 0o700})
+	require.NoError(t, err)
+	assert.Equal(t, dryRun, result)
+
+	assert.Equal(t, []string{"20251101/10-00-00-new.md"}, result.Imported)
+	assert.Equal(t, []transfer.Renamed{
+		{From: "20251030/09-00-00-design.md", To: "20251030/09-00-00-design-2.md"},
+		{From: "20251030/09-00-00-design.md", To: "20251030/09-00-00-design-3.md"},
+		{From: "20251031/daily.md", To: "20251031/00-00-00-daily-2.md"},
+	}, result.Renamed)
+	assert.Equal(t, []transfer.Skipped{
+		{Name: "20251029/08-00-00-copy.md", Reason: "duplicate of 20251030/09-00-00-design.md"},
+		{Name: "20251101/10-00-00-new-copy.md", Reason: "duplicate of 20251101/10-00-00-new.md"},
+		{Name: "notes/readme.md", Reason: "not a YYYYMMDD/HH-MM-SS-name.ext memo"},
+		{Name: "../20251101/10-00-00-evil.md", Reason: "not a YYYYMMDD/HH-MM-SS-name.ext memo"},
+		{Name: "20251101/con.md", Reason: `reserved file name: "con.md" is a reserved device name on Windows`},
+		{
+			Name:   "20251101/10-00-00-" + long + ".md",
+			Reason: "file name too long: 268 bytes exceeds the 255 byte limit (use a shorter memo name)",
+		},
+	}, result.Skipped)
+
+	data, err := dst.ReadFile("20251030/09-00-00-design-3.md")
+	require.NoError(t, err)
+	assert.Equal(t, "changed again", string(data))
+}
+
+func TestRead_UnknownFormat(t *testing.T) {
+	_, err := transfer.Read(bytes.NewReader([]byte("just text")))
+	require.ErrorIs(t, err, transfer.ErrUnknownFormat)
+}
@@ -0,0 +1,63 @@
+package tui
+
+import (
+	"strconv"
+	"strings"
+)
+
+// ANSI control sequences.
+const (
+	enterAltScreen = "\x1b[?1049h"
+	exitAltScreen  = "\x1b[?1049l"
+	hideCursor     = "\x1b[?25l"
+	showCursor     = "\x1b[?25h"
+	clearScreen    = "\x1b[2J"
+	resetStyle     = "\x1b[0m"
+	beginSync      = "\x1b[?2026h"
+	endSync        = "\x1b[?2026l"
+)
+
+// sgr returns the escape sequence selecting style.
+func sgr(style Style) string {
+	params := []string{"0"}
+	for _, attr := range []struct {
+		on   bool
+		code string
+	}{
+		{style.Bold, "1"}, {style.Dim, "2"}, {style.Italic, "3"}, {style.Underline, "4"}, {style.Reverse, "7"},
+	} {
+		if attr.on {
+			params = append(params, attr.code)
+		}
+	}
+	switch {
+	case style.FG == ColorGray:
+		params = append(params, "90")
+	case style.FG != ColorDefault:
+		params = append(params, strconv.Itoa(30+int(style.FG)))
+	}
+	return "\x1b[" + strings.Join(params, ";") + "m"
+}
+
+// encodeRow returns the escape sequences drawing row y of canvas, from the first column.
+func encodeRow(canvas *Canvas, y int) string {
+	var b strings.Builder
+	b.WriteString("\x1b[" + strconv.Itoa(y+1) + ";1H")
+
+	current := Style{}
+	b.WriteString(resetStyle)
+	width, _ := canvas.Size()
+	for x := range width {
+		cell := canvas.Cell(x, y)
+		if cell.Rune == 0 {
+			continue
+		}
+		if cell.Style != current {
+			b.WriteString(sgr(cell.Style))
+			current = cell.Style
+		}
+		b.WriteRune(cell.Rune)
+	}
+	b.WriteString(resetStyle)
+	return b.String()
+}
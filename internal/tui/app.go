@@ -0,0 +1,688 @@
+// Package tui implements "memo ui", a full-screen terminal browser of memos: the date directories
+// on the left, the memos of the selected day in the middle and a preview of the selected memo on the right.
+//
+// The UI draws on a Screen: a Terminal in raw mode for users, or a SimScreen for tests.
+package tui
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"regexp"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/sushichan044/memo-cli/internal/crypt"
+	"github.com/sushichan044/memo-cli/internal/hook"
+	"github.com/sushichan044/memo-cli/internal/markdown"
+	"github.com/sushichan044/memo-cli/internal/memo"
+	"github.com/sushichan044/memo-cli/internal/memofs"
+)
+
+// Options configures an App.
+type Options struct {
+	// Creator creates new memos.
+	Creator *memo.Creator
+	// Keyring reads memos for previews and search, and re-encrypts tagged memos.
+	Keyring *crypt.Keyring
+	// FilePerm is applied to tagged memos.
+	FilePerm fs.FileMode
+	// Edit opens a memo in the user's editor and reports whether it changed.
+	// The screen is suspended while it runs.
+	Edit func(entry memo.Entry) (bool, error)
+	// Record is called after a memo was created or changed, with a history message such as
+	// "edit 20251031/14-30-45-notes.md". It may be nil.
+	Record func(message string)
+	// Hooks run the post-edit hook after memos are edited or tagged, and the pre-delete hook before they are deleted.
+	// It may be nil.
+	Hooks *hook.Runner
+	// Warnings collects what Creator, Record and Hooks report while the screen is owned by the UI, such as failed
+	// post-hooks, to show it in the status bar. It may be nil.
+	Warnings *Warnings
+}
+
+// Warnings collects messages that must not be written to the terminal while the UI draws on it.
+// It is an io.Writer, so that it can be the output of a hook.Runner or an output.Printer.
+type Warnings struct {
+	mu   sync.Mutex
+	text strings.Builder
+}
+
+func (w *Warnings) Write(p []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.text.Write(p)
+}
+
+// take returns the collected messages on a single line and forgets them.
+func (w *Warnings) take() string {
+	if w == nil {
+		return ""
+	}
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	text := strings.Join(strings.Fields(w.text.String()), " ")
+	w.text.Reset()
+	return text
+}
+
+type pane int
+
+const (
+	paneDays pane = iota
+	paneMemos
+	panePreview
+)
+
+// treeRow is a row of the date tree: a month header, or a day to select.
+type treeRow struct {
+	label string
+	// day is the date directory of the row; empty for "all memos" and month headers.
+	day    string
+	header bool
+	count  int
+}
+
+// prompt reads a line of text in the status bar.
+type prompt struct {
+	label  string
+	input  []rune
+	submit func(text string) error
+}
+
+// confirmation asks a yes or no question in the status bar.
+type confirmation struct {
+	question string
+	action   func() error
+}
+
+// App is the state of the terminal UI.
+type App struct {
+	fsys   memofs.FS
+	opts   Options
+	screen Screen
+
+	// entries are all memos, newest first.
+	entries []memo.Entry
+	tree    []treeRow
+	// visible are the memos of the selected day that match the search.
+	visible []memo.Entry
+
+	focus      pane
+	treeCursor int
+	treeTop    int
+	listCursor int
+	listTop    int
+	scroll     int
+
+	// query is the search; matches holds the relative paths of the memos matching it.
+	query   string
+	matches map[string]bool
+
+	prompt  *prompt
+	confirm *confirmation
+	help    bool
+	status  string
+	failed  bool
+
+	// previewKey identifies the cached preview, which is rendered for a memo and a width.
+	previewKey   string
+	previewLines []line
+	previewTags  []string
+}
+
+// New returns an App browsing the memos in fsys.
+func New(fsys memofs.FS, opts Options) *App {
+	return &App{fsys: fsys, opts: opts, focus: paneMemos}
+}
+
+// Run shows the UI on screen until the user quits or the screen has no more events.
+func (a *App) Run(screen Screen) error {
+	a.screen = screen
+	if err := a.reload(); err != nil {
+		return err
+	}
+
+	for {
+		width, height := screen.Size()
+		canvas := NewCanvas(width, height)
+		a.draw(canvas)
+		if err := screen.Show(canvas); err != nil {
+			return err
+		}
+
+		ev, ok := screen.PollEvent()
+		if !ok || a.handle(ev) {
+			return nil
+		}
+		if warning := a.opts.Warnings.take(); warning != "" {
+			a.status, a.failed = strings.TrimSpace(a.status+" "+warning), true
+		}
+	}
+}
+
+// reload lists the memos again, keeping the selected day and memo where they still exist.
+func (a *App) reload() error {
+	entries, err := memo.List(a.fsys)
+	if err != nil {
+		return err
+	}
+	a.entries = entries
+	a.previewKey = ""
+
+	day, selected := a.selectedDay(), a.selectedPath()
+	a.buildTree()
+	a.treeCursor = max(slices.IndexFunc(a.tree, func(r treeRow) bool { return !r.header && r.day == day }), 0)
+	if a.query != "" {
+		if err = a.search(a.query); err != nil {
+			return err
+		}
+	}
+	a.filter()
+	if i := slices.IndexFunc(a.visible, func(e memo.Entry) bool { return e.RelPath == selected }); i >= 0 {
+		a.listCursor = i
+	}
+	return nil
+}
+
+// buildTree groups the date directories by month, newest first, after an "all memos" row.
+func (a *App) buildTree() {
+	a.tree = []treeRow{{label: "All memos", count: len(a.entries)}}
+	month := ""
+	for _, entry := range a.entries {
+		if last := &a.tree[len(a.tree)-1]; last.day == entry.DateDir {
+			last.count++
+			continue
+		}
+		if m := entry.CreatedAt.Format("2006-01"); m != month {
+			month = m
+			a.tree = append(a.tree, treeRow{label: entry.CreatedAt.Format("January 2006"), header: true})
+		}
+		a.tree = append(a.tree, treeRow{
+			label: entry.CreatedAt.Format("02 Mon"),
+			day:   entry.DateDir,
+			count: 1,
+		})
+	}
+}
+
+// filter lists the memos of the selected day that match the search.
+func (a *App) filter() {
+	day := a.selectedDay()
+	a.visible = nil
+	for _, entry := range a.entries {
+		if (day == "" || entry.DateDir == day) && (a.matches == nil || a.matches[entry.RelPath]) {
+			a.visible = append(a.visible, entry)
+		}
+	}
+	a.listCursor = min(a.listCursor, max(len(a.visible)-1, 0))
+	a.scroll = 0
+}
+
+func (a *App) selectedDay() string {
+	if a.treeCursor < len(a.tree) {
+		return a.tree[a.treeCursor].day
+	}
+	return ""
+}
+
+func (a *App) selected() *memo.Entry {
+	if a.listCursor < len(a.visible) {
+		return &a.visible[a.listCursor]
+	}
+	return nil
+}
+
+func (a *App) selectedPath() string {
+	if entry := a.selected(); entry != nil {
+		return entry.RelPath
+	}
+	return ""
+}
+
+// selectMemo moves the cursor to the memo at relPath, showing all memos if the selected day does not have it.
+func (a *App) selectMemo(relPath string) {
+	find := func() int {
+		return slices.IndexFunc(a.visible, func(e memo.Entry) bool { return e.RelPath == relPath })
+	}
+	i := find()
+	if i < 0 {
+		a.treeCursor = slices.IndexFunc(a.tree, func(r treeRow) bool { return !r.header && r.day == relPath[:8] })
+		if a.treeCursor < 0 {
+			a.treeCursor = 0
+		}
+		a.filter()
+		i = find()
+	}
+	if i >= 0 {
+		a.listCursor = i
+		a.scroll = 0
+	}
+}
+
+// handle applies an event and reports whether the UI should quit.
+func (a *App) handle(ev Event) bool {
+	key, ok := ev.(KeyEvent)
+	if !ok {
+		// Resizes only need a redraw.
+		return false
+	}
+
+	switch {
+	case a.prompt != nil:
+		a.handlePrompt(key)
+		return false
+	case a.confirm != nil:
+		confirm := a.confirm
+		a.confirm = nil
+		if key.Key == KeyRune && (key.Rune == 'y' || key.Rune == 'Y') {
+			a.report(confirm.action())
+		} else {
+			a.setStatus("Cancelled.")
+		}
+		return false
+	case a.help:
+		a.help = false
+		return false
+	}
+
+	a.status, a.failed = "", false
+	if key.Key == KeyCtrl {
+		switch key.Rune {
+		case 'c':
+			return true
+		case 'd':
+			a.move(a.page())
+		case 'u':
+			a.move(-a.page())
+		case 'l':
+			a.report(a.reload())
+		}
+		return false
+	}
+	if key.Key != KeyRune {
+		a.handleKey(key.Key)
+		return false
+	}
+
+	switch key.Rune {
+	case 'q':
+		return true
+	case 'j':
+		a.move(1)
+	case 'k':
+		a.move(-1)
+	case 'g':
+		a.move(-len(a.entries) - len(a.tree) - len(a.previewLines))
+	case 'G':
+		a.move(len(a.entries) + len(a.tree) + len(a.previewLines))
+	case ' ':
+		a.move(a.page())
+	case 'b':
+		a.move(-a.page())
+	case 'l':
+		a.focus = min(a.focus+1, panePreview)
+	case 'h':
+		a.focus = max(a.focus-1, paneDays)
+	case 'e':
+		a.edit()
+	case 'n':
+		a.ask("New memo name (empty for a timestamp)", "", a.create)
+	case 'r':
+		if entry := a.selected(); entry != nil {
+			a.ask("Rename "+entry.RelPath+" to", entry.Name, a.rename)
+		}
+	case 't':
+		if a.selected() != nil {
+			a.ask("Add tag", "", a.tag)
+		}
+	case 'd':
+		a.askDelete()
+	case '/':
+		a.ask("Search", a.query, a.submitSearch)
+	case 'R':
+		a.report(a.reload())
+	case '?':
+		a.help = true
+	}
+	return false
+}
+
+func (a *App) handleKey(key Key) {
+	switch key {
+	case KeyDown:
+		a.move(1)
+	case KeyUp:
+		a.move(-1)
+	case KeyPgDn:
+		a.move(a.page())
+	case KeyPgUp:
+		a.move(-a.page())
+	case KeyHome:
+		a.handle(KeyEvent{Key: KeyRune, Rune: 'g'})
+	case KeyEnd:
+		a.handle(KeyEvent{Key: KeyRune, Rune: 'G'})
+	case KeyTab, KeyRight:
+		a.focus = (a.focus + 1) % (panePreview + 1)
+	case KeyBacktab, KeyLeft:
+		a.focus = (a.focus + panePreview) % (panePreview + 1)
+	case KeyEnter:
+		if a.focus == paneDays {
+			a.focus = paneMemos
+		} else {
+			a.edit()
+		}
+	case KeyDelete:
+		a.askDelete()
+	case KeyEsc:
+		if a.query != "" {
+			a.query, a.matches = "", nil
+			a.keepSelection(a.filter)
+			a.setStatus("Search cleared.")
+		}
+	default:
+	}
+}
+
+func (a *App) handlePrompt(key KeyEvent) {
+	p := a.prompt
+	switch key.Key {
+	case KeyRune:
+		p.input = append(p.input, key.Rune)
+	case KeyBackspace:
+		if len(p.input) > 0 {
+			p.input = p.input[:len(p.input)-1]
+		}
+	case KeyCtrl:
+		switch key.Rune {
+		case 'u':
+			p.input = nil
+		case 'c':
+			a.prompt = nil
+			a.setStatus("Cancelled.")
+		}
+	case KeyEsc:
+		a.prompt = nil
+		a.setStatus("Cancelled.")
+	case KeyEnter:
+		a.prompt = nil
+		a.report(p.submit(strings.TrimSpace(string(p.input))))
+	default:
+	}
+}
+
+// move moves the cursor of the focused pane, or scrolls the preview.
+func (a *App) move(delta int) {
+	switch a.focus {
+	case paneDays:
+		cursor := a.treeCursor
+		for range abs(delta) {
+			next := cursor + sign(delta)
+			for next >= 0 && next < len(a.tree) && a.tree[next].header {
+				next += sign(delta)
+			}
+			if next < 0 || next >= len(a.tree) {
+				break
+			}
+			cursor = next
+		}
+		if cursor != a.treeCursor {
+			a.treeCursor = cursor
+			a.listCursor = 0
+			a.filter()
+		}
+	case paneMemos:
+		cursor := min(max(a.listCursor+delta, 0), max(len(a.visible)-1, 0))
+		if cursor != a.listCursor {
+			a.listCursor = cursor
+			a.scroll = 0
+		}
+	case panePreview:
+		a.scroll = min(max(a.scroll+delta, 0), max(len(a.previewLines)-1, 0))
+	}
+}
+
+// page is the number of rows moved by page up and down: the height of the panes.
+func (a *App) page() int {
+	_, height := a.screen.Size()
+	return max(height-4, 1) //nolint:mnd // title bar, status bar and pane titles
+}
+
+func (a *App) ask(label, initial string, submit func(string) error) {
+	a.prompt = &prompt{label: label, input: []rune(initial), submit: submit}
+}
+
+func (a *App) askDelete() {
+	entry := a.selected()
+	if entry == nil {
+		return
+	}
+	a.confirm = &confirmation{
+		question: "Delete " + entry.RelPath + "?",
+		action: func() error {
+			if err := a.opts.Hooks.Run(hook.PreDelete, entry.Schema()); err != nil {
+				return err
+			}
+			if err := memo.Delete(a.fsys, *entry); err != nil {
+				return err
+			}
+			a.record("rm %s", entry.RelPath)
+			a.setStatus("Deleted " + entry.RelPath + ".")
+			return a.reload()
+		},
+	}
+}
+
+func (a *App) create(name string) error {
+	path, err := a.opts.Creator.Create(name, "md")
+	if err != nil {
+		return err
+	}
+	entry, err := memo.NewEntry(a.fsys, path)
+	if err != nil {
+		return err
+	}
+	a.record("new %s", entry.RelPath)
+
+	if err = a.reload(); err != nil {
+		return err
+	}
+	a.selectMemo(entry.RelPath)
+	a.focus = paneMemos
+	a.edit()
+	if !a.failed {
+		a.setStatus("Created " + entry.RelPath + ".")
+	}
+	return nil
+}
+
+// edit suspends the screen and opens the selected memo in the editor.
+func (a *App) edit() {
+	entry := a.selected()
+	if entry == nil {
+		return
+	}
+
+	if err := a.screen.Suspend(); err != nil {
+		a.report(err)
+		return
+	}
+	changed, err := a.opts.Edit(*entry)
+	if resumeErr := a.screen.Resume(); err == nil {
+		err = resumeErr
+	}
+	if err != nil {
+		a.report(err)
+		return
+	}
+
+	if !changed {
+		a.setStatus("No changes to " + entry.RelPath + ".")
+		return
+	}
+	_ = a.opts.Hooks.Run(hook.PostEdit, entry.Schema())
+	a.record("edit %s", entry.RelPath)
+	a.setStatus("Saved " + entry.RelPath + ".")
+	a.report(a.reload())
+}
+
+func (a *App) rename(name string) error {
+	entry := a.selected()
+	if entry == nil || name == entry.Name {
+		return nil
+	}
+	renamed, err := memo.Rename(a.fsys, *entry, name)
+	if err != nil {
+		return err
+	}
+	a.record("mv %s -> %s", entry.RelPath, renamed.RelPath)
+
+	if err = a.reload(); err != nil {
+		return err
+	}
+	a.selectMemo(renamed.RelPath)
+	a.setStatus("Renamed to " + renamed.RelPath + ".")
+	return nil
+}
+
+func (a *App) tag(tag string) error {
+	entry := a.selected()
+	if entry == nil || tag == "" {
+		return nil
+	}
+	added, err := memo.AddTag(a.fsys, a.opts.Keyring, *entry, tag, a.opts.FilePerm)
+	if err != nil {
+		return err
+	}
+	if !added {
+		a.setStatus(entry.RelPath + " is already tagged #" + strings.TrimPrefix(tag, "#") + ".")
+		return nil
+	}
+	_ = a.opts.Hooks.Run(hook.PostEdit, entry.Schema())
+	a.record("tag %s", entry.RelPath)
+	a.setStatus("Tagged " + entry.RelPath + " #" + strings.TrimPrefix(tag, "#") + ".")
+	return a.reload()
+}
+
+// submitSearch searches all memos, showing the matches of every day. An empty query clears the search.
+func (a *App) submitSearch(query string) error {
+	if query == "" {
+		a.query, a.matches = "", nil
+		a.keepSelection(a.filter)
+		return nil
+	}
+	if err := a.search(query); err != nil {
+		return err
+	}
+	a.query = query
+	a.treeCursor = 0
+	a.listCursor = 0
+	a.filter()
+	a.focus = paneMemos
+	a.setStatus(fmt.Sprintf("%d memo(s) match %q.", len(a.matches), query))
+	return nil
+}
+
+// search finds the memos whose name or content matches query, case-insensitively.
+// Queries that are not valid regular expressions are searched for literally.
+func (a *App) search(query string) error {
+	re, err := regexp.Compile("(?i)" + query)
+	if err != nil {
+		re = regexp.MustCompile("(?i)" + regexp.QuoteMeta(query))
+	}
+
+	found, err := memo.Search(a.entries, re, a.readable)
+	if err != nil {
+		return err
+	}
+	a.matches = map[string]bool{}
+	for _, m := range found {
+		a.matches[m.Entry.RelPath] = true
+	}
+	for _, entry := range a.entries {
+		if re.MatchString(entry.Title()) {
+			a.matches[entry.RelPath] = true
+		}
+	}
+	return nil
+}
+
+// keepSelection runs change, then moves the cursor back to the memo selected before if it is still listed.
+func (a *App) keepSelection(change func()) {
+	selected := a.selectedPath()
+	change()
+	if selected != "" {
+		if i := slices.IndexFunc(a.visible, func(e memo.Entry) bool { return e.RelPath == selected }); i >= 0 {
+			a.listCursor = i
+		}
+	}
+}
+
+// readable reads a memo for previews and search. Encrypted memos that cannot be decrypted read as nil.
+func (a *App) readable(entry memo.Entry) ([]byte, error) {
+	content, err := a.opts.Keyring.ReadFile(a.fsys, entry.RelPath)
+	if errors.Is(err, crypt.ErrNoIdentity) {
+		return nil, nil
+	}
+	return content, err
+}
+
+// preview renders the selected memo for a pane width columns wide, caching the result.
+func (a *App) preview(width int) ([]line, []string, error) {
+	entry := a.selected()
+	if entry == nil {
+		return nil, nil, nil
+	}
+	key := fmt.Sprintf("%s@%d", entry.RelPath, width)
+	if key == a.previewKey {
+		return a.previewLines, a.previewTags, nil
+	}
+
+	content, err := a.readable(*entry)
+	if err != nil {
+		return nil, nil, err
+	}
+	switch {
+	case content == nil && entry.Encrypted:
+		a.previewLines = []line{{{text: "Encrypted, and no identity is configured to decrypt it.", style: mutedStyle}}}
+	case entry.Ext == "md" || entry.Ext == "markdown":
+		a.previewLines = renderMarkdown(content, width)
+	default:
+		a.previewLines = renderPlain(content, width)
+	}
+	a.previewTags = markdown.Tags(content)
+	a.previewKey = key
+	return a.previewLines, a.previewTags, nil
+}
+
+func (a *App) setStatus(message string) {
+	a.status, a.failed = message, false
+}
+
+// report shows err in the status bar, if any.
+func (a *App) report(err error) {
+	if err != nil {
+		a.status, a.failed = err.Error(), true
+	}
+}
+
+func (a *App) record(format string, args ...any) {
+	if a.opts.Record != nil {
+		a.opts.Record(fmt.Sprintf(format, args...))
+	}
+}
+
+func abs(n int) int {
+	if n < 0 {
+		return -n
+	}
+	return n
+}
+
+func sign(n int) int {
+	if n < 0 {
+		return -1
+	}
+	return 1
+}
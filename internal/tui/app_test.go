@@ -0,0 +1,266 @@
+package tui_test
+
+import (
+	"fmt"
+	"io"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/sushichan044/memo-cli/internal/config"
+	"github.com/sushichan044/memo-cli/internal/crypt"
+	"github.com/sushichan044/memo-cli/internal/hook"
+	"github.com/sushichan044/memo-cli/internal/memo"
+	"github.com/sushichan044/memo-cli/internal/memofs"
+	"github.com/sushichan044/memo-cli/internal/tui"
+)
+
+var memos = map[string]string{
+	"20251030/09-00-00-plan.md":  "# Plan\n\n- [ ] ship it\n\nSee [[notes]]. #work\n",
+	"20251030/daily.md":          "Standup at 10.\n",
+	"20251031/14-30-45-notes.md": "---\ntags: [meeting]\n---\n# Notes\n\nDiscussed the **roadmap**.\n",
+	"20251031/08-00-00.txt":      "plain text\n",
+}
+
+type fixture struct {
+	fsys     *memofs.Mem
+	app      *tui.App
+	screen   *tui.SimScreen
+	edited   []string
+	recorded []string
+}
+
+func setup(t *testing.T, width, height int) *fixture {
+	t.Helper()
+
+	fsys := memofs.NewMem("/memo")
+	for name, content := range memos {
+		require.NoError(t, fsys.MkdirAll(name[:8], 0o700))
+		require.NoError(t, fsys.WriteFile(name, []byte(content), 0o600))
+	}
+	cfg := &config.Config{BaseDir: "/memo"}
+
+	f := &fixture{fsys: fsys, screen: tui.NewSimScreen(width, height)}
+	f.app = tui.New(fsys, tui.Options{
+		Creator:  memo.NewWithFS(cfg, fsys),
+		Keyring:  crypt.NewKeyring(cfg.Encryption),
+		FilePerm: 0o600,
+		Edit: func(entry memo.Entry) (bool, error) {
+			assert.True(t, f.screen.Suspended(), "the editor runs with the screen suspended")
+			f.edited = append(f.edited, entry.RelPath)
+			return true, fsys.WriteFile(entry.RelPath, []byte("edited\n"), 0o600)
+		},
+		Record: func(message string) { f.recorded = append(f.recorded, message) },
+	})
+	return f
+}
+
+// run types input and runs the UI until it is consumed.
+func (f *fixture) run(t *testing.T, input string) string {
+	t.Helper()
+	f.screen.Type(input)
+	require.NoError(t, f.app.Run(f.screen))
+	return f.screen.Text()
+}
+
+func TestApp_Panes(t *testing.T) {
+	f := setup(t, 100, 20)
+	text := f.run(t, "")
+
+	assert.Contains(t, text, "October 2025")
+	assert.Contains(t, text, "31 Fri")
+	assert.Contains(t, text, "30 Thu")
+	assert.Contains(t, text, "Memos (4)")
+	assert.Contains(t, text, "10-31 14:30 notes")
+	assert.Contains(t, text, "10-30 daily daily")
+	// The newest memo is previewed, rendered.
+	assert.Contains(t, text, "#meeting")
+	assert.Contains(t, text, "Discussed the roadmap.")
+	assert.NotContains(t, text, "**")
+}
+
+func TestApp_Navigation(t *testing.T) {
+	f := setup(t, 100, 20)
+
+	// Select the 30th in the date tree: only its memos are listed.
+	text := f.run(t, "\t\tjj")
+	assert.Contains(t, text, "Memos (2)")
+	assert.Contains(t, text, "09:00 plan")
+	assert.NotContains(t, text, "14:30 notes")
+	assert.Contains(t, text, "[ ] ship it")
+	assert.Contains(t, text, "See notes. #work")
+
+	// Enter goes to the memo list, where moving down previews the daily note.
+	text = f.run(t, "\rj")
+	assert.Contains(t, text, "Standup at 10.")
+}
+
+func TestApp_Resize(t *testing.T) {
+	f := setup(t, 100, 20)
+	f.screen.Inject(tui.ResizeEvent{Width: 50, Height: 12})
+	text := f.run(t, "")
+
+	lines := strings.Split(strings.TrimRight(text, "\n"), "\n")
+	assert.Len(t, lines, 12)
+	for _, l := range lines {
+		assert.LessOrEqual(t, len([]rune(l)), 50)
+	}
+	// Narrow screens show the focused pane only.
+	assert.Contains(t, text, "Memos (4)")
+	assert.NotContains(t, text, "Days")
+	assert.NotContains(t, text, "Preview")
+
+	text = f.run(t, "l")
+	assert.Contains(t, text, "Preview")
+	assert.Contains(t, text, "Discussed the roadmap.")
+
+	f.screen.Inject(tui.ResizeEvent{Width: 10, Height: 3})
+	assert.Contains(t, f.run(t, ""), "Window")
+}
+
+func TestApp_Edit(t *testing.T) {
+	f := setup(t, 100, 20)
+	text := f.run(t, "\r")
+
+	assert.Equal(t, []string{"20251031/14-30-45-notes.md"}, f.edited)
+	assert.Equal(t, []string{"edit 20251031/14-30-45-notes.md"}, f.recorded)
+	assert.False(t, f.screen.Suspended())
+	assert.Contains(t, text, "Saved 20251031/14-30-45-notes.md.")
+	assert.Contains(t, text, "edited")
+}
+
+func TestApp_Create(t *testing.T) {
+	f := setup(t, 100, 20)
+	text := f.run(t, "nidea\r")
+
+	require.Len(t, f.edited, 1)
+	assert.Contains(t, f.edited[0], "-idea.md")
+	assert.Equal(t, []string{"new " + f.edited[0], "edit " + f.edited[0]}, f.recorded)
+	assert.Contains(t, text, "Memos (5)")
+	assert.Contains(t, text, "Created "+f.edited[0]+".")
+}
+
+func TestApp_Rename(t *testing.T) {
+	f := setup(t, 100, 20)
+	text := f.run(t, "r\x15minutes\r")
+
+	assert.Equal(t, []string{"mv 20251031/14-30-45-notes.md -> 20251031/14-30-45-minutes.md"}, f.recorded)
+	assert.Contains(t, text, "14:30 minutes")
+	_, err := f.fsys.Stat("20251031/14-30-45-minutes.md")
+	require.NoError(t, err)
+
+	// Esc cancels a prompt.
+	text = f.run(t, "rx\x1b")
+	assert.Contains(t, text, "Cancelled.")
+	assert.Len(t, f.recorded, 1)
+}
+
+func TestApp_Tag(t *testing.T) {
+	f := setup(t, 100, 20)
+	text := f.run(t, "tideas\r")
+
+	assert.Equal(t, []string{"tag 20251031/14-30-45-notes.md"}, f.recorded)
+	assert.Contains(t, text, "#ideas #meeting")
+	content, err := f.fsys.ReadFile("20251031/14-30-45-notes.md")
+	require.NoError(t, err)
+	assert.Contains(t, string(content), "tags: [meeting, ideas]")
+
+	text = f.run(t, "tIdeas\r")
+	assert.Contains(t, text, "already tagged")
+	assert.Len(t, f.recorded, 1)
+}
+
+func TestApp_Delete(t *testing.T) {
+	f := setup(t, 100, 20)
+
+	text := f.run(t, "dn")
+	assert.Contains(t, text, "Cancelled.")
+	assert.Contains(t, text, "Memos (4)")
+
+	text = f.run(t, "dy")
+	assert.Equal(t, []string{"rm 20251031/14-30-45-notes.md"}, f.recorded)
+	assert.Contains(t, text, "Memos (3)")
+	assert.NotContains(t, text, "14:30 notes")
+}
+
+func TestApp_DeleteHook(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("hooks are tested with sh")
+	}
+	f := setup(t, 100, 20)
+	cfg := &config.Config{Hooks: config.Hooks{PreDelete: config.Hook{Shell: "echo keep $MEMO_NAME; exit 1"}}}
+	f.app = tui.New(f.fsys, tui.Options{
+		Keyring: crypt.NewKeyring(cfg.Encryption),
+		Hooks:   hook.New(cfg, "/memo", io.Discard),
+	})
+
+	text := f.run(t, "dy")
+	assert.Contains(t, text, "keep notes")
+	assert.Contains(t, text, "Memos (4)")
+	_, err := f.fsys.Stat("20251031/14-30-45-notes.md")
+	require.NoError(t, err)
+}
+
+func TestApp_Warnings(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("hooks are tested with sh")
+	}
+	f := setup(t, 220, 20)
+	cfg := &config.Config{Hooks: config.Hooks{PostEdit: config.Hook{Shell: "echo cannot format; exit 1"}}}
+	warnings := &tui.Warnings{}
+	f.app = tui.New(f.fsys, tui.Options{
+		Keyring: crypt.NewKeyring(cfg.Encryption),
+		Edit: func(entry memo.Entry) (bool, error) {
+			return true, f.fsys.WriteFile(entry.RelPath, []byte("edited\n"), 0o600)
+		},
+		Record:   func(message string) { fmt.Fprintf(warnings, "no history for %q\n", message) },
+		Hooks:    hook.New(cfg, "/memo", warnings),
+		Warnings: warnings,
+	})
+
+	text := f.run(t, "\r")
+	assert.Contains(t, text, "Saved 20251031/14-30-45-notes.md. ⚠ Warning: hook failed: post-edit hook for 20251031/14-30-45-notes.md")
+	assert.Contains(t, text, "cannot format")
+	assert.Contains(t, text, `no history for "edit 20251031/14-30-45-notes.md"`)
+
+	// Warnings are shown once.
+	text = f.run(t, "j")
+	assert.NotContains(t, text, "cannot format")
+}
+
+func TestApp_Search(t *testing.T) {
+	f := setup(t, 100, 20)
+
+	text := f.run(t, "/standup\r")
+	assert.Contains(t, text, "search: standup (1)")
+	assert.Contains(t, text, "Memos (1)")
+	assert.Contains(t, text, "Standup at 10.")
+
+	// Names match too, and invalid regular expressions are searched literally.
+	text = f.run(t, "/\x15pla(\r")
+	assert.Contains(t, text, "Memos (0)")
+	text = f.run(t, "/\x15PLAN\r")
+	assert.Contains(t, text, "09:00 plan")
+
+	text = f.run(t, "\x1b")
+	assert.Contains(t, text, "Memos (4)")
+	assert.Contains(t, text, "Search cleared.")
+}
+
+func TestApp_Help(t *testing.T) {
+	f := setup(t, 100, 24)
+
+	assert.Contains(t, f.run(t, "?"), "switch pane")
+	assert.NotContains(t, f.run(t, "x"), "switch pane")
+}
+
+func TestApp_Quit(t *testing.T) {
+	f := setup(t, 100, 20)
+	f.screen.Type("qj")
+	require.NoError(t, f.app.Run(f.screen))
+
+	assert.Equal(t, 1, f.screen.Frames, "keys after q are left unread")
+}
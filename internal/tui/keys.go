@@ -0,0 +1,81 @@
+package tui
+
+import (
+	"strings"
+	"unicode/utf8"
+)
+
+// escapeKeys maps the escape sequences of xterm-compatible terminals to keys, with both
+// the normal ("ESC [") and application ("ESC O") cursor key modes.
+//
+//nolint:gochecknoglobals // constant table
+var escapeKeys = map[string]Key{
+	"[A": KeyUp, "[B": KeyDown, "[C": KeyRight, "[D": KeyLeft,
+	"OA": KeyUp, "OB": KeyDown, "OC": KeyRight, "OD": KeyLeft,
+	"[H": KeyHome, "[F": KeyEnd, "OH": KeyHome, "OF": KeyEnd,
+	"[1~": KeyHome, "[4~": KeyEnd, "[7~": KeyHome, "[8~": KeyEnd,
+	"[3~": KeyDelete, "[5~": KeyPgUp, "[6~": KeyPgDn, "[Z": KeyBacktab,
+}
+
+// ParseKeys decodes terminal input into key events. A lone ESC is the Esc key;
+// unknown escape sequences are dropped.
+func ParseKeys(input []byte) []KeyEvent {
+	var keys []KeyEvent
+	for len(input) > 0 {
+		switch b := input[0]; {
+		case b == 0x1b:
+			n, key, ok := parseEscape(input[1:])
+			if ok {
+				keys = append(keys, KeyEvent{Key: key})
+			}
+			input = input[1+n:]
+			continue
+		case b == '\r' || b == '\n':
+			keys = append(keys, KeyEvent{Key: KeyEnter})
+		case b == '\t':
+			keys = append(keys, KeyEvent{Key: KeyTab})
+		case b == 0x7f || b == 0x08:
+			keys = append(keys, KeyEvent{Key: KeyBackspace})
+		case b >= 1 && b <= 26:
+			keys = append(keys, KeyEvent{Key: KeyCtrl, Rune: rune('a' + b - 1)})
+		case b < ' ':
+			// Other control characters, such as Ctrl-\ and NUL, have no use.
+		default:
+			r, size := utf8.DecodeRune(input)
+			keys = append(keys, KeyEvent{Key: KeyRune, Rune: r})
+			input = input[size:]
+			continue
+		}
+		input = input[1:]
+	}
+	return keys
+}
+
+// parseEscape decodes the escape sequence following an ESC byte, returning its length.
+// An ESC that does not start a sequence is the Esc key.
+func parseEscape(seq []byte) (int, Key, bool) {
+	if len(seq) == 0 || (seq[0] != '[' && seq[0] != 'O') {
+		return 0, KeyEsc, true
+	}
+
+	// A sequence ends with its first byte in the 0x40-0x7e range after the introducer.
+	for i := 1; i < len(seq); i++ {
+		if seq[i] >= 0x40 && seq[i] <= 0x7e {
+			key, ok := escapeKeys[normalizeEscape(string(seq[:i+1]))]
+			return i + 1, key, ok
+		}
+	}
+	return len(seq), 0, false
+}
+
+// normalizeEscape drops the modifier parameter of sequences such as "[1;5A" (Ctrl-Up).
+func normalizeEscape(seq string) string {
+	if i := strings.IndexByte(seq, ';'); i >= 0 {
+		end := len(seq) - 1
+		if seq[1:i] == "1" {
+			return seq[:1] + seq[end:]
+		}
+		return seq[:i] + seq[end:]
+	}
+	return seq
+}
@@ -0,0 +1,52 @@
+package tui_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/sushichan044/memo-cli/internal/tui"
+)
+
+func TestParseKeys(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  []tui.KeyEvent
+	}{
+		{
+			name:  "characters",
+			input: "aé日",
+			want:  []tui.KeyEvent{{Rune: 'a'}, {Rune: 'é'}, {Rune: '日'}},
+		},
+		{
+			name:  "control keys",
+			input: "\r\t\x7f\x03",
+			want: []tui.KeyEvent{
+				{Key: tui.KeyEnter}, {Key: tui.KeyTab}, {Key: tui.KeyBackspace}, {Key: tui.KeyCtrl, Rune: 'c'},
+			},
+		},
+		{
+			name:  "escape sequences",
+			input: "\x1b[A\x1bOB\x1b[5~\x1b[1;5C\x1b[Z\x1b[3~",
+			want: []tui.KeyEvent{
+				{Key: tui.KeyUp}, {Key: tui.KeyDown}, {Key: tui.KeyPgUp}, {Key: tui.KeyRight}, {Key: tui.KeyBacktab}, {Key: tui.KeyDelete},
+			},
+		},
+		{
+			name:  "lone escape",
+			input: "\x1bq",
+			want:  []tui.KeyEvent{{Key: tui.KeyEsc}, {Rune: 'q'}},
+		},
+		{
+			name:  "unknown sequence",
+			input: "\x1b[99Xj",
+			want:  []tui.KeyEvent{{Rune: 'j'}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, tui.ParseKeys([]byte(tt.input)))
+		})
+	}
+}
@@ -0,0 +1,226 @@
+package tui
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/sushichan044/memo-cli/internal/markdown"
+)
+
+// span is a run of text in one style.
+type span struct {
+	text  string
+	style Style
+}
+
+// line is a line of styled text.
+type line []span
+
+// styledRune is a character with its style, the unit lines are wrapped in.
+type styledRune struct {
+	r     rune
+	style Style
+}
+
+var (
+	headingPattern = regexp.MustCompile(`^(#{1,6})\s+(.*?)\s*#*\s*$`)
+	taskPattern    = regexp.MustCompile(`^(\s*)[-*+] \[([ xX])\] (.*)$`)
+	bulletPattern  = regexp.MustCompile(`^(\s*)[-*+] (.*)$`)
+	orderedPattern = regexp.MustCompile(`^(\s*)(\d+[.)]) (.*)$`)
+	rulePattern    = regexp.MustCompile(`^\s*([-*_])(\s*[-*_]){2,}\s*$`)
+	// inlinePattern matches the inline elements styled in previews, in order of precedence:
+	// code spans, wiki-links, Markdown links, bold, italic and #tags.
+	inlinePattern = regexp.MustCompile("`[^`]+`" +
+		`|\[\[[^\[\]]+\]\]` +
+		`|\[[^\[\]]+\]\([^()\s]+\)` +
+		`|\*\*[^*]+\*\*|__[^_]+__` +
+		`|\*[^*\s][^*]*\*` +
+		`|(?:^|[\s(])(#\pL[\pL\pN_/-]*)`)
+)
+
+//nolint:gochecknoglobals // constant styles
+var (
+	headingStyle = Style{FG: ColorCyan, Bold: true}
+	codeStyle    = Style{FG: ColorYellow}
+	linkStyle    = Style{FG: ColorCyan, Underline: true}
+	tagStyle     = Style{FG: ColorMagenta}
+	mutedStyle   = Style{FG: ColorGray}
+	doneStyle    = Style{FG: ColorGreen, Dim: true}
+)
+
+// renderMarkdown renders memo content for a preview width columns wide.
+// It follows the line-based reading of the markdown package: block elements are recognized
+// by their first characters, and front matter is left out as the tags it declares are shown separately.
+func renderMarkdown(content []byte, width int) []line {
+	var lines []line
+	fence := ""
+	for raw := range strings.SplitSeq(strings.TrimRight(string(markdown.Body(content)), "\n"), "\n") {
+		text := strings.TrimSuffix(strings.ReplaceAll(raw, "\t", "    "), "\r")
+		trimmed := strings.TrimLeft(text, " ")
+
+		if marker := markdown.FenceMarker(trimmed); marker != "" && (fence == "" || strings.TrimSpace(trimmed) == fence) {
+			if fence == "" {
+				fence = marker
+			} else {
+				fence = ""
+			}
+			continue
+		}
+		if fence != "" {
+			lines = append(lines, wrapLine(line{{text: "  " + text, style: codeStyle}}, width)...)
+			continue
+		}
+
+		lines = append(lines, wrapLine(renderBlock(text, width), width)...)
+	}
+	return lines
+}
+
+// renderPlain renders content that is not Markdown, wrapping long lines.
+func renderPlain(content []byte, width int) []line {
+	var lines []line
+	for raw := range strings.SplitSeq(strings.TrimRight(string(content), "\n"), "\n") {
+		text := strings.TrimSuffix(strings.ReplaceAll(raw, "\t", "    "), "\r")
+		lines = append(lines, wrapLine(line{{text: text}}, width)...)
+	}
+	return lines
+}
+
+func renderBlock(text string, width int) line {
+	if m := headingPattern.FindStringSubmatch(text); m != nil {
+		style := headingStyle
+		style.Underline = len(m[1]) == 1
+		return line{{text: m[2], style: style}}
+	}
+	if rulePattern.MatchString(text) {
+		return line{{text: strings.Repeat("─", width), style: mutedStyle}}
+	}
+	if m := taskPattern.FindStringSubmatch(text); m != nil {
+		if m[2] == " " {
+			return append(line{{text: m[1] + "[ ] "}}, renderInline(m[3], Style{})...)
+		}
+		return line{{text: m[1] + "[x] " + m[3], style: doneStyle}}
+	}
+	if m := bulletPattern.FindStringSubmatch(text); m != nil {
+		return append(line{{text: m[1] + "• "}}, renderInline(m[2], Style{})...)
+	}
+	if m := orderedPattern.FindStringSubmatch(text); m != nil {
+		return append(line{{text: m[1] + m[2] + " "}}, renderInline(m[3], Style{})...)
+	}
+	if quoted, ok := strings.CutPrefix(strings.TrimLeft(text, " "), ">"); ok {
+		quoteStyle := Style{FG: ColorGray, Italic: true}
+		return append(line{{text: "│ ", style: mutedStyle}}, renderInline(strings.TrimPrefix(quoted, " "), quoteStyle)...)
+	}
+	return renderInline(text, Style{})
+}
+
+// renderInline styles the inline elements of text, on top of base.
+func renderInline(text string, base Style) line {
+	var l line
+	last := 0
+	for _, m := range inlinePattern.FindAllStringSubmatchIndex(text, -1) {
+		start, end := m[0], m[1]
+		// A #tag match includes the character before the '#'.
+		if m[2] >= 0 {
+			start = m[2]
+		}
+		element := text[start:end]
+		if start > last {
+			l = append(l, span{text: text[last:start], style: base})
+		}
+		l = append(l, inlineSpan(element, base))
+		last = end
+	}
+	if last < len(text) {
+		l = append(l, span{text: text[last:], style: base})
+	}
+	return l
+}
+
+func inlineSpan(element string, base Style) span {
+	style := base
+	switch {
+	case strings.HasPrefix(element, "`"):
+		style.FG = codeStyle.FG
+		return span{text: strings.Trim(element, "`"), style: style}
+	case strings.HasPrefix(element, "[["):
+		target := strings.TrimSuffix(strings.TrimPrefix(element, "[["), "]]")
+		if _, alias, ok := strings.Cut(target, "|"); ok {
+			target = alias
+		}
+		style.FG, style.Underline = linkStyle.FG, true
+		return span{text: target, style: style}
+	case strings.HasPrefix(element, "["):
+		text, _, _ := strings.Cut(strings.TrimPrefix(element, "["), "](")
+		style.FG, style.Underline = ColorBlue, true
+		return span{text: text, style: style}
+	case strings.HasPrefix(element, "**"), strings.HasPrefix(element, "__"):
+		style.Bold = true
+		return span{text: element[2 : len(element)-2], style: style}
+	case strings.HasPrefix(element, "*"):
+		style.Italic = true
+		return span{text: element[1 : len(element)-1], style: style}
+	default:
+		style.FG = tagStyle.FG
+		return span{text: element, style: style}
+	}
+}
+
+// wrapLine breaks l into lines of at most width columns, at spaces where possible.
+func wrapLine(l line, width int) []line {
+	if width <= 0 {
+		return nil
+	}
+
+	var runes []styledRune
+	for _, s := range l {
+		for _, r := range s.text {
+			runes = append(runes, styledRune{r: r, style: s.style})
+		}
+	}
+
+	var (
+		lines []line
+		used  int
+		start int
+		// breakAt is the index in runes after the last space of the current line, where it can break.
+		breakAt = -1
+	)
+	for i, sr := range runes {
+		w := runeWidth(sr.r)
+		if used+w > width && i > start {
+			end := i
+			if breakAt > start {
+				end = breakAt
+			}
+			lines = append(lines, joinRunes(runes[start:end]))
+			start, breakAt = end, -1
+			used = 0
+			for _, rest := range runes[start:i] {
+				used += runeWidth(rest.r)
+			}
+		}
+		used += w
+		if sr.r == ' ' {
+			breakAt = i + 1
+		}
+	}
+	return append(lines, joinRunes(runes[start:]))
+}
+
+// joinRunes groups runs of equally styled characters back into spans, dropping trailing spaces.
+func joinRunes(runes []styledRune) line {
+	for len(runes) > 0 && runes[len(runes)-1].r == ' ' {
+		runes = runes[:len(runes)-1]
+	}
+
+	var l line
+	for _, sr := range runes {
+		if n := len(l); n > 0 && l[n-1].style == sr.style {
+			l[n-1].text += string(sr.r)
+			continue
+		}
+		l = append(l, span{text: string(sr.r), style: sr.style})
+	}
+	return l
+}
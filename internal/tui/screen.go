@@ -0,0 +1,174 @@
+package tui
+
+import (
+	"errors"
+	"strings"
+)
+
+// ErrUnsupported is returned by NewTerminal on platforms without terminal support.
+var ErrUnsupported = errors.New("the terminal UI is not supported on this platform")
+
+// Screen is where the UI is drawn and where its events come from: a real terminal,
+// or a SimScreen in tests.
+type Screen interface {
+	// Size returns the size of the screen in columns and rows.
+	Size() (width, height int)
+	// Show replaces what is on the screen with canvas.
+	Show(canvas *Canvas) error
+	// PollEvent waits for the next event. It returns false once there are no more events.
+	PollEvent() (Event, bool)
+	// Suspend hands the terminal over to another program, such as an editor, until Resume.
+	Suspend() error
+	// Resume takes the terminal back after Suspend.
+	Resume() error
+}
+
+// Event is a KeyEvent or a ResizeEvent.
+type Event any
+
+// Key identifies a key that is not a printable character.
+type Key int
+
+// Keys.
+const (
+	// KeyRune is a printable character, in KeyEvent.Rune.
+	KeyRune Key = iota
+	// KeyCtrl is a control character, with the letter in KeyEvent.Rune (e.g. 'c' for Ctrl-C).
+	KeyCtrl
+	KeyEnter
+	KeyEsc
+	KeyBackspace
+	KeyTab
+	KeyBacktab
+	KeyUp
+	KeyDown
+	KeyLeft
+	KeyRight
+	KeyHome
+	KeyEnd
+	KeyPgUp
+	KeyPgDn
+	KeyDelete
+)
+
+// KeyEvent is a key press.
+type KeyEvent struct {
+	Key  Key
+	Rune rune
+}
+
+// ResizeEvent reports that the screen changed size.
+type ResizeEvent struct {
+	Width, Height int
+}
+
+// Color is one of the 8 standard terminal colors, or the default color.
+type Color int
+
+// Colors.
+const (
+	ColorDefault Color = iota
+	ColorRed
+	ColorGreen
+	ColorYellow
+	ColorBlue
+	ColorMagenta
+	ColorCyan
+	ColorGray
+)
+
+// Style is the appearance of a cell.
+type Style struct {
+	FG        Color
+	Bold      bool
+	Dim       bool
+	Italic    bool
+	Underline bool
+	Reverse   bool
+}
+
+// Cell is a character cell of a Canvas. The cell after a double-width character is its
+// continuation, with a zero Rune.
+type Cell struct {
+	Rune  rune
+	Style Style
+}
+
+// Canvas is a grid of cells to draw a frame into.
+type Canvas struct {
+	width, height int
+	cells         []Cell
+}
+
+// NewCanvas returns a blank canvas.
+func NewCanvas(width, height int) *Canvas {
+	width, height = max(width, 0), max(height, 0)
+	c := &Canvas{width: width, height: height, cells: make([]Cell, width*height)}
+	c.Fill(0, 0, width, height, Style{})
+	return c
+}
+
+// Size returns the size of the canvas.
+func (c *Canvas) Size() (width, height int) {
+	return c.width, c.height
+}
+
+// Cell returns the cell at x, y.
+func (c *Canvas) Cell(x, y int) Cell {
+	return c.cells[y*c.width+x]
+}
+
+// Fill blanks a rectangle with style.
+func (c *Canvas) Fill(x, y, width, height int, style Style) {
+	for row := max(y, 0); row < min(y+height, c.height); row++ {
+		for col := max(x, 0); col < min(x+width, c.width); col++ {
+			c.cells[row*c.width+col] = Cell{Rune: ' ', Style: style}
+		}
+	}
+}
+
+// Put draws s at x, y, clipped to width columns, and returns the number of columns drawn.
+// Control characters are drawn as spaces, and a double-width character that does not fit is left out.
+func (c *Canvas) Put(x, y, width int, s string, style Style) int {
+	if y < 0 || y >= c.height {
+		return 0
+	}
+	limit := min(x+width, c.width)
+	col := x
+	for _, r := range s {
+		w := runeWidth(r)
+		if w == 0 {
+			continue
+		}
+		if col+w > limit {
+			break
+		}
+		if r < ' ' {
+			r = ' '
+		}
+		if col >= 0 {
+			c.cells[y*c.width+col] = Cell{Rune: r, Style: style}
+			if w == 2 { //nolint:mnd // double-width character
+				c.cells[y*c.width+col+1] = Cell{Style: style}
+			}
+		}
+		col += w
+	}
+	return col - x
+}
+
+// Text returns the characters of the canvas as lines, without styles or trailing spaces.
+func (c *Canvas) Text() string {
+	var b strings.Builder
+	for y := range c.height {
+		var line strings.Builder
+		for x := range c.width {
+			if r := c.Cell(x, y).Rune; r != 0 {
+				line.WriteRune(r)
+			}
+		}
+		b.WriteString(strings.TrimRight(line.String(), " "))
+		b.WriteByte('\n')
+	}
+	return b.String()
+}
@@ -0,0 +1,19 @@
+package tui_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/sushichan044/memo-cli/internal/tui"
+)
+
+func TestCanvas_Put(t *testing.T) {
+	c := tui.NewCanvas(8, 2)
+
+	assert.Equal(t, 6, c.Put(1, 0, 6, "メモ帳ok", tui.Style{}), "a wide character that does not fit is left out")
+	assert.Equal(t, 3, c.Put(5, 1, 10, "a\tb", tui.Style{}), "clipped to the canvas")
+	assert.Zero(t, c.Cell(2, 0).Rune, "continuation of メ")
+
+	assert.Equal(t, " メモ帳\n     a b\n", c.Text())
+}
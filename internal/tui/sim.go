@@ -0,0 +1,100 @@
+package tui
+
+import "sync"
+
+// SimScreen is a headless Screen for tests. Events are queued up front and the last frame shown
+// can be inspected.
+type SimScreen struct {
+	mu            sync.Mutex
+	width, height int
+	events        []Event
+	frame         *Canvas
+	suspended     bool
+	// Frames counts the frames shown.
+	Frames int
+}
+
+// NewSimScreen returns a SimScreen of the given size.
+func NewSimScreen(width, height int) *SimScreen {
+	return &SimScreen{width: width, height: height, frame: NewCanvas(width, height)}
+}
+
+// Size implements Screen.
+func (s *SimScreen) Size() (int, int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.width, s.height
+}
+
+// Show implements Screen.
+func (s *SimScreen) Show(canvas *Canvas) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.frame = canvas
+	s.Frames++
+	return nil
+}
+
+// PollEvent implements Screen. It returns false once the queued events are consumed.
+func (s *SimScreen) PollEvent() (Event, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if len(s.events) == 0 {
+		return nil, false
+	}
+	ev := s.events[0]
+	s.events = s.events[1:]
+	if resize, ok := ev.(ResizeEvent); ok {
+		s.width, s.height = resize.Width, resize.Height
+	}
+	return ev, true
+}
+
+// Suspend implements Screen.
+func (s *SimScreen) Suspend() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.suspended = true
+	return nil
+}
+
+// Resume implements Screen.
+func (s *SimScreen) Resume() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.suspended = false
+	return nil
+}
+
+// Suspended reports whether the screen is suspended, such as while an editor runs.
+func (s *SimScreen) Suspended() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.suspended
+}
+
+// Type queues the keys of terminal input, such as "jj\r" or "\x1b[B".
+func (s *SimScreen) Type(input string) {
+	for _, key := range ParseKeys([]byte(input)) {
+		s.Inject(key)
+	}
+}
+
+// Inject queues an event. A ResizeEvent changes the size of the screen when it is polled.
+func (s *SimScreen) Inject(ev Event) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.events = append(s.events, ev)
+}
+
+// Canvas returns the last frame shown.
+func (s *SimScreen) Canvas() *Canvas {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.frame
+}
+
+// Text returns the characters of the last frame shown, as lines.
+func (s *SimScreen) Text() string {
+	return s.Canvas().Text()
+}
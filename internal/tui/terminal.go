@@ -0,0 +1,230 @@
+//go:build darwin || dragonfly || freebsd || linux || netbsd || openbsd
+
+package tui
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/signal"
+	"strings"
+	"sync"
+
+	"golang.org/x/sys/unix"
+)
+
+// ErrNotTerminal is returned by NewTerminal when input or output is not a terminal.
+var ErrNotTerminal = errors.New("not a terminal")
+
+const (
+	// defaultWidth and defaultHeight are used when the terminal does not report its size.
+	defaultWidth  = 80
+	defaultHeight = 24
+	// readTimeout is how long a read of input waits, in tenths of a second, before checking
+	// whether the terminal is being suspended.
+	readTimeout = 1
+)
+
+// Terminal is a Screen on a terminal in raw mode, using the alternate screen so that
+// the previous content of the terminal is restored on Close.
+type Terminal struct {
+	in, out *os.File
+	// cooked is the terminal mode to restore on Suspend and Close.
+	cooked *unix.Termios
+
+	events  chan Event
+	signals chan os.Signal
+	done    chan struct{}
+	// reading is held by the input reader during each read, and by Suspend until Resume,
+	// so that an editor running meanwhile gets all input.
+	reading chan struct{}
+
+	mu        sync.Mutex
+	suspended bool
+	// rows are the encoded rows last shown, so that only changed rows are drawn.
+	rows []string
+}
+
+// NewTerminal switches the terminal of in and out to raw mode and the alternate screen.
+// Close restores it.
+func NewTerminal(in, out *os.File) (*Terminal, error) {
+	cooked, err := unix.IoctlGetTermios(int(in.Fd()), ioctlGetTermios)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrNotTerminal, in.Name())
+	}
+	if _, err = unix.IoctlGetWinsize(int(out.Fd()), unix.TIOCGWINSZ); err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrNotTerminal, out.Name())
+	}
+
+	t := &Terminal{
+		in:      in,
+		out:     out,
+		cooked:  cooked,
+		events:  make(chan Event),
+		signals: make(chan os.Signal, 1),
+		done:    make(chan struct{}),
+		reading: make(chan struct{}, 1),
+	}
+	if err = t.enter(); err != nil {
+		return nil, err
+	}
+
+	signal.Notify(t.signals, unix.SIGWINCH)
+	go t.readInput()
+	go t.watchResize()
+	return t, nil
+}
+
+// Close restores the terminal.
+func (t *Terminal) Close() error {
+	close(t.done)
+	signal.Stop(t.signals)
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.suspended {
+		return nil
+	}
+	return t.leave()
+}
+
+// Size implements Screen.
+func (t *Terminal) Size() (int, int) {
+	ws, err := unix.IoctlGetWinsize(int(t.out.Fd()), unix.TIOCGWINSZ)
+	if err != nil || ws.Col == 0 || ws.Row == 0 {
+		return defaultWidth, defaultHeight
+	}
+	return int(ws.Col), int(ws.Row)
+}
+
+// Show implements Screen.
+func (t *Terminal) Show(canvas *Canvas) error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	var b strings.Builder
+	b.WriteString(beginSync)
+	_, height := canvas.Size()
+	if len(t.rows) != height {
+		// The terminal was resized or resumed: everything is redrawn.
+		b.WriteString(clearScreen)
+		t.rows = make([]string, height)
+	}
+	for y := range height {
+		if row := encodeRow(canvas, y); row != t.rows[y] {
+			b.WriteString(row)
+			t.rows[y] = row
+		}
+	}
+	b.WriteString(endSync)
+
+	_, err := t.out.WriteString(b.String())
+	return err
+}
+
+// PollEvent implements Screen.
+func (t *Terminal) PollEvent() (Event, bool) {
+	select {
+	case ev := <-t.events:
+		return ev, true
+	case <-t.done:
+		return nil, false
+	}
+}
+
+// Suspend implements Screen, restoring the terminal mode the UI was started from.
+func (t *Terminal) Suspend() error {
+	// Wait for the reader to finish its read and keep it from reading until Resume.
+	t.reading <- struct{}{}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.suspended = true
+	return t.leave()
+}
+
+// Resume implements Screen.
+func (t *Terminal) Resume() error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if !t.suspended {
+		return nil
+	}
+	t.suspended = false
+	t.rows = nil
+	err := t.enter()
+	<-t.reading
+	return err
+}
+
+// enter switches to raw mode and the alternate screen.
+func (t *Terminal) enter() error {
+	raw := *t.cooked
+	raw.Iflag &^= unix.IGNBRK | unix.BRKINT | unix.PARMRK | unix.ISTRIP | unix.INLCR | unix.IGNCR | unix.ICRNL | unix.IXON
+	raw.Oflag &^= unix.OPOST
+	raw.Lflag &^= unix.ECHO | unix.ECHONL | unix.ICANON | unix.ISIG | unix.IEXTEN
+	raw.Cflag &^= unix.CSIZE | unix.PARENB
+	raw.Cflag |= unix.CS8
+	// Reads return after readTimeout even without input, see readInput.
+	raw.Cc[unix.VMIN] = 0
+	raw.Cc[unix.VTIME] = readTimeout
+	if err := unix.IoctlSetTermios(int(t.in.Fd()), ioctlSetTermios, &raw); err != nil {
+		return fmt.Errorf("failed to set up the terminal: %w", err)
+	}
+
+	_, err := t.out.WriteString(enterAltScreen + hideCursor + clearScreen)
+	return err
+}
+
+// leave restores the terminal mode and the main screen.
+func (t *Terminal) leave() error {
+	if _, err := t.out.WriteString(resetStyle + showCursor + exitAltScreen); err != nil {
+		return err
+	}
+	if err := unix.IoctlSetTermios(int(t.in.Fd()), ioctlSetTermios, t.cooked); err != nil {
+		return fmt.Errorf("failed to restore the terminal: %w", err)
+	}
+	return nil
+}
+
+// readInput turns input into key events until Close. Reads time out so that Suspend
+// never waits long for the reader to let go of the terminal.
+func (t *Terminal) readInput() {
+	buf := make([]byte, 256) //nolint:mnd // longer than any escape sequence
+	for {
+		select {
+		case <-t.done:
+			return
+		case t.reading <- struct{}{}:
+		}
+		n, err := unix.Read(int(t.in.Fd()), buf)
+		<-t.reading
+		if err != nil && !errors.Is(err, unix.EINTR) && !errors.Is(err, unix.EAGAIN) {
+			return
+		}
+
+		for _, key := range ParseKeys(buf[:max(n, 0)]) {
+			select {
+			case t.events <- key:
+			case <-t.done:
+				return
+			}
+		}
+	}
+}
+
+func (t *Terminal) watchResize() {
+	for {
+		select {
+		case <-t.signals:
+			width, height := t.Size()
+			select {
+			case t.events <- ResizeEvent{Width: width, Height: height}:
+			case <-t.done:
+				return
+			}
+		case <-t.done:
+			return
+		}
+	}
+}
@@ -0,0 +1,20 @@
+//go:build !(darwin || dragonfly || freebsd || linux || netbsd || openbsd)
+
+package tui
+
+import "os"
+
+// Terminal is not available on this platform.
+type Terminal struct {
+	Screen
+}
+
+// NewTerminal returns ErrUnsupported on this platform.
+func NewTerminal(_, _ *os.File) (*Terminal, error) {
+	return nil, ErrUnsupported
+}
+
+// Close does nothing on this platform.
+func (t *Terminal) Close() error {
+	return nil
+}
@@ -0,0 +1,300 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+const (
+	// minWidth and minHeight are the smallest screen the UI is drawn on.
+	minWidth  = 20
+	minHeight = 5
+	// threePaneWidth is the narrowest screen showing all panes; narrower screens show the focused pane only.
+	threePaneWidth = 72
+)
+
+//nolint:gochecknoglobals // constant styles
+var (
+	barStyle      = Style{Reverse: true}
+	titleStyle    = Style{Bold: true}
+	focusStyle    = Style{FG: ColorCyan, Bold: true, Reverse: true}
+	cursorStyle   = Style{Reverse: true}
+	unfocusCursor = Style{Bold: true, Underline: true}
+	errorStyle    = Style{FG: ColorRed, Bold: true}
+)
+
+// keyHints are shown in the status bar when there is nothing else to show.
+const keyHints = "n new  e edit  r rename  t tag  d delete  / search  ? help  q quit"
+
+//nolint:gochecknoglobals // constant table
+var helpLines = []string{
+	"Tab, ←/→, h/l   switch pane",
+	"↑/↓, j/k        move",
+	"PgUp/PgDn, b/␠  page up/down",
+	"Home/End, g/G   first/last",
+	"Enter           open the day / edit the memo",
+	"e               edit in $EDITOR",
+	"n               new memo",
+	"r               rename",
+	"t               add a tag",
+	"d, Delete       delete",
+	"/               search names and content",
+	"Esc             clear the search",
+	"R, Ctrl-L       reload",
+	"q, Ctrl-C       quit",
+}
+
+// rect is an area of the canvas.
+type rect struct {
+	x, y, width, height int
+}
+
+func (a *App) draw(c *Canvas) {
+	width, height := c.Size()
+	if width < minWidth || height < minHeight {
+		c.Put(0, 0, width, "Window too small", Style{})
+		return
+	}
+
+	a.drawTitleBar(c, width)
+	a.drawStatusBar(c, width, height-1)
+
+	body := rect{x: 0, y: 1, width: width, height: height - 2} //nolint:mnd // title and status bars
+	if width < threePaneWidth {
+		a.drawPane(c, a.focus, body)
+	} else {
+		days := min(max(width/6, 16), 24)     //nolint:mnd // a sixth of the screen, within bounds
+		memos := min(max(width*3/10, 24), 44) //nolint:mnd // three tenths of the screen, within bounds
+		preview := width - days - memos - 2   //nolint:mnd // two separators
+		a.drawPane(c, paneDays, rect{x: 0, y: body.y, width: days, height: body.height})
+		a.drawSeparator(c, days, body)
+		a.drawPane(c, paneMemos, rect{x: days + 1, y: body.y, width: memos, height: body.height})
+		a.drawSeparator(c, days+1+memos, body)
+		a.drawPane(c, panePreview, rect{x: days + memos + 2, y: body.y, width: preview, height: body.height})
+	}
+
+	if a.help {
+		a.drawHelp(c, width, height)
+	}
+}
+
+func (a *App) drawTitleBar(c *Canvas, width int) {
+	c.Fill(0, 0, width, 1, barStyle)
+	title := " memo ui  " + a.fsys.Root()
+	right := ""
+	if a.query != "" {
+		right = fmt.Sprintf("search: %s (%d) ", a.query, len(a.matches))
+	}
+	c.Put(0, 0, width-stringWidth(right), truncate(title, width-stringWidth(right)-1), Style{Reverse: true, Bold: true})
+	c.Put(width-stringWidth(right), 0, stringWidth(right), right, barStyle)
+}
+
+func (a *App) drawStatusBar(c *Canvas, width, y int) {
+	switch {
+	case a.prompt != nil:
+		text := a.prompt.label + ": " + string(a.prompt.input)
+		// Keep the end of long input in view.
+		for stringWidth(text)+1 > width {
+			_, size := utf8.DecodeRuneInString(text)
+			text = text[size:]
+		}
+		n := c.Put(0, y, width, text, Style{})
+		c.Put(n, y, 1, " ", cursorStyle)
+	case a.confirm != nil:
+		c.Put(0, y, width, truncate(a.confirm.question+" (y/N)", width), Style{Bold: true})
+	case a.status != "":
+		style := Style{}
+		if a.failed {
+			style = errorStyle
+		}
+		c.Put(0, y, width, truncate(a.status, width), style)
+	default:
+		c.Put(0, y, width, truncate(keyHints, width), mutedStyle)
+	}
+}
+
+func (a *App) drawSeparator(c *Canvas, x int, area rect) {
+	for y := area.y; y < area.y+area.height; y++ {
+		c.Put(x, y, 1, "│", mutedStyle)
+	}
+}
+
+func (a *App) drawPane(c *Canvas, p pane, area rect) {
+	titles := map[pane]string{paneDays: "Days", paneMemos: fmt.Sprintf("Memos (%d)", len(a.visible)), panePreview: "Preview"}
+	style := titleStyle
+	if p == a.focus {
+		style = focusStyle
+	}
+	c.Fill(area.x, area.y, area.width, 1, style)
+	c.Put(area.x+1, area.y, area.width-1, truncate(titles[p], area.width-2), style) //nolint:mnd // padding
+
+	inner := rect{x: area.x + 1, y: area.y + 1, width: area.width - 2, height: area.height - 1} //nolint:mnd // padding
+	switch p {
+	case paneDays:
+		a.drawTree(c, inner)
+	case paneMemos:
+		a.drawList(c, inner)
+	case panePreview:
+		a.drawPreview(c, inner)
+	}
+}
+
+func (a *App) drawTree(c *Canvas, area rect) {
+	a.treeTop = scrollTo(a.treeTop, a.treeCursor, area.height)
+	today := time.Now().Format("20060102")
+	for row := range area.height {
+		i := a.treeTop + row
+		if i >= len(a.tree) {
+			break
+		}
+		r := a.tree[i]
+		y := area.y + row
+
+		if r.header {
+			c.Put(area.x, y, area.width, truncate(r.label, area.width), titleStyle)
+			continue
+		}
+		style := Style{}
+		if r.day == today {
+			style.FG = ColorCyan
+		}
+		if i == a.treeCursor {
+			style = a.cursorStyle(paneDays)
+			c.Fill(area.x, y, area.width, 1, style)
+		}
+		label := r.label
+		if r.day != "" {
+			label = "  " + label
+		}
+		count := fmt.Sprint(r.count)
+		c.Put(area.x, y, area.width, truncate(label, area.width-len(count)-1), style)
+		c.Put(area.x+area.width-len(count), y, len(count), count, style)
+	}
+}
+
+func (a *App) drawList(c *Canvas, area rect) {
+	if len(a.visible) == 0 {
+		message := "No memos."
+		if a.query != "" {
+			message = "No memos match."
+		}
+		c.Put(area.x, area.y, area.width, message, mutedStyle)
+		return
+	}
+
+	a.listTop = scrollTo(a.listTop, a.listCursor, area.height)
+	showDate := a.selectedDay() == ""
+	for row := range area.height {
+		i := a.listTop + row
+		if i >= len(a.visible) {
+			break
+		}
+		entry := a.visible[i]
+		y := area.y + row
+
+		style := Style{}
+		if i == a.listCursor {
+			style = a.cursorStyle(paneMemos)
+			c.Fill(area.x, y, area.width, 1, style)
+		}
+
+		when := entry.CreatedAt.Format("15:04")
+		if entry.Daily {
+			when = "daily"
+		}
+		if showDate {
+			when = entry.CreatedAt.Format("01-02 ") + when
+		}
+		title := entry.Title()
+		if entry.Encrypted {
+			title += " 🔒"
+		}
+
+		timeStyle := style
+		if i != a.listCursor {
+			timeStyle = mutedStyle
+		}
+		n := c.Put(area.x, y, area.width, when+" ", timeStyle)
+		c.Put(area.x+n, y, area.width-n, truncate(title, area.width-n), style)
+	}
+}
+
+func (a *App) drawPreview(c *Canvas, area rect) {
+	entry := a.selected()
+	if entry == nil {
+		return
+	}
+
+	y := area.y
+	c.Put(area.x, y, area.width, truncate(entry.Title(), area.width), titleStyle)
+	y++
+	c.Put(area.x, y, area.width, truncate(entry.RelPath, area.width), mutedStyle)
+	y++
+
+	lines, tags, err := a.preview(area.width)
+	if err != nil {
+		c.Put(area.x, y, area.width, truncate(err.Error(), area.width), errorStyle)
+		return
+	}
+	if len(tags) > 0 {
+		c.Put(area.x, y, area.width, truncate("#"+strings.Join(tags, " #"), area.width), tagStyle)
+		y++
+	}
+	y++
+
+	a.scroll = min(a.scroll, max(len(lines)-1, 0))
+	for _, l := range lines[a.scroll:] {
+		if y >= area.y+area.height {
+			break
+		}
+		x := area.x
+		for _, s := range l {
+			x += c.Put(x, y, area.x+area.width-x, s.text, s.style)
+		}
+		y++
+	}
+}
+
+func (a *App) drawHelp(c *Canvas, width, height int) {
+	boxWidth := 2
+	for _, l := range helpLines {
+		boxWidth = max(boxWidth, stringWidth(l)+4) //nolint:mnd // borders and padding
+	}
+	boxWidth = min(boxWidth, width)
+	boxHeight := min(len(helpLines)+2, height) //nolint:mnd // borders
+	x, y := (width-boxWidth)/2, (height-boxHeight)/2
+
+	c.Fill(x, y, boxWidth, boxHeight, Style{})
+	c.Put(x, y, boxWidth, "┌"+strings.Repeat("─", boxWidth-2)+"┐", mutedStyle)
+	c.Put(x+2, y, boxWidth-4, " Keys ", titleStyle) //nolint:mnd // after the corner
+	for i := 1; i < boxHeight-1; i++ {
+		c.Put(x, y+i, 1, "│", mutedStyle)
+		c.Put(x+2, y+i, boxWidth-4, helpLines[i-1], Style{}) //nolint:mnd // borders and padding
+		c.Put(x+boxWidth-1, y+i, 1, "│", mutedStyle)
+	}
+	c.Put(x, y+boxHeight-1, boxWidth, "└"+strings.Repeat("─", boxWidth-2)+"┘", mutedStyle)
+}
+
+// cursorStyle highlights the cursor row of a pane, less so when the pane is not focused.
+func (a *App) cursorStyle(p pane) Style {
+	if p == a.focus {
+		return cursorStyle
+	}
+	return unfocusCursor
+}
+
+// scrollTo returns the first row to show so that cursor is visible, scrolling as little as possible from top.
+func scrollTo(top, cursor, height int) int {
+	if height <= 0 {
+		return cursor
+	}
+	if cursor < top {
+		return cursor
+	}
+	if cursor >= top+height {
+		return cursor - height + 1
+	}
+	return top
+}
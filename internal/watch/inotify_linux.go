@@ -0,0 +1,144 @@
+package watch
+
+import (
+	"context"
+	"encoding/binary"
+	"errors"
+	"io/fs"
+	"path/filepath"
+	"time"
+
+	"golang.org/x/sys/unix"
+)
+
+// inotifyMask selects the events that may change the memos of a directory.
+const inotifyMask = unix.IN_CREATE | unix.IN_DELETE | unix.IN_MODIFY | unix.IN_CLOSE_WRITE | unix.IN_ATTRIB |
+	unix.IN_MOVED_FROM | unix.IN_MOVED_TO | unix.IN_DELETE_SELF | unix.IN_MOVE_SELF | unix.IN_ONLYDIR
+
+// pollTimeout bounds how long wait blocks before checking whether its context is done.
+const pollTimeout = 200 * time.Millisecond
+
+// errRootRemoved is returned by wait when the memo root is deleted or moved.
+var errRootRemoved = errors.New("memo root was removed")
+
+// inotify watches the memo root and its date directories with Linux inotify.
+type inotify struct {
+	fd   int
+	root string
+	// dirs maps watched directories, relative to root, to their watch descriptors.
+	dirs map[string]int
+	// names maps watch descriptors back to directories.
+	names map[int]string
+	buf   []byte
+}
+
+func newNotifier(root string) (notifier, error) {
+	fd, err := unix.InotifyInit1(unix.IN_CLOEXEC | unix.IN_NONBLOCK)
+	if err != nil {
+		return nil, err
+	}
+
+	n := &inotify{fd: fd, root: root, dirs: make(map[string]int), names: make(map[int]string), buf: make([]byte, 64*1024)} //nolint:mnd // room for many events
+	if watchErr := n.watch("."); watchErr != nil {
+		unix.Close(fd)
+		return nil, watchErr
+	}
+	return n, nil
+}
+
+func (n *inotify) watch(dir string) error {
+	if _, ok := n.dirs[dir]; ok {
+		return nil
+	}
+	wd, err := unix.InotifyAddWatch(n.fd, filepath.Join(n.root, dir), inotifyMask)
+	if err != nil {
+		return &fs.PathError{Op: "inotify_add_watch", Path: dir, Err: err}
+	}
+	n.dirs[dir] = wd
+	n.names[wd] = dir
+	return nil
+}
+
+func (n *inotify) wait(ctx context.Context) error {
+	// Block until the first event arrives.
+	for {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		ready, err := n.poll(pollTimeout)
+		if err != nil {
+			return err
+		}
+		if ready {
+			break
+		}
+	}
+
+	// Then keep reading until events stop for a while.
+	for {
+		if err := n.drain(); err != nil {
+			return err
+		}
+		ready, err := n.poll(settle)
+		if err != nil {
+			return err
+		}
+		if !ready {
+			return nil
+		}
+	}
+}
+
+// poll reports whether events are ready to be read within timeout.
+func (n *inotify) poll(timeout time.Duration) (bool, error) {
+	fds := []unix.PollFd{{Fd: int32(n.fd), Events: unix.POLLIN}} //nolint:gosec // file descriptors fit in int32
+	count, err := unix.Poll(fds, int(timeout.Milliseconds()))
+	if errors.Is(err, unix.EINTR) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	return count > 0, nil
+}
+
+// drain reads the pending events, forgetting directories that are no longer watched.
+func (n *inotify) drain() error {
+	for {
+		count, err := unix.Read(n.fd, n.buf)
+		if errors.Is(err, unix.EAGAIN) || errors.Is(err, unix.EINTR) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		for offset := 0; offset+unix.SizeofInotifyEvent <= count; {
+			wd := int(int32(binary.NativeEndian.Uint32(n.buf[offset:]))) //nolint:gosec // the kernel writes an int32
+			mask := binary.NativeEndian.Uint32(n.buf[offset+4:])
+			nameLen := int(binary.NativeEndian.Uint32(n.buf[offset+12:]))
+			offset += unix.SizeofInotifyEvent + nameLen
+
+			if mask&(unix.IN_IGNORED|unix.IN_MOVE_SELF) == 0 {
+				continue
+			}
+			dir, ok := n.names[wd]
+			if !ok {
+				continue
+			}
+			if dir == "." {
+				return errRootRemoved
+			}
+			// A moved directory is watched no more, so that a new directory of the same name can be.
+			if mask&unix.IN_MOVE_SELF != 0 {
+				_, _ = unix.InotifyRmWatch(n.fd, uint32(wd)) //nolint:gosec // watch descriptors are positive
+			}
+			delete(n.names, wd)
+			delete(n.dirs, dir)
+		}
+	}
+}
+
+func (n *inotify) close() error {
+	return unix.Close(n.fd)
+}
@@ -0,0 +1,7 @@
+//go:build !linux
+
+package watch
+
+func newNotifier(string) (notifier, error) {
+	return nil, errUnsupported
+}
@@ -0,0 +1,227 @@
+// Package watch reports changes to the memos of a memo root as they happen.
+//
+// On Linux the memo root and its date directories are watched with inotify, and a change
+// triggers a rescan of the memo root; elsewhere, or when inotify is unavailable, the memo root
+// is scanned at a fixed interval. Either way, changes are found by comparing the size and
+// modification time of every memo with the previous scan.
+package watch
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/sushichan044/memo-cli/internal/memo"
+	"github.com/sushichan044/memo-cli/internal/memofs"
+)
+
+// DefaultInterval is the time between scans when polling.
+const DefaultInterval = time.Second
+
+// settle is how long notifications must stop for a burst of changes, such as an editor saving a file, to be reported at once.
+const settle = 50 * time.Millisecond
+
+// Op is the kind of change to a memo.
+type Op string
+
+const (
+	Created  Op = "created"
+	Modified Op = "modified"
+	Deleted  Op = "deleted"
+)
+
+// errUnsupported is returned by newNotifier where change notifications are not implemented.
+var errUnsupported = errors.New("change notifications are not supported on this platform")
+
+// Event is a change to a memo.
+type Event struct {
+	Op Op
+	// Entry is the memo that changed. For deleted memos, it describes the memo as it was.
+	Entry memo.Entry
+	// Time is when the change was noticed.
+	Time time.Time
+}
+
+// Options configures a Watcher.
+type Options struct {
+	// Interval is the time between scans when polling. Zero means DefaultInterval.
+	Interval time.Duration
+	// Poll scans at Interval even where change notifications are available.
+	Poll bool
+	// Fallback is called with the reason when change notifications cannot be used and the watcher polls instead.
+	Fallback func(error)
+}
+
+// Watcher reports changes to the memos of a memo root.
+type Watcher struct {
+	fsys memofs.FS
+	opts Options
+}
+
+// notifier waits for changes in the directories it watches.
+type notifier interface {
+	// watch adds dir, relative to the memo root, to the watched directories. Watching a directory twice is a no-op.
+	watch(dir string) error
+	// wait blocks until a burst of changes is over or ctx is done.
+	wait(ctx context.Context) error
+	close() error
+}
+
+// stamp is the state of a memo in a scan.
+type stamp struct {
+	entry   memo.Entry
+	size    int64
+	modTime time.Time
+}
+
+// New returns a Watcher of the memos in fsys.
+func New(fsys memofs.FS, opts Options) *Watcher {
+	if opts.Interval <= 0 {
+		opts.Interval = DefaultInterval
+	}
+	return &Watcher{fsys: fsys, opts: opts}
+}
+
+// Run watches the memo root until ctx is done, calling handle with the changes found by each scan.
+// Memos present when Run starts are not reported. An error returned by handle stops Run and is returned.
+func (w *Watcher) Run(ctx context.Context, handle func([]Event) error) error {
+	var n notifier
+	if !w.opts.Poll {
+		var err error
+		if n, err = newNotifier(w.fsys.Root()); err != nil {
+			w.fallback(err)
+			n = nil
+		}
+	}
+	defer func() {
+		if n != nil {
+			_ = n.close()
+		}
+	}()
+	if n != nil {
+		if err := w.watchDirs(n); err != nil {
+			return err
+		}
+	}
+
+	state, err := w.scan()
+	if err != nil {
+		return err
+	}
+
+	for {
+		if n != nil {
+			if waitErr := n.wait(ctx); waitErr != nil && ctx.Err() == nil {
+				// The memo root itself went away: keep going by polling until it comes back.
+				w.fallback(waitErr)
+				_ = n.close()
+				n = nil
+			}
+		} else {
+			select {
+			case <-ctx.Done():
+			case <-time.After(w.opts.Interval):
+			}
+		}
+		if ctx.Err() != nil {
+			return nil
+		}
+
+		if n != nil {
+			if watchErr := w.watchDirs(n); watchErr != nil {
+				return watchErr
+			}
+		}
+		next, scanErr := w.scan()
+		if scanErr != nil {
+			return scanErr
+		}
+
+		events := diff(state, next, time.Now())
+		state = next
+		if len(events) == 0 {
+			continue
+		}
+		if handleErr := handle(events); handleErr != nil {
+			return handleErr
+		}
+	}
+}
+
+func (w *Watcher) fallback(err error) {
+	if w.opts.Fallback != nil {
+		w.opts.Fallback(err)
+	}
+}
+
+// watchDirs adds the date directories of the memo root to n, so that changes to the memos in them are noticed.
+func (w *Watcher) watchDirs(n notifier) error {
+	dirs, err := w.fsys.ReadDir(".")
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to read memo directory: %w", err)
+	}
+
+	for _, dir := range dirs {
+		if !dir.IsDir() || strings.HasPrefix(dir.Name(), ".") {
+			continue
+		}
+		if _, parseErr := time.Parse("20060102", dir.Name()); parseErr != nil {
+			continue
+		}
+		// The directory may be gone already; the next scan will tell.
+		if watchErr := n.watch(dir.Name()); watchErr != nil && !errors.Is(watchErr, fs.ErrNotExist) {
+			return fmt.Errorf("failed to watch %s: %w", dir.Name(), watchErr)
+		}
+	}
+	return nil
+}
+
+// scan returns the state of every memo by relative path.
+func (w *Watcher) scan() (map[string]stamp, error) {
+	entries, err := memo.List(w.fsys)
+	if err != nil {
+		return nil, err
+	}
+
+	state := make(map[string]stamp, len(entries))
+	for _, entry := range entries {
+		info, statErr := w.fsys.Stat(entry.RelPath)
+		if statErr != nil {
+			// Deleted since it was listed.
+			continue
+		}
+		state[entry.RelPath] = stamp{entry: entry, size: info.Size(), modTime: info.ModTime()}
+	}
+	return state, nil
+}
+
+// diff returns the changes from the scan prev to the scan next, by relative path.
+func diff(prev, next map[string]stamp, now time.Time) []Event {
+	var events []Event
+	for relPath, s := range next {
+		old, ok := prev[relPath]
+		switch {
+		case !ok:
+			events = append(events, Event{Op: Created, Entry: s.entry, Time: now})
+		case old.size != s.size || !old.modTime.Equal(s.modTime):
+			events = append(events, Event{Op: Modified, Entry: s.entry, Time: now})
+		}
+	}
+	for relPath, s := range prev {
+		if _, ok := next[relPath]; !ok {
+			events = append(events, Event{Op: Deleted, Entry: s.entry, Time: now})
+		}
+	}
+
+	slices.SortFunc(events, func(a, b Event) int {
+		return strings.Compare(a.Entry.RelPath, b.Entry.RelPath)
+	})
+	return events
+}
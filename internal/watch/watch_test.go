@@ -0,0 +1,109 @@
+package watch_test
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/sushichan044/memo-cli/internal/memofs"
+	"github.com/sushichan044/memo-cli/internal/watch"
+)
+
+// recorder collects the events reported by a running Watcher.
+type recorder struct {
+	mu     sync.Mutex
+	events []string
+}
+
+func (r *recorder) handle(events []watch.Event) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, e := range events {
+		r.events = append(r.events, string(e.Op)+" "+e.Entry.RelPath)
+	}
+	return nil
+}
+
+// waitFor waits until the events seen so far are want.
+func (r *recorder) waitFor(t *testing.T, want ...string) {
+	t.Helper()
+	assert.EventuallyWithT(t, func(c *assert.CollectT) {
+		r.mu.Lock()
+		defer r.mu.Unlock()
+		assert.Equal(c, want, r.events)
+	}, 5*time.Second, 10*time.Millisecond)
+}
+
+// start runs a Watcher of fsys until the test ends.
+func start(t *testing.T, fsys memofs.FS, opts watch.Options) *recorder {
+	t.Helper()
+
+	ctx, cancel := context.WithCancel(t.Context())
+	r := &recorder{}
+	done := make(chan error, 1)
+	go func() { done <- watch.New(fsys, opts).Run(ctx, r.handle) }()
+	t.Cleanup(func() {
+		cancel()
+		require.NoError(t, <-done)
+	})
+
+	// Give the watcher time to take its first scan.
+	time.Sleep(100 * time.Millisecond)
+	return r
+}
+
+func exercise(t *testing.T, fsys memofs.FS, r *recorder) {
+	t.Helper()
+
+	require.NoError(t, fsys.WriteFile("20251031/14-30-45-notes.md", []byte("# Notes\n"), 0o600))
+	require.NoError(t, fsys.WriteFile("20251031/notes.txt", []byte("not a memo"), 0o600))
+	r.waitFor(t, "created 20251031/14-30-45-notes.md")
+
+	require.NoError(t, fsys.WriteFile("20251031/14-30-45-notes.md", []byte("# Notes\n\nMore.\n"), 0o600))
+	r.waitFor(t, "created 20251031/14-30-45-notes.md", "modified 20251031/14-30-45-notes.md")
+
+	// Memos in new date directories are noticed too.
+	require.NoError(t, fsys.MkdirAll("20251101", 0o700))
+	require.NoError(t, fsys.WriteFile("20251101/daily.md", []byte("Standup.\n"), 0o600))
+	require.NoError(t, fsys.Remove("20251031/14-30-45-notes.md"))
+	r.waitFor(t,
+		"created 20251031/14-30-45-notes.md",
+		"modified 20251031/14-30-45-notes.md",
+		"deleted 20251031/14-30-45-notes.md",
+		"created 20251101/daily.md",
+	)
+}
+
+func TestWatcher_Poll(t *testing.T) {
+	fsys := memofs.NewMem("/memo")
+	require.NoError(t, fsys.MkdirAll("20251031", 0o700))
+	require.NoError(t, fsys.WriteFile("20251031/09-00-00-plan.md", []byte("# Plan\n"), 0o600))
+
+	r := start(t, fsys, watch.Options{Poll: true, Interval: 20 * time.Millisecond})
+	exercise(t, fsys, r)
+}
+
+// TestWatcher_Notify uses change notifications where available, and polling elsewhere.
+func TestWatcher_Notify(t *testing.T) {
+	fsys := memofs.NewOS(t.TempDir())
+	require.NoError(t, fsys.MkdirAll("20251031", 0o700))
+
+	r := start(t, fsys, watch.Options{Interval: 20 * time.Millisecond})
+	exercise(t, fsys, r)
+}
+
+func TestWatcher_MissingRoot(t *testing.T) {
+	root := t.TempDir() + "/memo"
+	fsys := memofs.NewOS(root)
+
+	var fallback error
+	r := start(t, fsys, watch.Options{Interval: 20 * time.Millisecond, Fallback: func(err error) { fallback = err }})
+	require.NoError(t, fsys.MkdirAll("20251031", 0o700))
+	require.NoError(t, fsys.WriteFile("20251031/daily.md", []byte("Standup.\n"), 0o600))
+	r.waitFor(t, "created 20251031/daily.md")
+	assert.Error(t, fallback)
+}
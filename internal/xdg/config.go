@@ -30,3 +30,28 @@ func getConfigHome() (string, error) {
 	}
 	return filepath.Join(home, ".config"), nil
 }
+
+func DataHome() (string, error) {
+	dataHome, err := getDataHome()
+	if err != nil {
+		return "", err
+	}
+
+	return filepath.Clean(dataHome), nil
+}
+
+func getDataHome() (string, error) {
+	if runtime.GOOS == "windows" {
+		return os.UserConfigDir()
+	}
+
+	if dataHome := os.Getenv("XDG_DATA_HOME"); dataHome != "" {
+		return dataHome, nil
+	}
+
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(home, ".local", "share"), nil
+}
@@ -0,0 +1,250 @@
+// Package memo is the Go API for creating and managing memos programmatically.
+//
+// A Store operates on a memo root laid out exactly like the one used by the memo CLI
+// (YYYYMMDD/HH-MM-SS[-name].ext), so memos created through either are visible to both.
+// Unlike the CLI, a Store is configured explicitly with options and never reads
+// environment variables or the configuration file.
+package memo
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"path/filepath"
+	"regexp"
+
+	"github.com/sushichan044/memo-cli/internal/config"
+	"github.com/sushichan044/memo-cli/internal/crypt"
+	internalmemo "github.com/sushichan044/memo-cli/internal/memo"
+	"github.com/sushichan044/memo-cli/internal/memofs"
+	"github.com/sushichan044/memo-cli/schema"
+)
+
+// LatestRef is a reference to the most recently created memo.
+const LatestRef = internalmemo.LatestRef
+
+//nolint:gochecknoglobals // sentinel errors re-exported for errors.Is
+var (
+	// ErrExists is returned by Create when a memo with the same file name already exists.
+	ErrExists = internalmemo.ErrExists
+	// ErrInvalidExtension is returned by Create when the extension contains unsupported characters.
+	ErrInvalidExtension = internalmemo.ErrInvalidExtension
+	// ErrInvalidName is returned by Create when the resulting file name is not portable across platforms.
+	ErrInvalidName = internalmemo.ErrInvalidFileName
+	// ErrNotFound is returned when a reference does not match any memo.
+	ErrNotFound = internalmemo.ErrNotFound
+)
+
+type (
+	// Memo describes a memo file. It is the same document printed by `memo --format json`.
+	Memo = schema.Memo
+	// Match is a line of a memo matching a search pattern.
+	Match = schema.Match
+	// FS is the storage backend of a Store: the io/fs read interfaces plus the writes a Store performs.
+	// Implement it to keep memos somewhere other than a local directory.
+	FS = memofs.FS
+)
+
+// Store creates, lists, reads, searches and deletes memos under a root directory.
+// A Store is safe for concurrent use.
+type Store struct {
+	config  *config.Config
+	fs      FS
+	creator *internalmemo.Creator
+	keyring *crypt.Keyring
+}
+
+type options struct {
+	config config.Config
+	fs     FS
+}
+
+// Option configures a Store.
+type Option func(*options)
+
+// WithFileMode sets the permission of new memo files. The default is 0600.
+func WithFileMode(mode fs.FileMode) Option {
+	return func(o *options) {
+		o.config.FileMode = mode.Perm()
+	}
+}
+
+// WithDirMode sets the permission of new date directories. The default is 0700.
+func WithDirMode(mode fs.FileMode) Option {
+	return func(o *options) {
+		o.config.DirMode = mode.Perm()
+	}
+}
+
+// WithRecipients sets the age public keys (age1...) that encrypted memos are encrypted to.
+func WithRecipients(recipients ...string) Option {
+	return func(o *options) {
+		o.config.Encryption.Recipients = append(o.config.Encryption.Recipients, recipients...)
+	}
+}
+
+// WithIdentityFile sets the age identity file used to decrypt encrypted memos.
+func WithIdentityFile(path string) Option {
+	return func(o *options) {
+		o.config.Encryption.IdentityFile = path
+	}
+}
+
+// WithFS stores memos in fsys instead of the local directory passed to NewStore.
+// Paths reported by the Store are then relative to fsys.Root().
+func WithFS(fsys FS) Option {
+	return func(o *options) {
+		o.fs = fsys
+	}
+}
+
+// NewMemFS returns an empty in-memory FS, handy for hermetic tests of code using a Store.
+// root is only used to build the paths reported by the Store.
+func NewMemFS(root string) FS {
+	return memofs.NewMem(root)
+}
+
+// NewStore creates a Store rooted at baseDir. The directory is created on the first Create.
+func NewStore(baseDir string, opts ...Option) (*Store, error) {
+	abs, err := filepath.Abs(baseDir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve base directory: %w", err)
+	}
+
+	o := options{
+		config: config.Config{
+			BaseDir:  abs,
+			FileMode: config.DefaultFileMode,
+			DirMode:  config.DefaultDirMode,
+		},
+	}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	if o.fs == nil {
+		o.fs = memofs.NewOS(abs)
+	}
+
+	cfg := &o.config
+	return &Store{
+		config:  cfg,
+		fs:      o.fs,
+		creator: internalmemo.NewWithFS(cfg, o.fs),
+		keyring: crypt.NewKeyring(cfg.Encryption),
+	}, nil
+}
+
+// BaseDir returns the absolute root directory of the store.
+func (s *Store) BaseDir() string {
+	return s.fs.Root()
+}
+
+// CreateOptions customizes Store.Create.
+type CreateOptions struct {
+	// Ext is the file extension without the leading dot. Empty means "md".
+	Ext string
+	// Content is written to the new memo.
+	Content []byte
+	// Encrypt encrypts the memo with age to the recipients given by WithRecipients.
+	Encrypt bool
+}
+
+// Create creates a new memo in today's date directory.
+// The file name is the current time (HH-MM-SS), followed by name if it is not empty.
+func (s *Store) Create(name string, opts CreateOptions) (Memo, error) {
+	createOpts := internalmemo.CreateOptions{Content: opts.Content}
+	if opts.Encrypt {
+		recipients, err := s.keyring.Recipients()
+		if err != nil {
+			return Memo{}, err
+		}
+		createOpts.Recipients = recipients
+	}
+
+	path, err := s.creator.CreateWith(name, opts.Ext, createOpts)
+	if err != nil {
+		return Memo{}, err
+	}
+
+	entry, err := internalmemo.NewEntry(s.fs, path)
+	if err != nil {
+		return Memo{}, err
+	}
+
+	return entry.Schema(), nil
+}
+
+// List returns every memo in the store, newest first.
+func (s *Store) List() ([]Memo, error) {
+	entries, err := internalmemo.List(s.fs)
+	if err != nil {
+		return nil, err
+	}
+
+	memos := make([]Memo, 0, len(entries))
+	for _, entry := range entries {
+		memos = append(memos, entry.Schema())
+	}
+
+	return memos, nil
+}
+
+// Get returns the memo referred to by ref, which is LatestRef, a path
+// (absolute or relative to the store root), or a memo name.
+// When several memos share a name, the newest one is returned.
+func (s *Store) Get(ref string) (Memo, error) {
+	entry, err := internalmemo.Resolve(s.fs, ref)
+	if err != nil {
+		return Memo{}, err
+	}
+
+	return entry.Schema(), nil
+}
+
+// Read returns the content of the memo referred to by ref, decrypting it if needed.
+func (s *Store) Read(ref string) ([]byte, error) {
+	entry, err := internalmemo.Resolve(s.fs, ref)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.keyring.ReadFile(s.fs, entry.RelPath)
+}
+
+// Search returns the lines matching re across all memos, newest memo first.
+// Encrypted memos are searched only when an identity file is configured; an identity file that cannot be
+// read is an error.
+func (s *Store) Search(re *regexp.Regexp) ([]Match, error) {
+	entries, err := internalmemo.List(s.fs)
+	if err != nil {
+		return nil, err
+	}
+
+	matches, err := internalmemo.Search(entries, re, func(entry internalmemo.Entry) ([]byte, error) {
+		content, readErr := s.keyring.ReadFile(s.fs, entry.RelPath)
+		if errors.Is(readErr, crypt.ErrNoIdentity) {
+			return nil, nil
+		}
+		return content, readErr
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]Match, 0, len(matches))
+	for _, m := range matches {
+		result = append(result, Match{Memo: m.Entry.Schema(), Line: m.Line, Text: m.Text})
+	}
+
+	return result, nil
+}
+
+// Delete removes the memo referred to by ref.
+func (s *Store) Delete(ref string) error {
+	entry, err := internalmemo.Resolve(s.fs, ref)
+	if err != nil {
+		return err
+	}
+
+	return internalmemo.Delete(s.fs, entry)
+}
@@ -0,0 +1,182 @@
+package memo_test
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"runtime"
+	"testing"
+
+	"filippo.io/age"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/sushichan044/memo-cli/memo"
+)
+
+func newStore(t *testing.T, opts ...memo.Option) *memo.Store {
+	t.Helper()
+
+	store, err := memo.NewStore(t.TempDir(), opts...)
+	require.NoError(t, err)
+
+	return store
+}
+
+func TestStore_CreateGetRead(t *testing.T) {
+	store := newStore(t)
+
+	created, err := store.Create("standup", memo.CreateOptions{Content: []byte("- shipped\n")})
+	require.NoError(t, err)
+	assert.Equal(t, "standup", created.Name)
+	assert.Equal(t, "md", created.Ext)
+	assert.Equal(t, filepath.Join(store.BaseDir(), filepath.FromSlash(created.RelPath)), created.Path)
+
+	got, err := store.Get("standup")
+	require.NoError(t, err)
+	assert.Equal(t, created, got)
+
+	latest, err := store.Get(memo.LatestRef)
+	require.NoError(t, err)
+	assert.Equal(t, created.Path, latest.Path)
+
+	content, err := store.Read("standup")
+	require.NoError(t, err)
+	assert.Equal(t, "- shipped\n", string(content))
+}
+
+func TestStore_ListSearchDelete(t *testing.T) {
+	store := newStore(t)
+
+	_, err := store.Create("a", memo.CreateOptions{Content: []byte("alpha\nTODO: review\n")})
+	require.NoError(t, err)
+	_, err = store.Create("b", memo.CreateOptions{Content: []byte("beta\n"), Ext: "txt"})
+	require.NoError(t, err)
+
+	memos, err := store.List()
+	require.NoError(t, err)
+	assert.Len(t, memos, 2)
+
+	matches, err := store.Search(regexp.MustCompile("TODO"))
+	require.NoError(t, err)
+	require.Len(t, matches, 1)
+	assert.Equal(t, "a", matches[0].Name)
+	assert.Equal(t, 2, matches[0].Line)
+
+	require.NoError(t, store.Delete("a"))
+	_, err = store.Get("a")
+	require.ErrorIs(t, err, memo.ErrNotFound)
+	require.ErrorIs(t, store.Delete("a"), memo.ErrNotFound)
+}
+
+func TestStore_TypedErrors(t *testing.T) {
+	store := newStore(t)
+
+	_, err := store.Create("x", memo.CreateOptions{Ext: "md;rm"})
+	require.ErrorIs(t, err, memo.ErrInvalidExtension)
+
+	_, err = store.Get("missing")
+	require.ErrorIs(t, err, memo.ErrNotFound)
+}
+
+func TestStore_Options(t *testing.T) {
+	store := newStore(t, memo.WithFileMode(0o640), memo.WithDirMode(0o750))
+
+	created, err := store.Create("", memo.CreateOptions{})
+	require.NoError(t, err)
+
+	info, err := os.Stat(created.Path)
+	require.NoError(t, err)
+	if runtime.GOOS != "windows" {
+		assert.Equal(t, os.FileMode(0o640), info.Mode().Perm())
+	}
+}
+
+func TestStore_Encrypted(t *testing.T) {
+	identity, err := age.GenerateX25519Identity()
+	require.NoError(t, err)
+
+	identityFile := filepath.Join(t.TempDir(), "identity.txt")
+	require.NoError(t, os.WriteFile(identityFile, []byte(identity.String()), 0o600))
+
+	store := newStore(t,
+		memo.WithRecipients(identity.Recipient().String()),
+		memo.WithIdentityFile(identityFile),
+	)
+
+	created, err := store.Create("secret", memo.CreateOptions{Content: []byte("rotate keys"), Encrypt: true})
+	require.NoError(t, err)
+	assert.True(t, created.Encrypted)
+
+	content, err := store.Read("secret")
+	require.NoError(t, err)
+	assert.Equal(t, "rotate keys", string(content))
+
+	matches, err := store.Search(regexp.MustCompile("rotate"))
+	require.NoError(t, err)
+	assert.Len(t, matches, 1)
+}
+
+func TestStore_SearchEncryptedWithoutIdentity(t *testing.T) {
+	identity, err := age.GenerateX25519Identity()
+	require.NoError(t, err)
+
+	dir := t.TempDir()
+	writer, err := memo.NewStore(dir, memo.WithRecipients(identity.Recipient().String()))
+	require.NoError(t, err)
+	_, err = writer.Create("secret", memo.CreateOptions{Content: []byte("rotate keys"), Encrypt: true})
+	require.NoError(t, err)
+	_, err = writer.Create("plain", memo.CreateOptions{Content: []byte("rotate tyres")})
+	require.NoError(t, err)
+
+	// Without an identity, encrypted memos are skipped.
+	matches, err := writer.Search(regexp.MustCompile("rotate"))
+	require.NoError(t, err)
+	require.Len(t, matches, 1)
+	assert.Equal(t, "plain", matches[0].Name)
+
+	// A broken identity file is reported instead of silently searching fewer memos.
+	identityFile := filepath.Join(t.TempDir(), "identity.txt")
+	require.NoError(t, os.WriteFile(identityFile, []byte("not an age identity"), 0o600))
+	broken, err := memo.NewStore(dir, memo.WithIdentityFile(identityFile))
+	require.NoError(t, err)
+	_, err = broken.Search(regexp.MustCompile("rotate"))
+	require.Error(t, err)
+}
+
+func ExampleStore() {
+	dir, err := os.MkdirTemp("", "memo-example-")
+	if err != nil {
+		panic(err)
+	}
+	defer os.RemoveAll(dir)
+
+	store, err := memo.NewStore(dir)
+	if err != nil {
+		panic(err)
+	}
+
+	created, err := store.Create("release-notes", memo.CreateOptions{Content: []byte("v1.0.0\n")})
+	if err != nil {
+		panic(err)
+	}
+
+	fmt.Println(created.Name, created.Ext)
+	// Output: release-notes md
+}
+
+func TestStore_WithMemFS(t *testing.T) {
+	fsys := memo.NewMemFS("/virtual")
+	store, err := memo.NewStore(t.TempDir(), memo.WithFS(fsys))
+	require.NoError(t, err)
+
+	created, err := store.Create("hermetic", memo.CreateOptions{Content: []byte("in memory")})
+	require.NoError(t, err)
+	assert.Equal(t, filepath.FromSlash("/virtual"), store.BaseDir())
+	assert.Equal(t, filepath.Join(filepath.FromSlash("/virtual"), filepath.FromSlash(created.RelPath)), created.Path)
+
+	data, err := fsys.ReadFile(created.RelPath)
+	require.NoError(t, err)
+	assert.Equal(t, "in memory", string(data))
+}
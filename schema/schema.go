@@ -0,0 +1,350 @@
+// Package schema defines the JSON documents printed by `memo --format json`.
+//
+// These types are the stable, machine-readable interface of the CLI.
+// Commands that print a single result emit one JSON object; commands that print
+// a collection (such as grep) emit NDJSON, one object per line.
+// Fields may be added in future versions, but existing fields are never renamed,
+// removed, or changed in meaning.
+package schema
+
+import "time"
+
+// Memo describes a memo file.
+type Memo struct {
+	// Path is the absolute path to the memo file.
+	Path string `json:"path"`
+	// RelPath is the slash-separated path relative to the memo root (e.g. "20251031/14-30-45-notes.md").
+	RelPath string `json:"rel_path"`
+	// DateDir is the YYYYMMDD directory the memo lives in.
+	DateDir string `json:"date_dir"`
+	// Name is the user-provided part of the file name, or empty for timestamp-only memos.
+	Name string `json:"name"`
+	// Ext is the memo extension without the leading dot and without the encryption suffix.
+	Ext string `json:"ext"`
+	// Encrypted reports whether the memo is encrypted with age.
+	Encrypted bool `json:"encrypted"`
+	// CreatedAt is derived from the date directory and the HH-MM-SS file name prefix.
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// Created is printed by `memo new`.
+type Created struct {
+	Memo
+
+	// GitignoreOK is false when the memo root is not ignored by the enclosing git repository.
+	GitignoreOK bool `json:"gitignore_ok"`
+}
+
+// Content is printed by `memo show`.
+type Content struct {
+	Memo
+
+	// Content is the plaintext content of the memo, decrypted if needed.
+	Content string `json:"content"`
+}
+
+// Edited is printed by `memo edit`.
+type Edited struct {
+	Memo
+
+	// Changed reports whether the content was modified in the editor.
+	Changed bool `json:"changed"`
+}
+
+// Daily is printed by `memo today`, `memo yesterday` and `memo day`.
+type Daily struct {
+	Memo
+
+	// Created is false when the daily note already existed.
+	Created bool `json:"created"`
+	// Carried is the number of unchecked tasks copied from the previous daily note.
+	Carried int `json:"carried"`
+}
+
+// Match is printed by `memo grep`, one per matching line.
+type Match struct {
+	Memo
+
+	// Line is the 1-based line number of the match.
+	Line int `json:"line"`
+	// Text is the matching line without its line terminator.
+	Text string `json:"text"`
+}
+
+// Event is printed by `memo watch`, one per change to a memo.
+type Event struct {
+	Memo
+
+	// Type is "created", "modified" or "deleted".
+	Type string `json:"type"`
+	// Time is when the change was noticed.
+	Time time.Time `json:"time"`
+}
+
+// Hook is passed on stdin to the hooks configured in the [hooks] section of the config file.
+type Hook struct {
+	Memo
+
+	// Hook is the name of the hook: "pre-create", "post-create", "post-edit" or "pre-delete".
+	Hook string `json:"hook"`
+}
+
+// Renamed is printed by `memo mv`.
+type Renamed struct {
+	Memo
+
+	// From is the slash-separated path of the memo before it was renamed.
+	From string `json:"from"`
+}
+
+// Init is printed by `memo init`.
+type Init struct {
+	// BaseDir is the absolute path of the memo root.
+	BaseDir string `json:"base_dir"`
+	// History reports whether the memo root is a history repository.
+	History bool `json:"history"`
+}
+
+// Revision is printed by `memo log`, one per commit that changed the memo.
+type Revision struct {
+	// Hash is the full commit hash, usable as a revision in `memo diff` and `memo revert`.
+	Hash string `json:"hash"`
+	// Date is the commit date.
+	Date time.Time `json:"date"`
+	// Subject describes the change (e.g. "edit 20251031/14-30-45-notes.md").
+	Subject string `json:"subject"`
+	// RelPath is the path of the memo in this revision, which differs from the current one after `memo mv`.
+	RelPath string `json:"rel_path"`
+}
+
+// Diff is printed by `memo diff`.
+type Diff struct {
+	Memo
+
+	// Rev is the revision compared against, or empty for the most recent change.
+	Rev string `json:"rev"`
+	// Patch is the unified diff.
+	Patch string `json:"patch"`
+}
+
+// Reverted is printed by `memo revert`.
+type Reverted struct {
+	Memo
+
+	// Rev is the revision the memo was restored to.
+	Rev string `json:"rev"`
+}
+
+// Backup is printed by `memo backup`.
+type Backup struct {
+	// Path is the absolute path of the archive.
+	Path string `json:"path"`
+	// Files is the number of files archived.
+	Files int `json:"files"`
+	// Bytes is the total size of the archived files before compression.
+	Bytes int64 `json:"bytes"`
+}
+
+// Verified is printed by `memo backup verify`.
+type Verified struct {
+	// Path is the path of the archive.
+	Path string `json:"path"`
+	// CreatedAt is when the backup was taken, according to its manifest.
+	CreatedAt time.Time `json:"created_at"`
+	// Files is the number of files listed in the manifest.
+	Files int `json:"files"`
+	// Problems lists differences between the archive and its manifest; empty means the archive is intact.
+	Problems []string `json:"problems"`
+}
+
+// Restored is printed by `memo restore`. Paths are relative to the memo root.
+type Restored struct {
+	// Restored are files that were missing and have been written.
+	Restored []string `json:"restored"`
+	// Unchanged are files that already had the archived content.
+	Unchanged []string `json:"unchanged"`
+	// Conflicts are files whose content differs from the archive; they are overwritten only with --force.
+	Conflicts []string `json:"conflicts"`
+	// DryRun reports that nothing was written because of --dry-run.
+	DryRun bool `json:"dry_run"`
+	// Aborted reports that nothing was written because of conflicts, which need --force to be overwritten.
+	Aborted bool `json:"aborted"`
+}
+
+// Task is printed by `memo tasks`, one per task list item, and by `memo tasks done`.
+type Task struct {
+	Memo
+
+	// ID identifies the task in `memo tasks done`.
+	ID string `json:"id"`
+	// Line is the 1-based line number of the task in the memo.
+	Line int `json:"line"`
+	// Done reports whether the checkbox is checked.
+	Done bool `json:"done"`
+	// Text is the task text after the checkbox.
+	Text string `json:"text"`
+	// Due is the @due(YYYY-MM-DD) date of the task, if any.
+	Due string `json:"due,omitempty"`
+	// Tags are the tags of the task and of its memo.
+	Tags []string `json:"tags"`
+}
+
+// Link is printed by `memo links`, `memo backlinks` and `memo check-links`, one object per [[wiki-link]].
+// The embedded Memo is the memo containing the link.
+type Link struct {
+	Memo
+
+	// Line is the 1-based line number of the link.
+	Line int `json:"line"`
+	// Target is the link target as written between the brackets, without heading or alias.
+	Target string `json:"target"`
+	// Text is the line containing the link.
+	Text string `json:"text"`
+	// To is the memo the link resolves to, or null when the link is broken.
+	To *Memo `json:"to"`
+}
+
+// Graph is printed by `memo graph --format json`.
+type Graph struct {
+	Nodes []GraphNode `json:"nodes"`
+	Edges []GraphEdge `json:"edges"`
+}
+
+// GraphNode is a memo of a Graph.
+type GraphNode struct {
+	Memo
+
+	// Tags are the tags of the memo.
+	Tags []string `json:"tags"`
+}
+
+// GraphEdge joins two memos of a Graph, identified by their rel_path.
+type GraphEdge struct {
+	From string `json:"from"`
+	To   string `json:"to"`
+	// Kind is "link" for a [[wiki-link]] from one memo to the other,
+	// or "tag" for memos sharing tags, in which case the edge has no direction.
+	Kind string `json:"kind"`
+	// Tags are the shared tags of a "tag" edge.
+	Tags []string `json:"tags,omitempty"`
+}
+
+// Site is printed by `memo export html`.
+type Site struct {
+	// Path is the directory the site was written to.
+	Path string `json:"path"`
+	// Memos is the number of exported memos.
+	Memos int `json:"memos"`
+	// Tags is the number of tag pages.
+	Tags int `json:"tags"`
+}
+
+// Exported is printed by `memo export --output FILE`; without --output, the archive itself is printed.
+type Exported struct {
+	// Path is the archive written.
+	Path string `json:"path"`
+	// Format is the archive format: "jsonl", "zip" or "tar".
+	Format string `json:"format"`
+	// Memos is the number of exported memos.
+	Memos int `json:"memos"`
+}
+
+// ExportedMemo is a line of `memo export --archive-format jsonl`, read back by `memo import`.
+type ExportedMemo struct {
+	// RelPath is the slash-separated path relative to the memo root (e.g. "20251031/14-30-45-notes.md").
+	RelPath string `json:"rel_path"`
+	// CreatedAt is derived from the date directory and the HH-MM-SS file name prefix.
+	CreatedAt time.Time `json:"created_at"`
+	// ModTime is the modification time of the memo file.
+	ModTime time.Time `json:"mtime"`
+	// Encrypted reports whether Content is an age-encrypted memo.
+	Encrypted bool `json:"encrypted"`
+	// Tags are the tags of the memo. They are empty for encrypted memos.
+	Tags []string `json:"tags"`
+	// SHA256 is the hex-encoded SHA-256 hash of the file content.
+	SHA256 string `json:"sha256"`
+	// Encoding is "utf-8" when Content is the file content itself,
+	// or "base64" for content that is not valid UTF-8, such as binary encrypted memos.
+	Encoding string `json:"encoding"`
+	// Content is the file content, including front matter.
+	Content string `json:"content"`
+}
+
+// Imported is printed by `memo import`.
+type Imported struct {
+	// Imported are the rel_paths of the memos imported as is.
+	Imported []string `json:"imported"`
+	// Renamed are memos imported under another name because theirs was taken.
+	Renamed []ImportRename `json:"renamed"`
+	// Skipped are memos that were not imported.
+	Skipped []ImportSkip `json:"skipped"`
+	// DryRun reports that nothing was written.
+	DryRun bool `json:"dry_run"`
+	// Sources map memos to the notes they are imported from, when importing the notes of another tool.
+	Sources []ImportSource `json:"sources,omitempty"`
+}
+
+// ImportSource is a note imported by `memo import --from`.
+type ImportSource struct {
+	// RelPath is where the note is imported, before renaming.
+	RelPath string `json:"rel_path"`
+	// Source is the path of the note in the source, with a "#N" suffix for the N-th entry of a journal.
+	Source string `json:"source"`
+	// TimeSource tells where the creation time comes from: "front matter", "file name", "entry" or "mtime".
+	TimeSource string `json:"time_source"`
+}
+
+// ImportRename is a memo renamed by `memo import`.
+type ImportRename struct {
+	From string `json:"from"`
+	To   string `json:"to"`
+}
+
+// ImportSkip is a memo skipped by `memo import`, such as a duplicate of an existing memo.
+type ImportSkip struct {
+	RelPath string `json:"rel_path"`
+	Reason  string `json:"reason"`
+}
+
+// PermissionIssue describes a path whose permission is looser than configured.
+type PermissionIssue struct {
+	Path string `json:"path"`
+	// Mode and Want are octal strings such as "0644".
+	Mode  string `json:"mode"`
+	Want  string `json:"want"`
+	IsDir bool   `json:"is_dir"`
+}
+
+// Doctor is printed by `memo doctor`.
+type Doctor struct {
+	// GitignoreOK is false when the memo root is not ignored by the enclosing git repository.
+	GitignoreOK bool `json:"gitignore_ok"`
+	// RootPrivate is false when the memo root is readable by group or others.
+	RootPrivate bool `json:"root_private"`
+	// PermissionIssues lists paths with looser permissions than configured.
+	PermissionIssues []PermissionIssue `json:"permission_issues"`
+	// Fixed reports whether PermissionIssues were tightened by --fix-perms.
+	Fixed bool `json:"fixed"`
+}
+
+// Serving is printed by `memo serve` once it listens.
+type Serving struct {
+	// URL opens the web UI, authenticated with the token.
+	URL string `json:"url"`
+	// Token authenticates API requests, sent as "Authorization: Bearer <token>".
+	Token string `json:"token"`
+}
+
+// Alias is printed by `memo alias list` for each alias of the [alias] section of the config file.
+type Alias struct {
+	Name string `json:"name"`
+	// Command is the command line the alias stands for, e.g. "new --ext md bug-report".
+	Command string `json:"command"`
+	// Shadowed is true when a built-in command has the same name, which always wins over the alias.
+	Shadowed bool `json:"shadowed"`
+}
+
+// Error is printed to stderr when a command fails in JSON mode.
+type Error struct {
+	Error string `json:"error"`
+}
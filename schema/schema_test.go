@@ -0,0 +1,52 @@
+package schema_test
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/sushichan044/memo-cli/schema"
+)
+
+// The JSON field names are a public interface; this test guards against accidental renames.
+func TestCreated_JSON(t *testing.T) {
+	created := schema.Created{
+		Memo: schema.Memo{
+			Path:      "/memo/20251031/14-30-45-notes.md",
+			RelPath:   "20251031/14-30-45-notes.md",
+			DateDir:   "20251031",
+			Name:      "notes",
+			Ext:       "md",
+			CreatedAt: time.Date(2025, 10, 31, 14, 30, 45, 0, time.UTC),
+		},
+		GitignoreOK: true,
+	}
+
+	data, err := json.Marshal(created)
+	require.NoError(t, err)
+
+	assert.JSONEq(t, `{
+		"path": "/memo/20251031/14-30-45-notes.md",
+		"rel_path": "20251031/14-30-45-notes.md",
+		"date_dir": "20251031",
+		"name": "notes",
+		"ext": "md",
+		"encrypted": false,
+		"created_at": "2025-10-31T14:30:45Z",
+		"gitignore_ok": true
+	}`, string(data))
+}
+
+func TestMatch_JSON(t *testing.T) {
+	data, err := json.Marshal(schema.Match{Memo: schema.Memo{Path: "/memo/a.md"}, Line: 3, Text: "TODO"})
+	require.NoError(t, err)
+
+	var fields map[string]any
+	require.NoError(t, json.Unmarshal(data, &fields))
+	assert.Equal(t, "/memo/a.md", fields["path"])
+	assert.InDelta(t, 3, fields["line"], 0)
+	assert.Equal(t, "TODO", fields["text"])
+}